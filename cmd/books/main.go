@@ -1,32 +1,147 @@
 package main
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
+	"os"
 	"regexp"
+	"time"
 
+	"connectrpc.com/connect"
 	"github.com/iho/bookstore/internal/books"
+	"github.com/iho/bookstore/internal/caller"
+	"github.com/iho/bookstore/internal/cart"
+	"github.com/iho/bookstore/internal/catalog"
+	"github.com/iho/bookstore/internal/clients"
+	"github.com/iho/bookstore/internal/events"
+	"github.com/iho/bookstore/internal/idempotency"
+	"github.com/iho/bookstore/internal/logging"
+	"github.com/iho/bookstore/internal/metrics"
+	"github.com/iho/bookstore/internal/money"
+	"github.com/iho/bookstore/internal/ratelimit"
+	"github.com/iho/bookstore/internal/redisclient"
+	"github.com/iho/bookstore/internal/telemetry"
+	"github.com/iho/bookstore/internal/tenant"
+	"github.com/iho/bookstore/protos/gen/authors/v1/authorsv1connect"
 	"github.com/iho/bookstore/protos/gen/books/v1/booksv1connect"
+	"github.com/iho/bookstore/protos/gen/cart/v1/cartv1connect"
+	"github.com/iho/bookstore/protos/gen/catalog/v1/catalogv1connect"
+	"github.com/iho/bookstore/protos/gen/orders/v1/ordersv1connect"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/redis/go-redis/extra/redisotel/v9"
 	redis "github.com/redis/go-redis/v9"
 	"golang.org/x/net/http2"
 	"golang.org/x/net/http2/h2c"
 )
 
-func main() {
-	rdb := redis.NewClient(&redis.Options{
-		Addr:     "redis:6379",
-		Password: "", // no password set
-		DB:       0,  // use default DB
-	})
-	booksService := books.NewBooksService(rdb)
+// cartTTL is how long carts live after their last change, CART_TTL or a day.
+func cartTTL() (time.Duration, error) {
+	value := os.Getenv("CART_TTL")
+	if value == "" {
+		return 24 * time.Hour, nil
+	}
+	ttl, err := time.ParseDuration(value)
+	if err != nil || ttl <= 0 {
+		return 0, fmt.Errorf("invalid CART_TTL: %q", value)
+	}
+	return ttl, nil
+}
+
+func run() error {
+	shutdownTracing, err := telemetry.Setup(context.Background(), "books")
+	if err != nil {
+		return err
+	}
+	defer shutdownTracing(context.Background())
+
+	redisOpts, err := redisclient.OptionsFromEnv("REDIS", "redis:6379")
+	if err != nil {
+		return err
+	}
+	rdb := redis.NewUniversalClient(redisOpts)
+	if err := redisotel.InstrumentTracing(rdb); err != nil {
+		return err
+	}
+	keys := books.NewKeys(os.Getenv("REDIS_KEY_PREFIX"))
+	idem, err := idempotency.FromEnv()
+	if err != nil {
+		return err
+	}
+	currency, err := money.CurrencyFromEnv()
+	if err != nil {
+		return err
+	}
+	rates, err := money.RatesFromEnv(currency)
+	if err != nil {
+		return err
+	}
+	booksService := books.NewBooksService(rdb, keys, idem, currency)
+	if err := booksService.EnsureCounts(context.Background()); err != nil {
+		return err
+	}
+	legacyTenant, err := tenant.LegacyFromEnv()
+	if err != nil {
+		return err
+	}
+	if err := booksService.MigrateLegacyKeys(context.Background(), legacyTenant); err != nil {
+		return err
+	}
+
+	broker, err := events.NewBrokerFromEnv()
+	if err != nil {
+		return err
+	}
+	// the relay polls all the time, keep it on a client without tracing
+	relay := events.NewRelay(books.NewOutboxStore(redis.NewUniversalClient(redisOpts), keys), broker)
+	go relay.Run(context.Background())
+
+	authors, err := clients.FromEnv("AUTHORS", "http://authors:8080")
+	if err != nil {
+		return err
+	}
+	orders, err := clients.FromEnv("ORDERS", "http://orders:9999")
+	if err != nil {
+		return err
+	}
+	ttl, err := cartTTL()
+	if err != nil {
+		return err
+	}
+	internalToken, err := caller.TokenFromEnv()
+	if err != nil {
+		return err
+	}
+	rpcMetrics := metrics.NewInterceptor()
+	rpcInterceptors := []connect.Interceptor{
+		telemetry.NewInterceptor(),
+		logging.NewInterceptor(slog.Default(), logging.AccessLogEnabled(), logging.LogBodiesEnabled()),
+		rpcMetrics,
+		caller.NewInterceptor(internalToken),
+		tenant.NewInterceptor(),
+	}
+	factory := clients.NewFactory(rpcInterceptors...)
+	limiter, err := ratelimit.FromEnv()
+	if err != nil {
+		return err
+	}
+	if limiter != nil {
+		rpcInterceptors = append(rpcInterceptors, ratelimit.NewInterceptor(limiter))
+	}
+	interceptors := connect.WithInterceptors(rpcInterceptors...)
+	authorsClient := clients.New(factory, authors, authorsv1connect.NewAuthorsServiceClient)
+	catalogService := catalog.NewCatalogService(authorsClient, booksService)
+	ordersClient := clients.New(factory, orders, ordersv1connect.NewOrdersServiceClient)
+	cartService := cart.NewCartService(rdb, os.Getenv("REDIS_KEY_PREFIX"), ttl, booksService, ordersClient, idem, currency, rates)
 
 	mux := http.NewServeMux()
-	mux.Handle(booksv1connect.NewBooksServiceHandler(booksService))
-	fmt.Println("Starting server on :9090")
+	mux.Handle(booksv1connect.NewBooksServiceHandler(booksService, interceptors))
+	mux.Handle(catalogv1connect.NewCatalogServiceHandler(catalogService, interceptors))
+	mux.Handle(cartv1connect.NewCartServiceHandler(cartService, interceptors))
+	slog.Info("starting server", "addr", ":9090")
 
 	reg := prometheus.NewRegistry()
 
@@ -35,6 +150,10 @@ func main() {
 	reg.MustRegister(collectors.NewGoCollector(
 		collectors.WithGoCollectorRuntimeMetrics(collectors.GoRuntimeMetricsRule{Matcher: regexp.MustCompile("/.*")}),
 	))
+	clients.RegisterMetrics(reg)
+	reg.MustRegister(rpcMetrics)
+	ratelimit.RegisterMetrics(reg)
+	reg.MustRegister(books.NewCatalogCollector(booksService))
 
 	// Expose the registered metrics via HTTP.
 	mux.Handle("/metrics", promhttp.HandlerFor(
@@ -45,7 +164,13 @@ func main() {
 		},
 	))
 
-	if err := http.ListenAndServe(":9090", h2c.NewHandler(mux, &http2.Server{})); err != nil {
-		log.Fatal(err)
+	return http.ListenAndServe(":9090", h2c.NewHandler(mux, &http2.Server{}))
+}
+
+func main() {
+	slog.SetDefault(logging.New("books"))
+	if err := run(); err != nil {
+		slog.Error("server stopped", "error", err)
+		os.Exit(1)
 	}
 }
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+
+	"connectrpc.com/connect"
+	"github.com/iho/bookstore/internal/caller"
+	"github.com/iho/bookstore/internal/catalog"
+	"github.com/iho/bookstore/internal/tenant"
+	v1 "github.com/iho/bookstore/protos/gen/catalog/v1"
+	"github.com/iho/bookstore/protos/gen/catalog/v1/catalogv1connect"
+)
+
+const (
+	defaultServerAddr = "http://localhost:9090/"
+	usage             = `usage: bookstore-admin <command> [flags]
+
+commands:
+  import [-addr url] [-tenant id] [-format csv|jsonl] [-dry-run] <file|->
+  export [-addr url] [-tenant id] [-format csv|jsonl] [-o file]
+
+The catalog service only accepts calls carrying its internal token, read
+from $BOOKSTORE_TOKEN.
+`
+)
+
+func serverAddr() string {
+	if addr := os.Getenv("BOOKSTORE_CATALOG_URL"); addr != "" {
+		return addr
+	}
+	return defaultServerAddr
+}
+
+// newClient returns a catalog client at addr calling on behalf of tenantID
+// with the internal token in $BOOKSTORE_TOKEN.
+func newClient(addr, tenantID string) (catalogv1connect.CatalogServiceClient, context.Context) {
+	client := catalogv1connect.NewCatalogServiceClient(http.DefaultClient, addr, connect.WithInterceptors(
+		caller.NewInterceptor(os.Getenv("BOOKSTORE_TOKEN")),
+		tenant.NewInterceptor(),
+	))
+	return client, tenant.WithID(context.Background(), tenantID)
+}
+
+func importCatalog(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	addr := fs.String("addr", serverAddr(), "catalog service URL")
+	tenantID := fs.String("tenant", os.Getenv("BOOKSTORE_TENANT"), "tenant ID (default: $BOOKSTORE_TENANT)")
+	formatName := fs.String("format", "", "input format: csv or jsonl (default: from file extension)")
+	dryRun := fs.Bool("dry-run", false, "validate rows without creating anything")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return errors.New("import needs exactly one file argument, use - for stdin")
+	}
+	path := fs.Arg(0)
+
+	format, err := catalog.ParseFormat(*formatName, path)
+	if err != nil {
+		return err
+	}
+
+	var in io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	reader, err := catalog.NewReader(format, in)
+	if err != nil {
+		return err
+	}
+
+	client, ctx := newClient(*addr, *tenantID)
+	stream := client.ImportCatalog(ctx)
+
+	var rowErrors []*v1.RowError
+	for {
+		record, row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		var rowErr *catalog.RowError
+		if errors.As(err, &rowErr) {
+			rowErrors = append(rowErrors, &v1.RowError{Row: rowErr.Row, Message: rowErr.Err.Error()})
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read input: %w", err)
+		}
+
+		err = stream.Send(&v1.ImportCatalogRequest{
+			DryRun: *dryRun,
+			Row:    row,
+			Record: record,
+		})
+		if err != nil {
+			// the real error is reported by CloseAndReceive
+			break
+		}
+	}
+
+	res, err := stream.CloseAndReceive()
+	if err != nil {
+		return fmt.Errorf("failed to import catalog: %w", err)
+	}
+
+	rowErrors = append(rowErrors, res.Msg.GetErrors()...)
+	for _, rowErr := range rowErrors {
+		fmt.Fprintf(os.Stderr, "row %d: %s\n", rowErr.Row, rowErr.Message)
+	}
+
+	prefix := ""
+	if res.Msg.GetDryRun() {
+		prefix = "dry run: "
+	}
+	fmt.Printf("%s%d rows, %d authors created, %d books created, %d errors\n",
+		prefix, res.Msg.GetRows(), res.Msg.GetAuthorsCreated(), res.Msg.GetBooksCreated(), len(rowErrors))
+
+	if len(rowErrors) > 0 {
+		return errors.New("import finished with errors")
+	}
+	return nil
+}
+
+func exportCatalog(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	addr := fs.String("addr", serverAddr(), "catalog service URL")
+	tenantID := fs.String("tenant", os.Getenv("BOOKSTORE_TENANT"), "tenant ID (default: $BOOKSTORE_TENANT)")
+	formatName := fs.String("format", "", "output format: csv or jsonl (default: from -o extension, else jsonl)")
+	output := fs.String("o", "-", "output file, - for stdout")
+	fs.Parse(args)
+
+	if *formatName == "" && *output == "-" {
+		*formatName = string(catalog.FormatJSONL)
+	}
+	format, err := catalog.ParseFormat(*formatName, *output)
+	if err != nil {
+		return err
+	}
+
+	var out io.Writer = os.Stdout
+	if *output != "-" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	writer, err := catalog.NewWriter(format, out)
+	if err != nil {
+		return err
+	}
+
+	client, ctx := newClient(*addr, *tenantID)
+	stream, err := client.ExportCatalog(ctx, connect.NewRequest(&v1.ExportCatalogRequest{}))
+	if err != nil {
+		return fmt.Errorf("failed to export catalog: %w", err)
+	}
+	defer stream.Close()
+
+	for stream.Receive() {
+		if err := writer.Write(stream.Msg().GetRecord()); err != nil {
+			return fmt.Errorf("failed to write record: %w", err)
+		}
+	}
+	if err := stream.Err(); err != nil {
+		return fmt.Errorf("failed to export catalog: %w", err)
+	}
+
+	return writer.Flush()
+}
+
+func main() {
+	log.SetFlags(0)
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "import":
+		err = importCatalog(os.Args[2:])
+	case "export":
+		err = exportCatalog(os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		log.Fatal(err)
+	}
+}
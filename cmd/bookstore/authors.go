@@ -0,0 +1,125 @@
+package main
+
+import (
+	"connectrpc.com/connect"
+	v1 "github.com/iho/bookstore/protos/gen/authors/v1"
+	"github.com/iho/bookstore/protos/gen/authors/v1/authorsv1connect"
+	"github.com/spf13/cobra"
+)
+
+func newAuthorsCommand(opts *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "authors",
+		Short: "Manage authors",
+	}
+
+	client := func() authorsv1connect.AuthorsServiceClient {
+		return authorsv1connect.NewAuthorsServiceClient(opts.httpClient(), opts.authorsURL, opts.clientOptions()...)
+	}
+
+	authorsTable := func(authors ...*v1.Author) table {
+		tbl := table{header: []string{"ID", "NAME"}}
+		for _, author := range authors {
+			tbl.rows = append(tbl.rows, []string{author.Id, author.Name})
+		}
+		return tbl
+	}
+
+	var limit, offset int32
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List authors ordered by name",
+		Args:  exactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := opts.context(cmd)
+			defer cancel()
+
+			res, err := client().ListAuthors(ctx, connect.NewRequest(&v1.ListAuthorsRequest{
+				Limit:  limit,
+				Offset: offset,
+			}))
+			if err != nil {
+				return err
+			}
+			return printMessage(cmd.OutOrStdout(), opts.output, res.Msg, authorsTable(res.Msg.Authors...))
+		},
+	}
+	list.Flags().Int32Var(&limit, "limit", 100, "maximum number of authors to return")
+	list.Flags().Int32Var(&offset, "offset", 0, "number of authors to skip")
+
+	get := &cobra.Command{
+		Use:   "get <id>",
+		Short: "Get an author",
+		Args:  exactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := opts.context(cmd)
+			defer cancel()
+
+			res, err := client().GetAuthor(ctx, connect.NewRequest(&v1.GetAuthorRequest{
+				Id: args[0],
+			}))
+			if err != nil {
+				return err
+			}
+			return printMessage(cmd.OutOrStdout(), opts.output, res.Msg, authorsTable(res.Msg.Author))
+		},
+	}
+
+	create := &cobra.Command{
+		Use:   "create <name>",
+		Short: "Create an author",
+		Args:  exactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := opts.context(cmd)
+			defer cancel()
+
+			res, err := client().CreateAuthor(ctx, connect.NewRequest(&v1.CreateAuthorRequest{
+				Name: args[0],
+			}))
+			if err != nil {
+				return err
+			}
+			return printMessage(cmd.OutOrStdout(), opts.output, res.Msg, authorsTable(res.Msg.Author))
+		},
+	}
+
+	update := &cobra.Command{
+		Use:   "update <id> <name>",
+		Short: "Rename an author",
+		Args:  exactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := opts.context(cmd)
+			defer cancel()
+
+			res, err := client().UpdateAuthor(ctx, connect.NewRequest(&v1.UpdateAuthorRequest{
+				Id:   args[0],
+				Name: args[1],
+			}))
+			if err != nil {
+				return err
+			}
+			return printMessage(cmd.OutOrStdout(), opts.output, res.Msg, authorsTable(res.Msg.Author))
+		},
+	}
+
+	del := &cobra.Command{
+		Use:   "delete <id>",
+		Short: "Delete an author",
+		Args:  exactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := opts.context(cmd)
+			defer cancel()
+
+			res, err := client().DeleteAuthor(ctx, connect.NewRequest(&v1.DeleteAuthorRequest{
+				Id: args[0],
+			}))
+			if err != nil {
+				return err
+			}
+			return printMessage(cmd.OutOrStdout(), opts.output, res.Msg, statusTable(res.Msg.Status))
+		},
+	}
+
+	cmd.AddCommand(list, get, create, update, del)
+	return cmd
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/iho/bookstore/internal/books"
+	v1 "github.com/iho/bookstore/protos/gen/books/v1"
+	"github.com/iho/bookstore/protos/gen/books/v1/booksv1connect"
+	moneyv1 "github.com/iho/bookstore/protos/gen/money/v1"
+	"github.com/spf13/cobra"
+)
+
+func newBooksCommand(opts *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "books",
+		Short: "Manage books",
+	}
+
+	client := func() booksv1connect.BooksServiceClient {
+		return booksv1connect.NewBooksServiceClient(opts.httpClient(), opts.booksURL, opts.clientOptions()...)
+	}
+
+	booksTable := func(books ...*v1.Book) table {
+		tbl := table{header: []string{"ID", "TITLE", "AUTHOR ID", "PUBLISHED", "PRICE"}}
+		for _, book := range books {
+			tbl.rows = append(tbl.rows, []string{book.Id, book.Title, book.AuthorId, book.PublishedDate, formatMoney(book.Price)})
+		}
+		return tbl
+	}
+
+	list := &cobra.Command{
+		Use:   "list <id>...",
+		Short: "Get several books by ID",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if err := cobra.MinimumNArgs(1)(cmd, args); err != nil {
+				return usageError{err}
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := opts.context(cmd)
+			defer cancel()
+
+			res, err := client().ListBooks(ctx, connect.NewRequest(&v1.ListBooksRequest{
+				Ids: args,
+			}))
+			if err != nil {
+				return err
+			}
+			return printMessage(cmd.OutOrStdout(), opts.output, res.Msg, booksTable(res.Msg.Books...))
+		},
+	}
+
+	get := &cobra.Command{
+		Use:   "get <id>",
+		Short: "Get a book",
+		Args:  exactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := opts.context(cmd)
+			defer cancel()
+
+			res, err := client().GetBook(ctx, connect.NewRequest(&v1.GetBookRequest{
+				Id: args[0],
+			}))
+			if err != nil {
+				return err
+			}
+			return printMessage(cmd.OutOrStdout(), opts.output, res.Msg, booksTable(res.Msg.Book))
+		},
+	}
+
+	var title, authorID, publishedDate, currency string
+	var price int64
+	bookFlags := func(cmd *cobra.Command) {
+		cmd.Flags().StringVar(&title, "title", "", "book title")
+		cmd.Flags().StringVar(&authorID, "author-id", "", "author ID")
+		cmd.Flags().StringVar(&publishedDate, "published-date", time.Now().UTC().Format(books.JSONDateFormat), "publication date, e.g. 2024-06-12T18:37:04.189Z")
+		cmd.Flags().Int64Var(&price, "price", 0, "unit price in minor units, 0 when not for sale")
+		cmd.Flags().StringVar(&currency, "currency", "", "ISO 4217 currency of the price, defaults to the currency of the store")
+		cmd.MarkFlagRequired("title")
+		cmd.MarkFlagRequired("author-id")
+	}
+
+	create := &cobra.Command{
+		Use:   "create",
+		Short: "Create a book",
+		Args:  exactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := opts.context(cmd)
+			defer cancel()
+
+			res, err := client().CreateBook(ctx, connect.NewRequest(&v1.CreateBookRequest{
+				Title:         title,
+				AuthorId:      authorID,
+				PublishedDate: publishedDate,
+				Price:         &moneyv1.Money{Amount: price, Currency: currency},
+			}))
+			if err != nil {
+				return err
+			}
+			return printMessage(cmd.OutOrStdout(), opts.output, res.Msg, booksTable(res.Msg.Book))
+		},
+	}
+	bookFlags(create)
+
+	update := &cobra.Command{
+		Use:   "update <id>",
+		Short: "Replace a book",
+		Args:  exactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := opts.context(cmd)
+			defer cancel()
+
+			req := &v1.UpdateBookRequest{
+				Id:            args[0],
+				Title:         title,
+				AuthorId:      authorID,
+				PublishedDate: publishedDate,
+			}
+			// without --price or --currency the stored price is kept
+			if cmd.Flags().Changed("price") || cmd.Flags().Changed("currency") {
+				req.Price = &moneyv1.Money{Amount: price, Currency: currency}
+			}
+			res, err := client().UpdateBook(ctx, connect.NewRequest(req))
+			if err != nil {
+				return err
+			}
+			return printMessage(cmd.OutOrStdout(), opts.output, res.Msg, booksTable(res.Msg.Book))
+		},
+	}
+	bookFlags(update)
+	update.Flags().Lookup("price").Usage = "unit price in minor units, 0 when not for sale, defaults to the stored price"
+
+	del := &cobra.Command{
+		Use:   "delete <id>",
+		Short: "Delete a book",
+		Args:  exactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := opts.context(cmd)
+			defer cancel()
+
+			res, err := client().DeleteBook(ctx, connect.NewRequest(&v1.DeleteBookRequest{
+				Id: args[0],
+			}))
+			if err != nil {
+				return err
+			}
+			return printMessage(cmd.OutOrStdout(), opts.output, res.Msg, statusTable(res.Msg.Status))
+		},
+	}
+
+	cmd.AddCommand(list, get, create, update, del)
+	return cmd
+}
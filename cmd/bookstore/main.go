@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/iho/bookstore/internal/caller"
+	"github.com/iho/bookstore/internal/tenant"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes. Connect errors exit with connectExitBase plus the Connect code,
+// e.g. 15 for not_found (5) and 24 for unavailable (14).
+const (
+	exitError       = 1
+	exitUsage       = 2
+	connectExitBase = 10
+)
+
+type options struct {
+	authorsURL string
+	booksURL   string
+	ordersURL  string
+	token      string
+	tenant     string
+	output     string
+	timeout    time.Duration
+}
+
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// clientOptions returns the options shared by every service client.
+func (o *options) clientOptions() []connect.ClientOption {
+	return []connect.ClientOption{
+		connect.WithInterceptors(caller.NewInterceptor(o.token), tenant.NewInterceptor()),
+	}
+}
+
+func (o *options) context(cmd *cobra.Command) (context.Context, context.CancelFunc) {
+	ctx := tenant.WithID(cmd.Context(), o.tenant)
+	if o.timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, o.timeout)
+}
+
+func (o *options) httpClient() *http.Client {
+	return http.DefaultClient
+}
+
+func newRootCommand() *cobra.Command {
+	opts := &options{}
+
+	root := &cobra.Command{
+		Use:   "bookstore",
+		Short: "Command line client for the bookstore services",
+		Long: `Command line client for the bookstore services.
+
+Exit status is 0 on success, 1 on local errors and 2 on usage errors. Failed
+calls exit with 10 plus the Connect error code, e.g. 13 for invalid_argument,
+15 for not_found and 24 for unavailable.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if _, err := parseOutputFormat(opts.output); err != nil {
+				return usageError{err}
+			}
+			return nil
+		},
+	}
+
+	flags := root.PersistentFlags()
+	flags.StringVar(&opts.authorsURL, "authors-url", envOr("BOOKSTORE_AUTHORS_URL", "http://localhost:8080/"), "authors service URL [$BOOKSTORE_AUTHORS_URL]")
+	flags.StringVar(&opts.booksURL, "books-url", envOr("BOOKSTORE_BOOKS_URL", "http://localhost:9090/"), "books service URL [$BOOKSTORE_BOOKS_URL]")
+	flags.StringVar(&opts.ordersURL, "orders-url", envOr("BOOKSTORE_ORDERS_URL", "http://localhost:9999/"), "orders service URL [$BOOKSTORE_ORDERS_URL]")
+	flags.StringVar(&opts.token, "token", os.Getenv("BOOKSTORE_TOKEN"), "internal token of the services, INTERNAL_TOKEN, sent with every request [$BOOKSTORE_TOKEN]")
+	flags.StringVar(&opts.tenant, "tenant", os.Getenv("BOOKSTORE_TENANT"), "tenant ID sent with every request [$BOOKSTORE_TENANT]")
+	flags.StringVarP(&opts.output, "output", "o", string(outputTable), "output format: table, json or yaml")
+	flags.DurationVar(&opts.timeout, "timeout", 10*time.Second, "request timeout, 0 to disable")
+
+	root.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{string(outputTable), string(outputJSON), string(outputYAML)}, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	root.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return usageError{err}
+	})
+
+	root.AddCommand(
+		newAuthorsCommand(opts),
+		newBooksCommand(opts),
+		newOrdersCommand(opts),
+	)
+
+	return root
+}
+
+// usageError marks errors caused by bad arguments rather than failed calls.
+type usageError struct {
+	err error
+}
+
+func (e usageError) Error() string { return e.err.Error() }
+func (e usageError) Unwrap() error { return e.err }
+
+func exitCode(err error) int {
+	var usageErr usageError
+	if errors.As(err, &usageErr) {
+		return exitUsage
+	}
+	if code := connect.CodeOf(err); code != connect.CodeUnknown {
+		return connectExitBase + int(code)
+	}
+	return exitError
+}
+
+// exactArgs is cobra.ExactArgs reporting a usage error.
+func exactArgs(n int) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if err := cobra.ExactArgs(n)(cmd, args); err != nil {
+			return usageError{err}
+		}
+		return nil
+	}
+}
+
+func main() {
+	root := newRootCommand()
+	if err := root.Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(exitCode(err))
+	}
+}
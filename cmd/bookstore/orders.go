@@ -0,0 +1,508 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	moneyv1 "github.com/iho/bookstore/protos/gen/money/v1"
+	v1 "github.com/iho/bookstore/protos/gen/orders/v1"
+	"github.com/iho/bookstore/protos/gen/orders/v1/ordersv1connect"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/proto"
+)
+
+// parseOrderLines parses --line values of the form BOOK_ID:QUANTITY. The
+// quantity defaults to 1 when omitted.
+func parseOrderLines(values []string) ([]*v1.OrderLine, error) {
+	lines := make([]*v1.OrderLine, 0, len(values))
+	for _, value := range values {
+		bookID, quantity, found := strings.Cut(value, ":")
+		if bookID == "" {
+			return nil, fmt.Errorf("invalid order line %q, want BOOK_ID:QUANTITY", value)
+		}
+
+		var qty int64 = 1
+		if found {
+			var err error
+			qty, err = strconv.ParseInt(quantity, 10, 32)
+			if err != nil || qty < 1 {
+				return nil, fmt.Errorf("invalid quantity in order line %q", value)
+			}
+		}
+
+		lines = append(lines, &v1.OrderLine{
+			BookId:   bookID,
+			Quantity: int32(qty),
+		})
+	}
+	return lines, nil
+}
+
+// addressKeys are the keys --ship-to and --bill-to take.
+var addressKeys = []string{"name", "line1", "line2", "city", "region", "postal-code", "country", "phone"}
+
+// parseAddress parses address flags of the form KEY=VALUE,... into an
+// address. No values mean no address.
+func parseAddress(flag string, values map[string]string) (*v1.Address, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	for key := range values {
+		if !slices.Contains(addressKeys, key) {
+			return nil, fmt.Errorf("unknown key %q in --%s, want one of %s", key, flag, strings.Join(addressKeys, ", "))
+		}
+	}
+	return &v1.Address{
+		Name:       values["name"],
+		Line1:      values["line1"],
+		Line2:      values["line2"],
+		City:       values["city"],
+		Region:     values["region"],
+		PostalCode: values["postal-code"],
+		Country:    values["country"],
+		Phone:      values["phone"],
+	}, nil
+}
+
+// parseShipmentLines parses --line values of the form BOOK_ID:QUANTITY
+// into shipment lines.
+func parseShipmentLines(values []string) ([]*v1.ShipmentLine, error) {
+	orderLines, err := parseOrderLines(values)
+	if err != nil {
+		return nil, err
+	}
+	lines := make([]*v1.ShipmentLine, 0, len(orderLines))
+	for _, line := range orderLines {
+		lines = append(lines, &v1.ShipmentLine{
+			BookId:   line.BookId,
+			Quantity: line.Quantity,
+		})
+	}
+	return lines, nil
+}
+
+func newOrdersCommand(opts *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "orders",
+		Short: "Manage orders",
+	}
+
+	client := func() ordersv1connect.OrdersServiceClient {
+		return ordersv1connect.NewOrdersServiceClient(opts.httpClient(), opts.ordersURL, opts.clientOptions()...)
+	}
+
+	ordersTable := func(orders ...*v1.Order) table {
+		tbl := table{header: []string{"ID", "LINES", "DISCOUNT", "TAX", "TOTAL", "DATE", "STATUS"}}
+		for _, order := range orders {
+			lines := make([]string, 0, len(order.OrderLines))
+			for _, line := range order.OrderLines {
+				lines = append(lines, fmt.Sprintf("%s:%d", line.BookId, line.Quantity))
+			}
+			tbl.rows = append(tbl.rows, []string{
+				order.Id,
+				strings.Join(lines, ","),
+				formatMoney(order.DiscountTotal),
+				formatMoney(order.TaxTotal),
+				formatMoney(order.TotalPrice),
+				order.OrderDate,
+				strings.ToLower(strings.TrimPrefix(order.Status.String(), "ORDER_STATUS_")),
+			})
+		}
+		return tbl
+	}
+
+	shipmentsTable := func(order *v1.Order) table {
+		tbl := table{header: []string{"ID", "CARRIER", "TRACKING", "LINES", "SHIPPED", "DELIVERED"}}
+		for _, s := range order.Shipments {
+			lines := make([]string, 0, len(s.Lines))
+			for _, line := range s.Lines {
+				lines = append(lines, fmt.Sprintf("%s:%d", line.BookId, line.Quantity))
+			}
+			tbl.rows = append(tbl.rows, []string{
+				s.Id,
+				s.Carrier,
+				s.TrackingNumber,
+				strings.Join(lines, ","),
+				s.ShippedAt,
+				s.DeliveredAt,
+			})
+		}
+		return tbl
+	}
+
+	var bookIDs, listStatuses []string
+	var limit, offset int32
+	var from, to, customerID, listCurrency, totalCurrency, sortBy, pageToken string
+	var minTotal, maxTotal int64
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List orders",
+		Args:  exactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			req := &v1.ListOrdersRequest{
+				BookIds:    bookIDs,
+				Limit:      limit,
+				Offset:     offset,
+				From:       from,
+				To:         to,
+				CustomerId: customerID,
+				PageToken:  pageToken,
+				Currency:   listCurrency,
+			}
+			if cmd.Flags().Changed("min-total") {
+				req.MinTotal = &moneyv1.Money{Amount: minTotal, Currency: totalCurrency}
+			}
+			if cmd.Flags().Changed("max-total") {
+				req.MaxTotal = &moneyv1.Money{Amount: maxTotal, Currency: totalCurrency}
+			}
+			for _, s := range listStatuses {
+				status, err := parseEnum("order status", "ORDER_STATUS_", s, v1.OrderStatus_value)
+				if err != nil {
+					return usageError{err}
+				}
+				req.Statuses = append(req.Statuses, v1.OrderStatus(status))
+			}
+			if sortBy != "" {
+				sort, err := parseEnum("sort", "ORDER_SORT_", sortBy, v1.OrderSort_value)
+				if err != nil {
+					return usageError{err}
+				}
+				req.Sort = v1.OrderSort(sort)
+			}
+
+			ctx, cancel := opts.context(cmd)
+			defer cancel()
+
+			res, err := client().ListOrders(ctx, connect.NewRequest(req))
+			if err != nil {
+				return err
+			}
+			if err := printMessage(cmd.OutOrStdout(), opts.output, res.Msg, ordersTable(res.Msg.Orders...)); err != nil {
+				return err
+			}
+			// the tokens are part of the json and yaml output, the table
+			// leaves them out
+			if f, _ := parseOutputFormat(opts.output); f == outputTable {
+				fmt.Fprintf(cmd.ErrOrStderr(), "%d of %d orders\n", len(res.Msg.Orders), res.Msg.TotalCount)
+				if res.Msg.PreviousPageToken != "" {
+					fmt.Fprintf(cmd.ErrOrStderr(), "previous page: --page-token %s\n", res.Msg.PreviousPageToken)
+				}
+				if res.Msg.NextPageToken != "" {
+					fmt.Fprintf(cmd.ErrOrStderr(), "next page: --page-token %s\n", res.Msg.NextPageToken)
+				}
+			}
+			return nil
+		},
+	}
+	list.Flags().StringArrayVar(&bookIDs, "book-id", nil, "only list orders containing this book, repeatable")
+	list.Flags().StringVar(&from, "from", "", "only list orders dated at or after this RFC 3339 time")
+	list.Flags().StringVar(&to, "to", "", "only list orders dated before this RFC 3339 time")
+	list.Flags().Int64Var(&minTotal, "min-total", 0, "only list orders with at least this total price, in minor units")
+	list.Flags().Int64Var(&maxTotal, "max-total", 0, "only list orders with at most this total price, in minor units")
+	list.Flags().StringVar(&totalCurrency, "total-currency", "", "ISO 4217 currency of --min-total and --max-total, defaults to --currency or the currency of the store")
+	list.Flags().StringVar(&listCurrency, "currency", "", "only list orders in this ISO 4217 currency, total sorts default to the currency of the store")
+	list.Flags().StringArrayVar(&listStatuses, "status", nil, "only list orders with this status, e.g. paid, repeatable")
+	list.Flags().StringVar(&customerID, "customer", "", "only list orders of this customer")
+	list.Flags().StringVar(&sortBy, "sort", "", "date-asc, date-desc, total-asc or total-desc, defaults to date-desc; total sorts list one currency")
+	list.Flags().Int32Var(&limit, "limit", 10, "maximum number of orders to return, at most 100")
+	list.Flags().Int32Var(&offset, "offset", 0, "number of orders to skip")
+	list.Flags().StringVar(&pageToken, "page-token", "", "page to list, from a previous list")
+
+	get := &cobra.Command{
+		Use:   "get <id>",
+		Short: "Get an order",
+		Args:  exactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := opts.context(cmd)
+			defer cancel()
+
+			res, err := client().GetOrder(ctx, connect.NewRequest(&v1.GetOrderRequest{
+				Id: args[0],
+			}))
+			if err != nil {
+				return err
+			}
+			return printMessage(cmd.OutOrStdout(), opts.output, res.Msg, ordersTable(res.Msg.Order))
+		},
+	}
+
+	var lines []string
+	var totalPrice int32
+	var orderDate string
+	orderFlags := func(cmd *cobra.Command) {
+		cmd.Flags().StringArrayVar(&lines, "line", nil, "order line as BOOK_ID:QUANTITY, repeatable")
+		cmd.Flags().Int32Var(&totalPrice, "total", 0, "total price")
+		cmd.Flags().MarkDeprecated("total", "orders are priced by the orders service")
+		cmd.Flags().StringVar(&orderDate, "date", time.Now().UTC().Format(time.RFC3339), "RFC 3339 order date")
+		cmd.MarkFlagRequired("line")
+	}
+
+	var couponCode, currency, taxRegion string
+	termFlags := func(cmd *cobra.Command) {
+		cmd.Flags().StringVar(&couponCode, "coupon", "", "coupon code to redeem")
+		cmd.Flags().StringVar(&currency, "currency", "", "ISO 4217 currency of the order, defaults to the currency of the store")
+		cmd.Flags().StringVar(&taxRegion, "tax-region", "", "region taxes are computed for, e.g. US-CA, defaults to the region of --ship-to")
+	}
+
+	var shipTo, billTo map[string]string
+	addressFlags := func(cmd *cobra.Command, billing bool) {
+		keys := strings.Join(addressKeys, ",")
+		cmd.Flags().StringToStringVar(&shipTo, "ship-to", nil, "shipping address as KEY=VALUE pairs with keys "+keys)
+		if billing {
+			cmd.Flags().StringToStringVar(&billTo, "bill-to", nil, "billing address like --ship-to, defaults to the shipping address")
+		}
+	}
+	// addresses parses the address flags
+	addresses := func() (shipping, billing *v1.Address, err error) {
+		if shipping, err = parseAddress("ship-to", shipTo); err != nil {
+			return nil, nil, err
+		}
+		if billing, err = parseAddress("bill-to", billTo); err != nil {
+			return nil, nil, err
+		}
+		return shipping, billing, nil
+	}
+	price := &cobra.Command{
+		Use:   "price",
+		Short: "Price an order without placing it",
+		Args:  exactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			orderLines, err := parseOrderLines(lines)
+			if err != nil {
+				return usageError{err}
+			}
+			shipping, _, err := addresses()
+			if err != nil {
+				return usageError{err}
+			}
+
+			ctx, cancel := opts.context(cmd)
+			defer cancel()
+
+			res, err := client().PriceOrder(ctx, connect.NewRequest(&v1.PriceOrderRequest{
+				OrderLines:      orderLines,
+				CouponCode:      couponCode,
+				Currency:        currency,
+				TaxRegion:       taxRegion,
+				ShippingAddress: shipping,
+			}))
+			if err != nil {
+				return err
+			}
+			return printMessage(cmd.OutOrStdout(), opts.output, res.Msg, ordersTable(res.Msg.Order))
+		},
+	}
+	price.Flags().StringArrayVar(&lines, "line", nil, "order line as BOOK_ID:QUANTITY, repeatable")
+	termFlags(price)
+	addressFlags(price, false)
+	price.MarkFlagRequired("line")
+
+	create := &cobra.Command{
+		Use:   "create",
+		Short: "Create an order",
+		Args:  exactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			orderLines, err := parseOrderLines(lines)
+			if err != nil {
+				return usageError{err}
+			}
+			shipping, billing, err := addresses()
+			if err != nil {
+				return usageError{err}
+			}
+
+			ctx, cancel := opts.context(cmd)
+			defer cancel()
+
+			res, err := client().CreateOrder(ctx, connect.NewRequest(&v1.CreateOrderRequest{
+				OrderLines:      orderLines,
+				OrderDate:       orderDate,
+				CouponCode:      couponCode,
+				Currency:        currency,
+				TaxRegion:       taxRegion,
+				ShippingAddress: shipping,
+				BillingAddress:  billing,
+				CustomerId:      customerID,
+			}))
+			if err != nil {
+				return err
+			}
+			return printMessage(cmd.OutOrStdout(), opts.output, res.Msg, ordersTable(res.Msg.Order))
+		},
+	}
+	orderFlags(create)
+	termFlags(create)
+	addressFlags(create, true)
+	create.Flags().StringVar(&customerID, "customer", "", "ID of the customer placing the order")
+
+	update := &cobra.Command{
+		Use:   "update <id>",
+		Short: "Replace an order",
+		Args:  exactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			orderLines, err := parseOrderLines(lines)
+			if err != nil {
+				return usageError{err}
+			}
+			shipping, billing, err := addresses()
+			if err != nil {
+				return usageError{err}
+			}
+
+			req := &v1.UpdateOrderRequest{
+				Id:              args[0],
+				OrderLines:      orderLines,
+				OrderDate:       orderDate,
+				ShippingAddress: shipping,
+				BillingAddress:  billing,
+			}
+			// the coupon of the order is kept unless --coupon is given, an
+			// empty one removes it
+			if cmd.Flags().Changed("coupon") {
+				req.CouponCode = &couponCode
+			}
+
+			ctx, cancel := opts.context(cmd)
+			defer cancel()
+
+			res, err := client().UpdateOrder(ctx, connect.NewRequest(req))
+			if err != nil {
+				return err
+			}
+			return printMessage(cmd.OutOrStdout(), opts.output, res.Msg, ordersTable(res.Msg.Order))
+		},
+	}
+	orderFlags(update)
+	addressFlags(update, true)
+	update.Flags().StringVar(&couponCode, "coupon", "", "coupon code replacing the one of the order, empty to remove it")
+
+	del := &cobra.Command{
+		Use:   "delete <id>",
+		Short: "Delete an order",
+		Args:  exactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := opts.context(cmd)
+			defer cancel()
+
+			res, err := client().DeleteOrder(ctx, connect.NewRequest(&v1.DeleteOrderRequest{
+				Id: args[0],
+			}))
+			if err != nil {
+				return err
+			}
+			return printMessage(cmd.OutOrStdout(), opts.output, res.Msg, statusTable(res.Msg.Status))
+		},
+	}
+
+	// payment runs one payment operation on an order
+	payment := func(use, short string, call func(ctx context.Context, id string) (proto.Message, *v1.Order, error)) *cobra.Command {
+		return &cobra.Command{
+			Use:   use + " <id>",
+			Short: short,
+			Args:  exactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				ctx, cancel := opts.context(cmd)
+				defer cancel()
+
+				msg, order, err := call(ctx, args[0])
+				if err != nil {
+					return err
+				}
+				return printMessage(cmd.OutOrStdout(), opts.output, msg, ordersTable(order))
+			},
+		}
+	}
+
+	authorize := payment("authorize", "Authorize the payment of an order", func(ctx context.Context, id string) (proto.Message, *v1.Order, error) {
+		res, err := client().AuthorizePayment(ctx, connect.NewRequest(&v1.AuthorizePaymentRequest{OrderId: id}))
+		if err != nil {
+			return nil, nil, err
+		}
+		return res.Msg, res.Msg.Order, nil
+	})
+	capture := payment("capture", "Capture the authorized payment of an order", func(ctx context.Context, id string) (proto.Message, *v1.Order, error) {
+		res, err := client().CapturePayment(ctx, connect.NewRequest(&v1.CapturePaymentRequest{OrderId: id}))
+		if err != nil {
+			return nil, nil, err
+		}
+		return res.Msg, res.Msg.Order, nil
+	})
+	refund := payment("refund", "Refund the captured payment of an order", func(ctx context.Context, id string) (proto.Message, *v1.Order, error) {
+		res, err := client().RefundPayment(ctx, connect.NewRequest(&v1.RefundPaymentRequest{OrderId: id}))
+		if err != nil {
+			return nil, nil, err
+		}
+		return res.Msg, res.Msg.Order, nil
+	})
+	void := payment("void", "Void the authorized payment of an order", func(ctx context.Context, id string) (proto.Message, *v1.Order, error) {
+		res, err := client().VoidPayment(ctx, connect.NewRequest(&v1.VoidPaymentRequest{OrderId: id}))
+		if err != nil {
+			return nil, nil, err
+		}
+		return res.Msg, res.Msg.Order, nil
+	})
+
+	var carrier, trackingNumber, shippedAt string
+	var shipLines []string
+	ship := &cobra.Command{
+		Use:   "ship <id>",
+		Short: "Ship books of a paid order",
+		Args:  exactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			shipmentLines, err := parseShipmentLines(shipLines)
+			if err != nil {
+				return usageError{err}
+			}
+
+			ctx, cancel := opts.context(cmd)
+			defer cancel()
+
+			res, err := client().CreateShipment(ctx, connect.NewRequest(&v1.CreateShipmentRequest{
+				OrderId:        args[0],
+				Carrier:        carrier,
+				TrackingNumber: trackingNumber,
+				Lines:          shipmentLines,
+				ShippedAt:      shippedAt,
+			}))
+			if err != nil {
+				return err
+			}
+			return printMessage(cmd.OutOrStdout(), opts.output, res.Msg, shipmentsTable(res.Msg.Order))
+		},
+	}
+	ship.Flags().StringVar(&carrier, "carrier", "", "carrier the books are shipped with")
+	ship.Flags().StringVar(&trackingNumber, "tracking-number", "", "tracking number of the carrier")
+	ship.Flags().StringArrayVar(&shipLines, "line", nil, "books to ship as BOOK_ID:QUANTITY, repeatable, defaults to every book not shipped yet")
+	ship.Flags().StringVar(&shippedAt, "shipped-at", "", "RFC 3339 time the books were shipped, defaults to now")
+	ship.MarkFlagRequired("carrier")
+
+	var deliveredAt string
+	deliver := &cobra.Command{
+		Use:   "deliver <id> <shipment-id>",
+		Short: "Mark a shipment of an order delivered",
+		Args:  exactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := opts.context(cmd)
+			defer cancel()
+
+			res, err := client().DeliverShipment(ctx, connect.NewRequest(&v1.DeliverShipmentRequest{
+				OrderId:     args[0],
+				ShipmentId:  args[1],
+				DeliveredAt: deliveredAt,
+			}))
+			if err != nil {
+				return err
+			}
+			return printMessage(cmd.OutOrStdout(), opts.output, res.Msg, shipmentsTable(res.Msg.Order))
+		},
+	}
+	deliver.Flags().StringVar(&deliveredAt, "delivered-at", "", "RFC 3339 time the shipment arrived, defaults to now")
+
+	cmd.AddCommand(list, get, price, create, update, del, authorize, capture, refund, void, ship, deliver, newReportsCommand(opts))
+	return cmd
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/iho/bookstore/internal/money"
+	moneyv1 "github.com/iho/bookstore/protos/gen/money/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v3"
+)
+
+type outputFormat string
+
+const (
+	outputTable outputFormat = "table"
+	outputJSON  outputFormat = "json"
+	outputYAML  outputFormat = "yaml"
+)
+
+func parseOutputFormat(value string) (outputFormat, error) {
+	switch format := outputFormat(strings.ToLower(value)); format {
+	case outputTable, outputJSON, outputYAML:
+		return format, nil
+	}
+	return "", fmt.Errorf("unknown output format %q, want table, json or yaml", value)
+}
+
+// table is the tabular rendering of a response.
+type table struct {
+	header []string
+	rows   [][]string
+}
+
+// printMessage writes msg in the selected format. tbl is only used for the
+// table format.
+func printMessage(w io.Writer, format string, msg proto.Message, tbl table) error {
+	f, err := parseOutputFormat(format)
+	if err != nil {
+		return err
+	}
+
+	switch f {
+	case outputJSON:
+		data, err := protojson.MarshalOptions{Multiline: true, Indent: "  "}.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	case outputYAML:
+		// Go through JSON so field names match the json output.
+		data, err := protojson.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		var value any
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(value); err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(tbl.header, "\t"))
+	for _, row := range tbl.rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// formatMoney formats m in major units, e.g. "12.50 USD".
+func formatMoney(m *moneyv1.Money) string {
+	return money.New(m.GetAmount(), m.GetCurrency()).String()
+}
+
+func statusTable(status bool) table {
+	return table{
+		header: []string{"STATUS"},
+		rows:   [][]string{{strconv.FormatBool(status)}},
+	}
+}
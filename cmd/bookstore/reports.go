@@ -0,0 +1,240 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"connectrpc.com/connect"
+	v1 "github.com/iho/bookstore/protos/gen/orders/v1"
+	"github.com/iho/bookstore/protos/gen/orders/v1/ordersv1connect"
+	"github.com/spf13/cobra"
+)
+
+// parseEnum parses the lower case name of a value of a proto enum, e.g.
+// "paid" for ORDER_STATUS_PAID.
+func parseEnum(kind, prefix, value string, values map[string]int32) (int32, error) {
+	n, ok := values[prefix+strings.ToUpper(strings.ReplaceAll(value, "-", "_"))]
+	if !ok || n == 0 {
+		return 0, fmt.Errorf("unknown %s %q", kind, value)
+	}
+	return n, nil
+}
+
+func newReportsCommand(opts *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Report on the sales of paid orders",
+	}
+
+	client := func() ordersv1connect.OrdersServiceClient {
+		return ordersv1connect.NewOrdersServiceClient(opts.httpClient(), opts.ordersURL, opts.clientOptions()...)
+	}
+
+	var from, to, currency string
+	var statuses []string
+	// filter builds the filter of the report from the filter flags
+	filter := func() (*v1.SalesFilter, error) {
+		f := &v1.SalesFilter{From: from, To: to, Currency: currency}
+		for _, s := range statuses {
+			status, err := parseEnum("order status", "ORDER_STATUS_", s, v1.OrderStatus_value)
+			if err != nil {
+				return nil, err
+			}
+			f.Statuses = append(f.Statuses, v1.OrderStatus(status))
+		}
+		return f, nil
+	}
+	cmd.PersistentFlags().StringVar(&from, "from", "", "RFC 3339 start of the range, defaults to 30 days before --to")
+	cmd.PersistentFlags().StringVar(&to, "to", "", "RFC 3339 end of the range, exclusive, defaults to now")
+	cmd.PersistentFlags().StringVar(&currency, "currency", "", "ISO 4217 currency of the orders, defaults to the currency of the store")
+	cmd.PersistentFlags().StringArrayVar(&statuses, "status", nil, "order status to include, e.g. paid, repeatable, defaults to the paid and not refunded ones")
+
+	var interval string
+	// reportInterval parses --interval
+	reportInterval := func() (v1.ReportInterval, error) {
+		if interval == "" {
+			return v1.ReportInterval_REPORT_INTERVAL_UNSPECIFIED, nil
+		}
+		n, err := parseEnum("interval", "REPORT_INTERVAL_", interval, v1.ReportInterval_value)
+		return v1.ReportInterval(n), err
+	}
+	var limit int32
+
+	books := &cobra.Command{
+		Use:   "books",
+		Short: "List the best selling books",
+		Args:  exactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			f, err := filter()
+			if err != nil {
+				return usageError{err}
+			}
+
+			ctx, cancel := opts.context(cmd)
+			defer cancel()
+
+			res, err := client().ListBestSellingBooks(ctx, connect.NewRequest(&v1.ListBestSellingBooksRequest{
+				Filter: f,
+				Limit:  limit,
+			}))
+			if err != nil {
+				return err
+			}
+			tbl := table{header: []string{"BOOK", "QUANTITY", "ORDERS", "REVENUE"}}
+			for _, b := range res.Msg.Books {
+				tbl.rows = append(tbl.rows, []string{b.BookId, strconv.FormatInt(b.Quantity, 10), strconv.FormatInt(b.Orders, 10), formatMoney(b.Revenue)})
+			}
+			return printMessage(cmd.OutOrStdout(), opts.output, res.Msg, tbl)
+		},
+	}
+	books.Flags().Int32Var(&limit, "limit", 10, "number of books to list, at most 100")
+
+	authors := &cobra.Command{
+		Use:   "authors",
+		Short: "List the best selling authors",
+		Args:  exactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			f, err := filter()
+			if err != nil {
+				return usageError{err}
+			}
+
+			ctx, cancel := opts.context(cmd)
+			defer cancel()
+
+			res, err := client().ListBestSellingAuthors(ctx, connect.NewRequest(&v1.ListBestSellingAuthorsRequest{
+				Filter: f,
+				Limit:  limit,
+			}))
+			if err != nil {
+				return err
+			}
+			tbl := table{header: []string{"AUTHOR", "QUANTITY", "ORDERS", "BOOKS", "REVENUE"}}
+			for _, a := range res.Msg.Authors {
+				tbl.rows = append(tbl.rows, []string{a.AuthorId, strconv.FormatInt(a.Quantity, 10), strconv.FormatInt(a.Orders, 10), strconv.FormatInt(a.Books, 10), formatMoney(a.Revenue)})
+			}
+			return printMessage(cmd.OutOrStdout(), opts.output, res.Msg, tbl)
+		},
+	}
+	authors.Flags().Int32Var(&limit, "limit", 10, "number of authors to list, at most 100")
+
+	revenue := &cobra.Command{
+		Use:   "revenue",
+		Short: "Sum the orders per day, week or month",
+		Args:  exactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			f, err := filter()
+			if err != nil {
+				return usageError{err}
+			}
+			i, err := reportInterval()
+			if err != nil {
+				return usageError{err}
+			}
+
+			ctx, cancel := opts.context(cmd)
+			defer cancel()
+
+			res, err := client().GetRevenue(ctx, connect.NewRequest(&v1.GetRevenueRequest{
+				Filter:   f,
+				Interval: i,
+			}))
+			if err != nil {
+				return err
+			}
+			tbl := table{header: []string{"START", "ORDERS", "SUBTOTAL", "DISCOUNT", "TAX", "TOTAL"}}
+			for _, p := range res.Msg.Periods {
+				tbl.rows = append(tbl.rows, []string{p.Start, strconv.FormatInt(p.Orders, 10), formatMoney(p.SubtotalPrice), formatMoney(p.DiscountTotal), formatMoney(p.TaxTotal), formatMoney(p.TotalPrice)})
+			}
+			return printMessage(cmd.OutOrStdout(), opts.output, res.Msg, tbl)
+		},
+	}
+	revenue.Flags().StringVar(&interval, "interval", "day", "period to sum over: day, week or month")
+
+	stats := &cobra.Command{
+		Use:   "stats",
+		Short: "Show the average order value and how many books orders have",
+		Args:  exactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			f, err := filter()
+			if err != nil {
+				return usageError{err}
+			}
+
+			ctx, cancel := opts.context(cmd)
+			defer cancel()
+
+			res, err := client().GetOrderStats(ctx, connect.NewRequest(&v1.GetOrderStatsRequest{
+				Filter: f,
+			}))
+			if err != nil {
+				return err
+			}
+			tbl := table{header: []string{"ORDERS", "TOTAL", "AVERAGE", "BOOKS", "AVERAGE BOOKS"}}
+			tbl.rows = append(tbl.rows, []string{
+				strconv.FormatInt(res.Msg.Orders, 10),
+				formatMoney(res.Msg.TotalPrice),
+				formatMoney(res.Msg.AverageOrderValue),
+				strconv.FormatInt(res.Msg.Quantity, 10),
+				strconv.FormatFloat(res.Msg.AverageQuantity, 'f', 2, 64),
+			})
+			return printMessage(cmd.OutOrStdout(), opts.output, res.Msg, tbl)
+		},
+	}
+
+	var file string
+	export := &cobra.Command{
+		Use:   "export <books|authors|revenue|quantities>",
+		Short: "Export a report as CSV",
+		Args:  exactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			reports := map[string]v1.SalesReport{
+				"books":      v1.SalesReport_SALES_REPORT_BEST_SELLING_BOOKS,
+				"authors":    v1.SalesReport_SALES_REPORT_BEST_SELLING_AUTHORS,
+				"revenue":    v1.SalesReport_SALES_REPORT_REVENUE,
+				"quantities": v1.SalesReport_SALES_REPORT_QUANTITY_DISTRIBUTION,
+			}
+			report, ok := reports[args[0]]
+			if !ok {
+				return usageError{fmt.Errorf("unknown report %q", args[0])}
+			}
+			f, err := filter()
+			if err != nil {
+				return usageError{err}
+			}
+			i, err := reportInterval()
+			if err != nil {
+				return usageError{err}
+			}
+
+			ctx, cancel := opts.context(cmd)
+			defer cancel()
+
+			res, err := client().ExportSalesReport(ctx, connect.NewRequest(&v1.ExportSalesReportRequest{
+				Filter:   f,
+				Report:   report,
+				Limit:    limit,
+				Interval: i,
+			}))
+			if err != nil {
+				return err
+			}
+			if file == "" {
+				_, err = cmd.OutOrStdout().Write(res.Msg.Csv)
+				return err
+			}
+			if file == "." {
+				file = res.Msg.Filename
+			}
+			return os.WriteFile(file, res.Msg.Csv, 0o644)
+		},
+	}
+	export.Flags().StringVar(&file, "file", "", "file to write, \".\" for the name the service suggests, defaults to stdout")
+	export.Flags().Int32Var(&limit, "limit", 10, "number of books or authors to export, at most 100")
+	export.Flags().StringVar(&interval, "interval", "day", "period revenue is summed over: day, week or month")
+
+	cmd.AddCommand(books, authors, revenue, stats, export)
+	return cmd
+}
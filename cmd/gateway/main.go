@@ -1,44 +1,150 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
+	"os"
+	"os/signal"
 	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
+	"github.com/99designs/gqlgen/graphql"
 	"github.com/99designs/gqlgen/graphql/handler"
 	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/99designs/gqlgen/graphql/playground"
 	"github.com/go-chi/chi/v5"
+	"github.com/gorilla/websocket"
+	"github.com/iho/bookstore/internal/caller"
 	"github.com/iho/bookstore/internal/cfg"
+	"github.com/iho/bookstore/internal/clients"
+	"github.com/iho/bookstore/internal/gateway/auth"
+	"github.com/iho/bookstore/internal/gateway/cache"
 	"github.com/iho/bookstore/internal/gateway/graph"
+	"github.com/iho/bookstore/internal/gateway/limits"
 	"github.com/iho/bookstore/internal/gateway/loaders"
+	"github.com/iho/bookstore/internal/gateway/persisted"
+	"github.com/iho/bookstore/internal/idempotency"
+	"github.com/iho/bookstore/internal/logging"
+	"github.com/iho/bookstore/internal/metrics"
+	"github.com/iho/bookstore/internal/ratelimit"
+	"github.com/iho/bookstore/internal/redisclient"
+	"github.com/iho/bookstore/internal/telemetry"
+	"github.com/iho/bookstore/internal/tenant"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	redis "github.com/redis/go-redis/v9"
 	"github.com/rs/cors"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
-func main() {
-	var cfg = &cfg.Config{}
-	cfg.AuthorSericeUrl = "http://authors:9090"
-	cfg.BookServiceUrl = "http://books:8080"
+func envInt(key string, fallback int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return n, nil
+}
 
-	// create the query handler
-	srv := handler.NewDefaultServer(graph.NewExecutableSchema(graph.Config{Resolvers: graph.NewResolver(cfg)}))
+func envDuration(key string, fallback time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return d, nil
+}
 
-	router := chi.NewRouter()
+// reloadOnHangup reloads the manifest whenever the process gets SIGHUP.
+func reloadOnHangup(manifest *persisted.Manifest) {
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	for range hangup {
+		if err := manifest.Reload(); err != nil {
+			slog.Error("failed to reload persisted queries", "error", err)
+			continue
+		}
+		slog.Info("reloaded persisted queries", "operations", manifest.Len())
+	}
+}
 
-	c := cors.New(cors.Options{
-		AllowedOrigins:   []string{"*"},
-		AllowCredentials: true,
-		Debug:            false,
-	})
+func run() error {
+	var cfg = &cfg.Config{}
+	if tokens := os.Getenv("GATEWAY_API_TOKENS"); tokens != "" {
+		cfg.APITokens = strings.Split(tokens, ",")
+	}
+	cfg.JWTSecret = os.Getenv("GATEWAY_JWT_SECRET")
 
-	srv.Use(extension.Introspection{})
+	cfg.DefaultTenant = os.Getenv("GATEWAY_DEFAULT_TENANT")
+	if cfg.DefaultTenant != "" {
+		if err := tenant.Validate(cfg.DefaultTenant); err != nil {
+			return fmt.Errorf("invalid GATEWAY_DEFAULT_TENANT: %w", err)
+		}
+	}
 
-	router.Handle("/", playground.Handler("My GraphQL App", "/app"))
-	router.Handle("/app", loaders.Middleware(cfg, c.Handler(srv)))
+	var err error
+	if cfg.Authors, err = clients.FromEnv("AUTHORS", "http://authors:8080"); err != nil {
+		return err
+	}
+	if cfg.Books, err = clients.FromEnv("BOOKS", "http://books:9090"); err != nil {
+		return err
+	}
+	if cfg.Orders, err = clients.FromEnv("ORDERS", "http://orders:9999"); err != nil {
+		return err
+	}
+	if cfg.Cart, err = clients.FromEnv("CART", "http://books:9090"); err != nil {
+		return err
+	}
+	if cfg.MaxComplexity, err = envInt("GATEWAY_MAX_COMPLEXITY", 1000); err != nil {
+		return err
+	}
+	if cfg.MaxDepth, err = envInt("GATEWAY_MAX_DEPTH", 10); err != nil {
+		return err
+	}
+	if cfg.ListSize, err = envInt("GATEWAY_LIST_SIZE", 20); err != nil {
+		return err
+	}
+	if cfg.OperationTimeout, err = envDuration("GATEWAY_OPERATION_TIMEOUT", 10*time.Second); err != nil {
+		return err
+	}
+	cfg.PersistedQueriesManifest = os.Getenv("GATEWAY_PERSISTED_QUERIES")
+	cfg.StrictPersistedQueries = os.Getenv("GATEWAY_STRICT_PERSISTED_QUERIES") == "true"
+	if cfg.APQRedis, err = redisclient.OptionsFromEnv("GATEWAY_APQ_REDIS", ""); err != nil {
+		return err
+	}
+	if cfg.CacheTTL, err = envDuration("GATEWAY_CACHE_TTL", time.Minute); err != nil {
+		return err
+	}
+	if cfg.CacheSize, err = envInt("GATEWAY_CACHE_SIZE", 10000); err != nil {
+		return err
+	}
+	if cfg.CacheRedis, err = redisclient.OptionsFromEnv("GATEWAY_CACHE_REDIS", ""); err != nil {
+		return err
+	}
+	if cfg.StrictPersistedQueries && cfg.PersistedQueriesManifest == "" {
+		return errors.New("GATEWAY_STRICT_PERSISTED_QUERIES needs GATEWAY_PERSISTED_QUERIES")
+	}
+
+	shutdownTracing, err := telemetry.Setup(context.Background(), "gateway")
+	if err != nil {
+		return err
+	}
+	defer shutdownTracing(context.Background())
 
 	reg := prometheus.NewRegistry()
 
@@ -48,6 +154,134 @@ func main() {
 		collectors.WithGoCollectorRuntimeMetrics(collectors.GoRuntimeMetricsRule{Matcher: regexp.MustCompile("/.*")}),
 	))
 
+	clientMetrics := metrics.NewInterceptor()
+	graphqlMetrics := metrics.NewGraphQL()
+	reg.MustRegister(clientMetrics, graphqlMetrics)
+	loaders.RegisterMetrics(reg)
+	cache.RegisterMetrics(reg)
+	clients.RegisterMetrics(reg)
+	ratelimit.RegisterMetrics(reg)
+
+	internalToken, err := caller.TokenFromEnv()
+	if err != nil {
+		return err
+	}
+	services := clients.NewServices(
+		clients.NewFactory(
+			telemetry.NewInterceptor(),
+			logging.NewInterceptor(slog.Default(), false, false),
+			clientMetrics,
+			caller.NewInterceptor(internalToken),
+			tenant.NewInterceptor(),
+		),
+		cfg.Authors, cfg.Books, cfg.Orders, cfg.Cart,
+	)
+
+	var caches *cache.Caches
+	if cfg.CacheTTL > 0 {
+		var store cache.Store = cache.NewMemoryStore(cfg.CacheSize, cfg.CacheTTL)
+		if cfg.CacheRedis != nil {
+			store = cache.NewRedisStore(redis.NewUniversalClient(cfg.CacheRedis), cfg.CacheTTL)
+		}
+		caches = cache.New(store)
+		caches.Watch(context.Background(), services.Books, services.Authors)
+	}
+
+	limiter, err := ratelimit.FromEnv()
+	if err != nil {
+		return err
+	}
+
+	authenticator := auth.New(cfg.APITokens, cfg.JWTSecret)
+	tenants := auth.Tenants{Default: cfg.DefaultTenant}
+
+	// create the query handler
+	srv := handler.New(graph.NewExecutableSchema(graph.Config{
+		Resolvers:  graph.NewResolver(cfg, services, caches),
+		Directives: graph.DirectiveRoot{CacheControl: cache.Directive},
+		Complexity: graph.NewComplexity(cfg.ListSize),
+	}))
+
+	srv.SetErrorPresenter(graph.ErrorPresenter)
+
+	srv.AddTransport(transport.Websocket{
+		InitFunc: func(ctx context.Context, payload transport.InitPayload) (context.Context, *transport.InitPayload, error) {
+			ctx, _, err := authenticator.WebsocketInit(ctx, payload)
+			if err != nil {
+				return ctx, nil, err
+			}
+			return tenants.WebsocketInit(ctx, payload)
+		},
+		// keepalive messages for the legacy graphql-ws protocol
+		KeepAlivePingInterval: 10 * time.Second,
+		// ping/pong for graphql-transport-ws, dropping clients that stop
+		// answering so their subscriptions are released
+		PingPongInterval: 10 * time.Second,
+		InitTimeout:      10 * time.Second,
+		Upgrader: websocket.Upgrader{
+			// allow every origin, matching the CORS policy below
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+	})
+	srv.AddTransport(transport.Options{})
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.AddTransport(transport.MultipartForm{})
+
+	srv.SetQueryCache(lru.New(1000))
+
+	var manifest *persisted.Manifest
+	if cfg.PersistedQueriesManifest != "" {
+		if manifest, err = persisted.LoadManifest(cfg.PersistedQueriesManifest); err != nil {
+			return err
+		}
+		slog.Info("loaded persisted queries", "operations", manifest.Len(), "strict", cfg.StrictPersistedQueries)
+		go reloadOnHangup(manifest)
+	}
+
+	if cfg.StrictPersistedQueries {
+		srv.Use(persisted.Allowlist{Manifest: manifest})
+	} else {
+		var apqCache graphql.Cache = lru.New(1000)
+		if cfg.APQRedis != nil {
+			apqCache = persisted.NewRedisCache(redis.NewUniversalClient(cfg.APQRedis))
+		}
+		srv.Use(extension.AutomaticPersistedQuery{
+			Cache: persisted.NewCache(manifest, apqCache),
+		})
+	}
+	srv.Use(extension.FixedComplexityLimit(cfg.MaxComplexity))
+	srv.Use(limits.DepthLimit{Max: cfg.MaxDepth})
+	srv.Use(limits.Timeout{Duration: cfg.OperationTimeout})
+	if limiter != nil {
+		srv.Use(limits.RateLimit{Limiter: limiter})
+	}
+	srv.AroundResponses(loaders.ResponseMiddleware(services, caches))
+	srv.Use(cache.CacheControl{})
+	srv.Use(telemetry.GraphQLTracer{})
+	srv.Use(graphqlMetrics)
+
+	router := chi.NewRouter()
+
+	c := cors.New(cors.Options{
+		AllowedOrigins:   []string{"*"},
+		AllowCredentials: true,
+		Debug:            false,
+	})
+
+	// strict mode is for production clients, which need neither
+	if !cfg.StrictPersistedQueries {
+		srv.Use(extension.Introspection{})
+		router.Handle("/", playground.Handler("My GraphQL App", "/app"))
+	}
+	accessLog := logging.Middleware(slog.Default(), logging.AccessLogEnabled())
+	var app http.Handler = tenants.Middleware(idempotency.Middleware(cache.Middleware(srv)))
+	if limiter != nil {
+		app = limiter.Middleware(app)
+	}
+	app = authenticator.Middleware(app)
+	router.Handle("/app", otelhttp.NewHandler(accessLog(c.Handler(app)), "graphql"))
+
 	// Expose the registered metrics via HTTP.
 	router.Handle("/metrics", promhttp.HandlerFor(
 		reg,
@@ -58,8 +292,14 @@ func main() {
 	))
 
 	// register the wrapped handler
-	fmt.Println("Starting server on :10000")
-	if err := http.ListenAndServe(":10000", router); err != nil {
-		log.Fatal(err)
+	slog.Info("starting server", "addr", ":10000")
+	return http.ListenAndServe(":10000", router)
+}
+
+func main() {
+	slog.SetDefault(logging.New("gateway"))
+	if err := run(); err != nil {
+		slog.Error("server stopped", "error", err)
+		os.Exit(1)
 	}
 }
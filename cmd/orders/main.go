@@ -2,14 +2,30 @@ package main
 
 import (
 	"context"
-	"fmt"
-	"log"
+	"errors"
+	"log/slog"
 	"net/http"
 	"os"
 	"regexp"
 
+	"connectrpc.com/connect"
+	"github.com/iho/bookstore/internal/caller"
+	"github.com/iho/bookstore/internal/clients"
+	"github.com/iho/bookstore/internal/events"
+	"github.com/iho/bookstore/internal/idempotency"
+	"github.com/iho/bookstore/internal/logging"
+	"github.com/iho/bookstore/internal/metrics"
+	"github.com/iho/bookstore/internal/money"
 	"github.com/iho/bookstore/internal/orders"
+	"github.com/iho/bookstore/internal/payments"
+	"github.com/iho/bookstore/internal/promotions"
+	"github.com/iho/bookstore/internal/ratelimit"
+	"github.com/iho/bookstore/internal/tax"
+	"github.com/iho/bookstore/internal/telemetry"
+	"github.com/iho/bookstore/internal/tenant"
+	"github.com/iho/bookstore/protos/gen/books/v1/booksv1connect"
 	"github.com/iho/bookstore/protos/gen/orders/v1/ordersv1connect"
+	"github.com/iho/bookstore/protos/gen/promotions/v1/promotionsv1connect"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -19,21 +35,101 @@ import (
 	"golang.org/x/net/http2/h2c"
 )
 
-func main() {
+func run() error {
 	var uri string
 	if uri = os.Getenv("MONGODB_URI"); uri == "" {
-		log.Fatal("You must set your 'MONGODB_URI' environment variable. See\n\t https://www.mongodb.com/docs/drivers/go/current/usage-examples/#environment-variable")
+		return errors.New("you must set your 'MONGODB_URI' environment variable, see https://www.mongodb.com/docs/drivers/go/current/usage-examples/#environment-variable")
 	}
 	ctx := context.Background()
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+
+	shutdownTracing, err := telemetry.Setup(ctx, "orders")
+	if err != nil {
+		return err
+	}
+	defer shutdownTracing(ctx)
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri).SetMonitor(telemetry.NewMongoMonitor()))
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 	defer client.Disconnect(ctx)
 
-	ordersService := orders.NewOrdersService(client)
+	idem, err := idempotency.FromEnv()
+	if err != nil {
+		return err
+	}
+	provider, err := payments.FromEnv()
+	if err != nil {
+		return err
+	}
+	currency, err := money.CurrencyFromEnv()
+	if err != nil {
+		return err
+	}
+	rates, err := money.RatesFromEnv(currency)
+	if err != nil {
+		return err
+	}
+	taxes, err := tax.FromEnv()
+	if err != nil {
+		return err
+	}
+
+	broker, err := events.NewBrokerFromEnv()
+	if err != nil {
+		return err
+	}
+	relay := events.NewRelay(orders.NewOutboxStore(client), broker)
+	go relay.Run(ctx)
+
+	books, err := clients.FromEnv("BOOKS", "http://books:9090")
+	if err != nil {
+		return err
+	}
+	internalToken, err := caller.TokenFromEnv()
+	if err != nil {
+		return err
+	}
+	rpcMetrics := metrics.NewInterceptor()
+	interceptors := []connect.Interceptor{
+		telemetry.NewInterceptor(),
+		logging.NewInterceptor(slog.Default(), logging.AccessLogEnabled(), logging.LogBodiesEnabled()),
+		rpcMetrics,
+		caller.NewInterceptor(internalToken),
+		tenant.NewInterceptor(),
+	}
+	factory := clients.NewFactory(interceptors...)
+	limiter, err := ratelimit.FromEnv()
+	if err != nil {
+		return err
+	}
+	if limiter != nil {
+		interceptors = append(interceptors, ratelimit.NewInterceptor(limiter))
+	}
+
+	booksClient := clients.New(factory, books, booksv1connect.NewBooksServiceClient)
+	promotionsStore := promotions.NewStore(client)
+	ordersService := orders.NewOrdersService(client, idem, provider, booksClient, promotionsStore, currency, rates, taxes)
+	if err := ordersService.EnsureIndexes(ctx); err != nil {
+		return err
+	}
+
 	mux := http.NewServeMux()
-	mux.Handle(ordersv1connect.NewOrdersServiceHandler(ordersService))
+	mux.Handle(ordersv1connect.NewOrdersServiceHandler(
+		ordersService,
+		connect.WithInterceptors(interceptors...),
+	))
+	mux.Handle(promotionsv1connect.NewPromotionsServiceHandler(
+		promotions.NewPromotionsService(promotionsStore, currency),
+		connect.WithInterceptors(interceptors...),
+	))
+	// webhooks come from the provider, not from other services, so they
+	// name their tenant in the signed event rather than a header
+	if secret := os.Getenv("PAYMENTS_WEBHOOK_SECRET"); secret != "" {
+		mux.Handle("/webhooks/payments", payments.NewWebhookHandler([]byte(secret), ordersService.HandlePaymentEvent))
+	} else {
+		slog.Warn("payment webhooks disabled, PAYMENTS_WEBHOOK_SECRET is not set")
+	}
 
 	reg := prometheus.NewRegistry()
 
@@ -42,6 +138,10 @@ func main() {
 	reg.MustRegister(collectors.NewGoCollector(
 		collectors.WithGoCollectorRuntimeMetrics(collectors.GoRuntimeMetricsRule{Matcher: regexp.MustCompile("/.*")}),
 	))
+	clients.RegisterMetrics(reg)
+	reg.MustRegister(rpcMetrics)
+	ratelimit.RegisterMetrics(reg)
+	orders.RegisterMetrics(reg)
 
 	// Expose the registered metrics via HTTP.
 	mux.Handle("/metrics", promhttp.HandlerFor(
@@ -52,9 +152,15 @@ func main() {
 		},
 	))
 
-	fmt.Println("Starting server on :9999")
+	slog.Info("starting server", "addr", ":9999")
+
+	return http.ListenAndServe(":9999", h2c.NewHandler(mux, &http2.Server{}))
+}
 
-	if err := http.ListenAndServe(":9999", h2c.NewHandler(mux, &http2.Server{})); err != nil {
-		log.Fatal(err)
+func main() {
+	slog.SetDefault(logging.New("orders"))
+	if err := run(); err != nil {
+		slog.Error("server stopped", "error", err)
+		os.Exit(1)
 	}
 }
@@ -4,9 +4,29 @@
 
 package db
 
-import ()
+import (
+	"github.com/jackc/pgx/v5/pgtype"
+)
 
 type Author struct {
-	ID   int64
-	Name string
+	ID       int64
+	Name     string
+	TenantID string
+}
+
+type AuthorEvent struct {
+	ID        int64
+	EventType string
+	AuthorID  int64
+	Name      string
+	CreatedAt pgtype.Timestamptz
+	TenantID  string
+}
+
+type Outbox struct {
+	ID          string
+	Topic       string
+	Payload     []byte
+	CreatedAt   pgtype.Timestamptz
+	PublishedAt pgtype.Timestamptz
 }
@@ -7,6 +7,8 @@ package db
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const createAuthor = `-- name: CreateAuthor :one
@@ -15,41 +17,139 @@ INSERT INTO authors (
 ) VALUES (
   $1
 )
-RETURNING id, name
+RETURNING id, name, tenant_id
 `
 
 func (q *Queries) CreateAuthor(ctx context.Context, name string) (Author, error) {
 	row := q.db.QueryRow(ctx, createAuthor, name)
 	var i Author
-	err := row.Scan(&i.ID, &i.Name)
+	err := row.Scan(&i.ID, &i.Name, &i.TenantID)
 	return i, err
 }
 
-const deleteAuthor = `-- name: DeleteAuthor :exec
+const deleteAuthor = `-- name: DeleteAuthor :execrows
 DELETE FROM authors
 WHERE id = $1
-RETURNING id, name
+RETURNING id, name, tenant_id
 `
 
-func (q *Queries) DeleteAuthor(ctx context.Context, id int64) error {
-	_, err := q.db.Exec(ctx, deleteAuthor, id)
-	return err
+func (q *Queries) DeleteAuthor(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteAuthor, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteAuthorEventsBefore = `-- name: DeleteAuthorEventsBefore :execrows
+DELETE FROM author_events
+WHERE created_at < $1
+`
+
+func (q *Queries) DeleteAuthorEventsBefore(ctx context.Context, createdAt pgtype.Timestamptz) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteAuthorEventsBefore, createdAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
 
 const getAuthor = `-- name: GetAuthor :one
-SELECT id, name FROM authors
+SELECT id, name, tenant_id FROM authors
 WHERE id = $1 LIMIT 1
 `
 
 func (q *Queries) GetAuthor(ctx context.Context, id int64) (Author, error) {
 	row := q.db.QueryRow(ctx, getAuthor, id)
 	var i Author
-	err := row.Scan(&i.ID, &i.Name)
+	err := row.Scan(&i.ID, &i.Name, &i.TenantID)
 	return i, err
 }
 
+const getAuthorForUpdate = `-- name: GetAuthorForUpdate :one
+SELECT id, name, tenant_id FROM authors
+WHERE id = $1 LIMIT 1
+FOR UPDATE
+`
+
+func (q *Queries) GetAuthorForUpdate(ctx context.Context, id int64) (Author, error) {
+	row := q.db.QueryRow(ctx, getAuthorForUpdate, id)
+	var i Author
+	err := row.Scan(&i.ID, &i.Name, &i.TenantID)
+	return i, err
+}
+
+const getLastAuthorEventID = `-- name: GetLastAuthorEventID :one
+SELECT COALESCE(MAX(id), 0)::bigint AS last_id FROM author_events
+`
+
+func (q *Queries) GetLastAuthorEventID(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, getLastAuthorEventID)
+	var last_id int64
+	err := row.Scan(&last_id)
+	return last_id, err
+}
+
+const insertOutboxMessage = `-- name: InsertOutboxMessage :exec
+INSERT INTO outbox (
+  id, topic, payload
+) VALUES (
+  $1, $2, $3
+)
+`
+
+type InsertOutboxMessageParams struct {
+	ID      string
+	Topic   string
+	Payload []byte
+}
+
+func (q *Queries) InsertOutboxMessage(ctx context.Context, arg InsertOutboxMessageParams) error {
+	_, err := q.db.Exec(ctx, insertOutboxMessage, arg.ID, arg.Topic, arg.Payload)
+	return err
+}
+
+const listAuthorEventsAfter = `-- name: ListAuthorEventsAfter :many
+SELECT id, event_type, author_id, name, created_at, tenant_id FROM author_events
+WHERE id > $1
+ORDER BY id
+LIMIT $2
+`
+
+type ListAuthorEventsAfterParams struct {
+	ID    int64
+	Limit int32
+}
+
+func (q *Queries) ListAuthorEventsAfter(ctx context.Context, arg ListAuthorEventsAfterParams) ([]AuthorEvent, error) {
+	rows, err := q.db.Query(ctx, listAuthorEventsAfter, arg.ID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AuthorEvent
+	for rows.Next() {
+		var i AuthorEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.EventType,
+			&i.AuthorID,
+			&i.Name,
+			&i.CreatedAt,
+			&i.TenantID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listAuthors = `-- name: ListAuthors :many
-SELECT id, name FROM authors
+SELECT id, name, tenant_id FROM authors
 ORDER BY name limit $1 offset $2
 `
 
@@ -67,7 +167,40 @@ func (q *Queries) ListAuthors(ctx context.Context, arg ListAuthorsParams) ([]Aut
 	var items []Author
 	for rows.Next() {
 		var i Author
-		if err := rows.Scan(&i.ID, &i.Name); err != nil {
+		if err := rows.Scan(&i.ID, &i.Name, &i.TenantID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUnpublishedOutboxMessages = `-- name: ListUnpublishedOutboxMessages :many
+SELECT id, topic, payload, created_at, published_at FROM outbox
+WHERE published_at IS NULL
+ORDER BY created_at, id
+LIMIT $1
+`
+
+func (q *Queries) ListUnpublishedOutboxMessages(ctx context.Context, limit int32) ([]Outbox, error) {
+	rows, err := q.db.Query(ctx, listUnpublishedOutboxMessages, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Outbox
+	for rows.Next() {
+		var i Outbox
+		if err := rows.Scan(
+			&i.ID,
+			&i.Topic,
+			&i.Payload,
+			&i.CreatedAt,
+			&i.PublishedAt,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -78,11 +211,31 @@ func (q *Queries) ListAuthors(ctx context.Context, arg ListAuthorsParams) ([]Aut
 	return items, nil
 }
 
+const markOutboxMessagesPublished = `-- name: MarkOutboxMessagesPublished :exec
+UPDATE outbox
+  set published_at = now()
+WHERE id = ANY($1::text[])
+`
+
+func (q *Queries) MarkOutboxMessagesPublished(ctx context.Context, ids []string) error {
+	_, err := q.db.Exec(ctx, markOutboxMessagesPublished, ids)
+	return err
+}
+
+const setTenant = `-- name: SetTenant :exec
+SELECT set_config('app.tenant_id', $1::text, true), set_config('role', 'bookstore_tenant', true)
+`
+
+func (q *Queries) SetTenant(ctx context.Context, tenantID string) error {
+	_, err := q.db.Exec(ctx, setTenant, tenantID)
+	return err
+}
+
 const updateAuthor = `-- name: UpdateAuthor :exec
 UPDATE authors
   set name = $2
 WHERE id = $1
-RETURNING id, name
+RETURNING id, name, tenant_id
 `
 
 type UpdateAuthorParams struct {
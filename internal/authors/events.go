@@ -0,0 +1,223 @@
+package authors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/iho/bookstore/internal/authors/db"
+	v1 "github.com/iho/bookstore/protos/gen/authors/v1"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	authorEventsChannel = "author_events"
+	watchBatchSize      = 100
+	// watchPollInterval bounds how long a watcher waits when a notification
+	// is lost, e.g. while the listener reconnects.
+	watchPollInterval = 30 * time.Second
+	listenRetryDelay  = time.Second
+	// pruneInterval is how often events past their retention are deleted.
+	pruneInterval = time.Hour
+)
+
+var eventTypes = map[string]v1.EventType{
+	"created": v1.EventType_EVENT_TYPE_CREATED,
+	"updated": v1.EventType_EVENT_TYPE_UPDATED,
+	"deleted": v1.EventType_EVENT_TYPE_DELETED,
+}
+
+// EventListener holds a dedicated connection that LISTENs on the
+// author_events channel and wakes up every active watcher on notification.
+// The events themselves are read from the author_events table.
+type EventListener struct {
+	connString string
+
+	mu          sync.Mutex
+	subscribers map[chan struct{}]struct{}
+}
+
+func NewEventListener(connString string) *EventListener {
+	return &EventListener{
+		connString:  connString,
+		subscribers: make(map[chan struct{}]struct{}),
+	}
+}
+
+// Run listens for notifications until ctx is done, reconnecting on errors.
+func (l *EventListener) Run(ctx context.Context) error {
+	for {
+		err := l.listen(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		slog.ErrorContext(ctx, "event listener failed", "error", err)
+
+		// Notifications may have been missed while disconnected.
+		l.broadcast()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(listenRetryDelay):
+		}
+	}
+}
+
+func (l *EventListener) listen(ctx context.Context) error {
+	conn, err := pgx.Connect(ctx, l.connString)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+authorEventsChannel); err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+
+	for {
+		if _, err := conn.WaitForNotification(ctx); err != nil {
+			return fmt.Errorf("failed to wait for notification: %w", err)
+		}
+		l.broadcast()
+	}
+}
+
+func (l *EventListener) subscribe() (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	l.mu.Lock()
+	l.subscribers[ch] = struct{}{}
+	l.mu.Unlock()
+
+	return ch, func() {
+		l.mu.Lock()
+		delete(l.subscribers, ch)
+		l.mu.Unlock()
+	}
+}
+
+// broadcast wakes up subscribers without blocking. A subscriber that has not
+// consumed its previous wake-up will read the table anyway.
+func (l *EventListener) broadcast() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for ch := range l.subscribers {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (as *AuthorsService) WatchAuthors(ctx context.Context, req *connect.Request[v1.WatchAuthorsRequest], stream *connect.ServerStream[v1.WatchAuthorsResponse]) error {
+	if as.events == nil {
+		return connect.NewError(connect.CodeUnimplemented, errors.New("author events are not enabled"))
+	}
+
+	// Subscribe before reading the backlog so no notification is missed.
+	wake, unsubscribe := as.events.subscribe()
+	defer unsubscribe()
+
+	var lastID int64
+	if token := req.Msg.GetResumeToken(); token != "" {
+		id, err := strconv.ParseInt(token, 10, 64)
+		if err != nil || id < 0 {
+			return connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("invalid resume token: %q", token))
+		}
+		lastID = id
+	} else {
+		err := as.inTx(ctx, func(q *db.Queries) error {
+			var err error
+			lastID, err = q.GetLastAuthorEventID(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to get last author event: %w", err)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for {
+		var events []db.AuthorEvent
+		err := as.inTx(ctx, func(q *db.Queries) error {
+			var err error
+			events, err = q.ListAuthorEventsAfter(ctx, db.ListAuthorEventsAfterParams{
+				ID:    lastID,
+				Limit: watchBatchSize,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to list author events: %w", err)
+			}
+			return nil
+		})
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			err := stream.Send(&v1.WatchAuthorsResponse{
+				Type: eventTypes[event.EventType],
+				Author: &v1.Author{
+					Id:   strconv.FormatInt(event.AuthorID, 10),
+					Name: event.Name,
+				},
+				ResumeToken: strconv.FormatInt(event.ID, 10),
+			})
+			if err != nil {
+				return err
+			}
+			lastID = event.ID
+		}
+
+		if len(events) == watchBatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-wake:
+		case <-time.After(watchPollInterval):
+		}
+	}
+}
+
+// PruneEvents deletes the events older than retention every pruneInterval
+// until ctx is done. Watchers resuming from a pruned event miss the pruned
+// events after it.
+func (as *AuthorsService) PruneEvents(ctx context.Context, retention time.Duration) {
+	ticker := time.NewTicker(pruneInterval)
+	defer ticker.Stop()
+
+	for {
+		// the events of every tenant are pruned, so this does not run in a
+		// tenant transaction
+		before := pgtype.Timestamptz{Time: time.Now().Add(-retention), Valid: true}
+		n, err := as.pgDB.DeleteAuthorEventsBefore(ctx, before)
+		switch {
+		case err != nil && ctx.Err() == nil:
+			slog.ErrorContext(ctx, "failed to prune author events", "error", err)
+		case n > 0:
+			slog.InfoContext(ctx, "pruned author events", "events", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -7,27 +7,72 @@ import (
 
 	"connectrpc.com/connect"
 	"github.com/iho/bookstore/internal/authors/db"
+	"github.com/iho/bookstore/internal/idempotency"
+	"github.com/iho/bookstore/internal/tenant"
 	v1 "github.com/iho/bookstore/protos/gen/authors/v1"
+	eventsv1 "github.com/iho/bookstore/protos/gen/events/v1"
+	"github.com/jackc/pgx/v5"
 )
 
+// DB is a connection the service can start transactions on, e.g. a
+// *pgxpool.Pool.
+type DB interface {
+	db.DBTX
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
 type AuthorsService struct {
-	pgDB *db.Queries
+	pool        DB
+	pgDB        *db.Queries
+	events      *EventListener
+	idempotency idempotency.Store
 }
 
-func NewAuthorsService(pgDB db.DBTX) *AuthorsService {
+// NewAuthorsService creates the service. events may be nil, in which case
+// WatchAuthors is unavailable, and idem may be nil, in which case
+// idempotency keys are ignored.
+func NewAuthorsService(pool DB, events *EventListener, idem idempotency.Store) *AuthorsService {
 	return &AuthorsService{
-		pgDB: db.New(pgDB),
+		pool:        pool,
+		pgDB:        db.New(pool),
+		events:      events,
+		idempotency: idem,
 	}
 }
 
-func (as *AuthorsService) ListAuthors(ctx context.Context, req *connect.Request[v1.ListAuthorsRequest]) (*connect.Response[v1.ListAuthorsResponse], error) {
-	dbAuthors, err := as.pgDB.ListAuthors(ctx, db.ListAuthorsParams{
-		Limit:  req.Msg.Limit,
-		Offset: req.Msg.Offset,
+// inTx runs fn in a transaction scoped to the tenant of ctx, so that
+// changes and the events written to the outbox are committed together.
+// Every query runs in one: row level security only shows the transaction
+// the rows of its tenant.
+func (as *AuthorsService) inTx(ctx context.Context, fn func(q *db.Queries) error) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+	return pgx.BeginFunc(ctx, as.pool, func(tx pgx.Tx) error {
+		q := as.pgDB.WithTx(tx)
+		if err := q.SetTenant(ctx, tenantID); err != nil {
+			return fmt.Errorf("failed to set tenant: %w", err)
+		}
+		return fn(q)
 	})
+}
 
+func (as *AuthorsService) ListAuthors(ctx context.Context, req *connect.Request[v1.ListAuthorsRequest]) (*connect.Response[v1.ListAuthorsResponse], error) {
+	var dbAuthors []db.Author
+	err := as.inTx(ctx, func(q *db.Queries) error {
+		var err error
+		dbAuthors, err = q.ListAuthors(ctx, db.ListAuthorsParams{
+			Limit:  req.Msg.Limit,
+			Offset: req.Msg.Offset,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list authors: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to list authors: %w", err)
+		return nil, err
 	}
 
 	authors := make([]*v1.Author, 0, len(dbAuthors))
@@ -51,9 +96,17 @@ func (as AuthorsService) GetAuthor(ctx context.Context, req *connect.Request[v1.
 		return nil, fmt.Errorf("failed to parse ID: %w", err)
 	}
 
-	dbAuthor, err := as.pgDB.GetAuthor(ctx, id)
+	var dbAuthor db.Author
+	err = as.inTx(ctx, func(q *db.Queries) error {
+		var err error
+		dbAuthor, err = q.GetAuthor(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get author: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get author: %w", err)
+		return nil, err
 	}
 
 	return &connect.Response[v1.GetAuthorResponse]{
@@ -67,9 +120,25 @@ func (as AuthorsService) GetAuthor(ctx context.Context, req *connect.Request[v1.
 }
 
 func (as *AuthorsService) CreateAuthor(ctx context.Context, req *connect.Request[v1.CreateAuthorRequest]) (*connect.Response[v1.CreateAuthorResponse], error) {
-	dbAuthor, err := as.pgDB.CreateAuthor(ctx, req.Msg.Name)
+	return idempotency.Handle(ctx, as.idempotency, req, as.createAuthor)
+}
+
+func (as *AuthorsService) createAuthor(ctx context.Context, req *connect.Request[v1.CreateAuthorRequest]) (*connect.Response[v1.CreateAuthorResponse], error) {
+	var dbAuthor db.Author
+	err := as.inTx(ctx, func(q *db.Queries) error {
+		var err error
+		dbAuthor, err = q.CreateAuthor(ctx, req.Msg.Name)
+		if err != nil {
+			return fmt.Errorf("failed to create author: %w", err)
+		}
+
+		return addEvent(ctx, q, dbAuthor.ID, &eventsv1.AuthorCreated{
+			AuthorId: strconv.FormatInt(dbAuthor.ID, 10),
+			Name:     dbAuthor.Name,
+		})
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create author: %w", err)
+		return nil, err
 	}
 
 	return &connect.Response[v1.CreateAuthorResponse]{
@@ -88,18 +157,37 @@ func (as *AuthorsService) UpdateAuthor(ctx context.Context, req *connect.Request
 		return nil, fmt.Errorf("failed to parse ID: %w", err)
 	}
 
-	err = as.pgDB.UpdateAuthor(ctx, db.UpdateAuthorParams{
-		ID:   id,
-		Name: req.Msg.Name,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to update author: %w", err)
-	}
+	var dbAuthor db.Author
+	err = as.inTx(ctx, func(q *db.Queries) error {
+		previous, err := q.GetAuthorForUpdate(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get author: %w", err)
+		}
 
-	// maybe transaction is needed here
-	dbAuthor, err := as.pgDB.GetAuthor(ctx, id)
+		err = q.UpdateAuthor(ctx, db.UpdateAuthorParams{
+			ID:   id,
+			Name: req.Msg.Name,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update author: %w", err)
+		}
+
+		dbAuthor, err = q.GetAuthor(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get author: %w", err)
+		}
+
+		if previous.Name == dbAuthor.Name {
+			return nil
+		}
+		return addEvent(ctx, q, id, &eventsv1.AuthorRenamed{
+			AuthorId:     strconv.FormatInt(id, 10),
+			PreviousName: previous.Name,
+			Name:         dbAuthor.Name,
+		})
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get author: %w", err)
+		return nil, err
 	}
 
 	return &connect.Response[v1.UpdateAuthorResponse]{
@@ -118,13 +206,27 @@ func (as *AuthorsService) DeleteAuthor(ctx context.Context, req *connect.Request
 		return nil, fmt.Errorf("failed to parse ID: %w", err)
 	}
 
-	err = as.pgDB.DeleteAuthor(ctx, id)
+	var deleted bool
+	err = as.inTx(ctx, func(q *db.Queries) error {
+		n, err := q.DeleteAuthor(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to delete author: %w", err)
+		}
+
+		deleted = n > 0
+		if !deleted {
+			return nil
+		}
+		return addEvent(ctx, q, id, &eventsv1.AuthorDeleted{
+			AuthorId: strconv.FormatInt(id, 10),
+		})
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to delete author: %w", err)
+		return nil, err
 	}
 	return &connect.Response[v1.DeleteAuthorResponse]{
 		Msg: &v1.DeleteAuthorResponse{
-			Status: true,
+			Status: deleted,
 		},
 	}, nil
 }
@@ -0,0 +1,73 @@
+-- Scopes a database created before authors belonged to tenants, moving the
+-- existing authors and events to one tenant, LEGACY_TENANT of the other
+-- services:
+--
+--   psql -v tenant=default -f migrate_tenants.sql
+--
+-- It can be run again, rows that have a tenant keep it.
+\if :{?tenant}
+\else
+  \set tenant default
+\endif
+
+BEGIN;
+
+ALTER TABLE authors ADD COLUMN IF NOT EXISTS tenant_id text;
+UPDATE authors SET tenant_id = :'tenant' WHERE tenant_id IS NULL;
+ALTER TABLE authors
+  ALTER COLUMN tenant_id SET NOT NULL,
+  ALTER COLUMN tenant_id SET DEFAULT current_setting('app.tenant_id');
+
+CREATE INDEX IF NOT EXISTS authors_tenant_name ON authors (tenant_id, name);
+
+ALTER TABLE author_events ADD COLUMN IF NOT EXISTS tenant_id text;
+UPDATE author_events SET tenant_id = :'tenant' WHERE tenant_id IS NULL;
+ALTER TABLE author_events ALTER COLUMN tenant_id SET NOT NULL;
+
+CREATE OR REPLACE FUNCTION record_author_event() RETURNS trigger AS $$
+DECLARE
+  event_id bigint;
+BEGIN
+  IF TG_OP = 'DELETE' THEN
+    INSERT INTO author_events (event_type, author_id, name, tenant_id)
+    VALUES ('deleted', OLD.id, OLD.name, OLD.tenant_id)
+    RETURNING id INTO event_id;
+  ELSE
+    INSERT INTO author_events (event_type, author_id, name, tenant_id)
+    VALUES (CASE TG_OP WHEN 'INSERT' THEN 'created' ELSE 'updated' END, NEW.id, NEW.name, NEW.tenant_id)
+    RETURNING id INTO event_id;
+  END IF;
+
+  PERFORM pg_notify('author_events', event_id::text);
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT FROM pg_roles WHERE rolname = 'bookstore_tenant') THEN
+    CREATE ROLE bookstore_tenant NOLOGIN;
+  END IF;
+  IF NOT pg_has_role(CURRENT_USER, 'bookstore_tenant', 'MEMBER') THEN
+    GRANT bookstore_tenant TO CURRENT_USER;
+  END IF;
+END
+$$;
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON authors TO bookstore_tenant;
+GRANT SELECT, INSERT ON author_events TO bookstore_tenant;
+GRANT INSERT ON outbox TO bookstore_tenant;
+GRANT USAGE ON SEQUENCE authors_id_seq, author_events_id_seq TO bookstore_tenant;
+
+ALTER TABLE authors ENABLE ROW LEVEL SECURITY;
+ALTER TABLE author_events ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS authors_tenant_isolation ON authors;
+CREATE POLICY authors_tenant_isolation ON authors
+  USING (tenant_id = current_setting('app.tenant_id'));
+
+DROP POLICY IF EXISTS author_events_tenant_isolation ON author_events;
+CREATE POLICY author_events_tenant_isolation ON author_events
+  USING (tenant_id = current_setting('app.tenant_id'));
+
+COMMIT;
@@ -0,0 +1,69 @@
+package authors
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/iho/bookstore/internal/authors/db"
+	"github.com/iho/bookstore/internal/events"
+	"google.golang.org/protobuf/proto"
+)
+
+// addEvent writes a domain event about author id to the outbox. q must be
+// bound to the transaction making the change.
+func addEvent(ctx context.Context, q *db.Queries, id int64, payload proto.Message) error {
+	msg, err := events.NewMessage(ctx, events.TopicAuthors, strconv.FormatInt(id, 10), payload)
+	if err != nil {
+		return err
+	}
+
+	err = q.InsertOutboxMessage(ctx, db.InsertOutboxMessageParams{
+		ID:      msg.ID,
+		Topic:   msg.Topic,
+		Payload: msg.Payload,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write outbox: %w", err)
+	}
+	return nil
+}
+
+// OutboxStore reads the outbox table for events.Relay.
+type OutboxStore struct {
+	pgDB *db.Queries
+}
+
+func NewOutboxStore(pgDB db.DBTX) *OutboxStore {
+	return &OutboxStore{
+		pgDB: db.New(pgDB),
+	}
+}
+
+func (s *OutboxStore) Fetch(ctx context.Context, limit int) ([]events.OutboxEntry, error) {
+	rows, err := s.pgDB.ListUnpublishedOutboxMessages(ctx, int32(limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list outbox: %w", err)
+	}
+
+	entries := make([]events.OutboxEntry, 0, len(rows))
+	for _, row := range rows {
+		entries = append(entries, events.OutboxEntry{
+			Key: row.ID,
+			Message: events.Message{
+				ID:      row.ID,
+				Topic:   row.Topic,
+				Payload: row.Payload,
+			},
+		})
+	}
+	return entries, nil
+}
+
+func (s *OutboxStore) MarkPublished(ctx context.Context, entries []events.OutboxEntry) error {
+	ids := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		ids = append(ids, entry.Key)
+	}
+	return s.pgDB.MarkOutboxMessagesPublished(ctx, ids)
+}
@@ -1,7 +1,15 @@
+-- name: SetTenant :exec
+SELECT set_config('app.tenant_id', @tenant_id::text, true), set_config('role', 'bookstore_tenant', true);
+
 -- name: GetAuthor :one
 SELECT * FROM authors
 WHERE id = $1 LIMIT 1;
 
+-- name: GetAuthorForUpdate :one
+SELECT * FROM authors
+WHERE id = $1 LIMIT 1
+FOR UPDATE;
+
 -- name: ListAuthors :many
 SELECT * FROM authors
 ORDER BY name limit $1 offset $2;
@@ -20,7 +28,38 @@ UPDATE authors
 WHERE id = $1
 RETURNING *;
 
--- name: DeleteAuthor :exec
+-- name: DeleteAuthor :execrows
 DELETE FROM authors
 WHERE id = $1
-RETURNING *;
\ No newline at end of file
+RETURNING *;
+
+-- name: ListAuthorEventsAfter :many
+SELECT * FROM author_events
+WHERE id > $1
+ORDER BY id
+LIMIT $2;
+
+-- name: DeleteAuthorEventsBefore :execrows
+DELETE FROM author_events
+WHERE created_at < $1;
+
+-- name: GetLastAuthorEventID :one
+SELECT COALESCE(MAX(id), 0)::bigint AS last_id FROM author_events;
+
+-- name: InsertOutboxMessage :exec
+INSERT INTO outbox (
+  id, topic, payload
+) VALUES (
+  $1, $2, $3
+);
+
+-- name: ListUnpublishedOutboxMessages :many
+SELECT * FROM outbox
+WHERE published_at IS NULL
+ORDER BY created_at, id
+LIMIT $1;
+
+-- name: MarkOutboxMessagesPublished :exec
+UPDATE outbox
+  set published_at = now()
+WHERE id = ANY(@ids::text[]);
\ No newline at end of file
@@ -1,4 +1,87 @@
 CREATE TABLE authors (
-  id   BIGSERIAL PRIMARY KEY,
-  name text      NOT NULL
-);
\ No newline at end of file
+  id        BIGSERIAL PRIMARY KEY,
+  name      text      NOT NULL,
+  tenant_id text      NOT NULL DEFAULT current_setting('app.tenant_id')
+);
+
+CREATE INDEX authors_tenant_name ON authors (tenant_id, name);
+
+CREATE TABLE author_events (
+  id         BIGSERIAL   PRIMARY KEY,
+  event_type text        NOT NULL,
+  author_id  bigint      NOT NULL,
+  name       text        NOT NULL,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  tenant_id  text        NOT NULL
+);
+
+-- author_events_created_at serves the pruning of old events.
+CREATE INDEX author_events_created_at ON author_events (created_at);
+
+-- record_author_event logs every change to authors and wakes up watchers
+-- listening on the author_events channel. The payload is the event id.
+CREATE FUNCTION record_author_event() RETURNS trigger AS $$
+DECLARE
+  event_id bigint;
+BEGIN
+  IF TG_OP = 'DELETE' THEN
+    INSERT INTO author_events (event_type, author_id, name, tenant_id)
+    VALUES ('deleted', OLD.id, OLD.name, OLD.tenant_id)
+    RETURNING id INTO event_id;
+  ELSE
+    INSERT INTO author_events (event_type, author_id, name, tenant_id)
+    VALUES (CASE TG_OP WHEN 'INSERT' THEN 'created' ELSE 'updated' END, NEW.id, NEW.name, NEW.tenant_id)
+    RETURNING id INTO event_id;
+  END IF;
+
+  PERFORM pg_notify('author_events', event_id::text);
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER authors_record_event
+AFTER INSERT OR UPDATE OR DELETE ON authors
+FOR EACH ROW EXECUTE FUNCTION record_author_event();
+
+-- outbox holds domain events written in the same transaction as the change
+-- they describe, until the relay has published them.
+CREATE TABLE outbox (
+  id           text        PRIMARY KEY,
+  topic        text        NOT NULL,
+  payload      bytea       NOT NULL,
+  created_at   timestamptz NOT NULL DEFAULT now(),
+  published_at timestamptz
+);
+
+CREATE INDEX outbox_unpublished ON outbox (created_at) WHERE published_at IS NULL;
+
+-- Row level security keeps tenants apart. The service runs every
+-- transaction as bookstore_tenant with app.tenant_id set to the tenant of the
+-- call, so the policies apply even when it logs in as the table owner or a
+-- superuser. The outbox is only read by the relay and has no policy, the
+-- events carry their tenant. Roles are shared by every database of the
+-- cluster, so the role may exist already.
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT FROM pg_roles WHERE rolname = 'bookstore_tenant') THEN
+    CREATE ROLE bookstore_tenant NOLOGIN;
+  END IF;
+  IF NOT pg_has_role(CURRENT_USER, 'bookstore_tenant', 'MEMBER') THEN
+    GRANT bookstore_tenant TO CURRENT_USER;
+  END IF;
+END
+$$;
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON authors TO bookstore_tenant;
+GRANT SELECT, INSERT ON author_events TO bookstore_tenant;
+GRANT INSERT ON outbox TO bookstore_tenant;
+GRANT USAGE ON SEQUENCE authors_id_seq, author_events_id_seq TO bookstore_tenant;
+
+ALTER TABLE authors ENABLE ROW LEVEL SECURITY;
+ALTER TABLE author_events ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY authors_tenant_isolation ON authors
+  USING (tenant_id = current_setting('app.tenant_id'));
+
+CREATE POLICY author_events_tenant_isolation ON author_events
+  USING (tenant_id = current_setting('app.tenant_id'));
@@ -7,4 +7,7 @@ var (
 	ErrInvalidTitle         = errors.New("books: invalid title")
 	ErrInvalidAuthorID      = errors.New("books: invalid author id")
 	ErrInvalidPublishedDate = errors.New("books: invalid published date")
+	ErrInvalidPrice         = errors.New("books: invalid price")
+	ErrBookNotFound         = errors.New("books: book not found")
+	ErrConflict             = errors.New("books: too many concurrent changes")
 )
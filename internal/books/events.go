@@ -0,0 +1,138 @@
+package books
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	v1 "github.com/iho/bookstore/protos/gen/books/v1"
+	redis "github.com/redis/go-redis/v9"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// booksEventsMaxLen bounds the stream; clients resuming from an older
+	// token only get the events that are still retained.
+	booksEventsMaxLen = 10000
+	watchBatchSize    = 100
+	watchBlock        = 5 * time.Second
+)
+
+// addEvent queues a change event on pipe. It is meant to be used inside
+// TxPipelined so the event is written atomically with the change itself.
+func (bs *BooksService) addEvent(ctx context.Context, pipe redis.Pipeliner, keys Keys, eventType v1.EventType, book *v1.Book) error {
+	data, err := proto.Marshal(book)
+	if err != nil {
+		return fmt.Errorf("failed to encode book event: %w", err)
+	}
+
+	pipe.XAdd(ctx, &redis.XAddArgs{
+		Stream: keys.events(),
+		MaxLen: booksEventsMaxLen,
+		Approx: true,
+		Values: map[string]any{
+			"type": int32(eventType),
+			"book": data,
+		},
+	})
+	return nil
+}
+
+func (bs *BooksService) WatchBooks(ctx context.Context, req *connect.Request[v1.WatchBooksRequest], stream *connect.ServerStream[v1.WatchBooksResponse]) error {
+	keys, err := bs.tenantKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	lastID := req.Msg.GetResumeToken()
+	if lastID == "" {
+		id, err := bs.lastEventID(ctx, keys)
+		if err != nil {
+			return err
+		}
+		lastID = id
+	} else if !validStreamID(lastID) {
+		return connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("invalid resume token: %q", lastID))
+	}
+
+	for {
+		streams, err := bs.rdb.XRead(ctx, &redis.XReadArgs{
+			Streams: []string{keys.events(), lastID},
+			Count:   watchBatchSize,
+			Block:   watchBlock,
+		}).Result()
+		if ctx.Err() != nil {
+			return nil
+		}
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read book events: %w", err)
+		}
+
+		for _, s := range streams {
+			for _, msg := range s.Messages {
+				res, err := eventFromMessage(msg)
+				if err != nil {
+					return err
+				}
+				if err := stream.Send(res); err != nil {
+					return err
+				}
+				lastID = msg.ID
+			}
+		}
+	}
+}
+
+// lastEventID returns the ID of the newest event, so a watch without a resume
+// token starts right after it.
+func (bs *BooksService) lastEventID(ctx context.Context, keys Keys) (string, error) {
+	msgs, err := bs.rdb.XRevRangeN(ctx, keys.events(), "+", "-", 1).Result()
+	if err != nil {
+		return "", fmt.Errorf("failed to read last book event: %w", err)
+	}
+	if len(msgs) == 0 {
+		return "0-0", nil
+	}
+	return msgs[0].ID, nil
+}
+
+func eventFromMessage(msg redis.XMessage) (*v1.WatchBooksResponse, error) {
+	eventType, _ := msg.Values["type"].(string)
+	data, _ := msg.Values["book"].(string)
+
+	t, err := strconv.ParseInt(eventType, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse book event type: [id=%s] %w", msg.ID, err)
+	}
+
+	book := new(v1.Book)
+	if err := proto.Unmarshal([]byte(data), book); err != nil {
+		return nil, fmt.Errorf("failed to decode book event: [id=%s] %w", msg.ID, err)
+	}
+
+	return &v1.WatchBooksResponse{
+		Type:        v1.EventType(t),
+		Book:        book,
+		ResumeToken: msg.ID,
+	}, nil
+}
+
+// validStreamID reports whether id looks like a Redis stream entry ID.
+func validStreamID(id string) bool {
+	ms, seq, ok := strings.Cut(id, "-")
+	if !ok {
+		return false
+	}
+	if _, err := strconv.ParseUint(ms, 10, 64); err != nil {
+		return false
+	}
+	_, err := strconv.ParseUint(seq, 10, 64)
+	return err == nil
+}
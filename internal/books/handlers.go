@@ -4,39 +4,87 @@ import (
 	"bytes"
 	"context"
 	"encoding/gob"
+	"errors"
 	"fmt"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"connectrpc.com/connect"
+	"github.com/iho/bookstore/internal/idempotency"
+	"github.com/iho/bookstore/internal/money"
+	"github.com/iho/bookstore/internal/tenant"
 	v1 "github.com/iho/bookstore/protos/gen/books/v1"
+	eventsv1 "github.com/iho/bookstore/protos/gen/events/v1"
+	moneyv1 "github.com/iho/bookstore/protos/gen/money/v1"
 	redis "github.com/redis/go-redis/v9"
 )
 
 const (
-	booksKey          = "books"
-	booksIDCounterKey = "books_id"
-	JSONDateFormat    = "2006-01-02T15:04:05.000Z"
+	JSONDateFormat = "2006-01-02T15:04:05.000Z"
+	// maxTxAttempts bounds the retries of transactions that lost a race.
+	maxTxAttempts = 10
 )
 
 type BooksService struct {
-	rdb *redis.Client
+	rdb         redis.UniversalClient
+	keys        Keys
+	idempotency idempotency.Store
+	// currency is the currency of the store.
+	currency string
+	// registered holds the tenants known to be in the tenants set.
+	registered sync.Map
 }
 
-func NewBooksService(rdb *redis.Client) *BooksService {
+// NewBooksService creates the service. Prices without a currency are in
+// currency. idem may be nil, in which case idempotency keys are ignored.
+func NewBooksService(rdb redis.UniversalClient, keys Keys, idem idempotency.Store, currency string) *BooksService {
 	return &BooksService{
-		rdb: rdb,
+		rdb:         rdb,
+		keys:        keys,
+		idempotency: idem,
+		currency:    currency,
 	}
 }
 
+// tenantKeys returns the keys of the tenant of ctx.
+func (bs *BooksService) tenantKeys(ctx context.Context) (Keys, error) {
+	id, err := tenant.Require(ctx)
+	if err != nil {
+		return Keys{}, err
+	}
+	return bs.keys.Tenant(id), nil
+}
+
+// writeKeys is tenantKeys for calls that write events. It adds the tenant
+// to the tenants set first, so that the relay finds its outbox.
+func (bs *BooksService) writeKeys(ctx context.Context) (Keys, error) {
+	id, err := tenant.Require(ctx)
+	if err != nil {
+		return Keys{}, err
+	}
+	if _, ok := bs.registered.Load(id); !ok {
+		if err := bs.rdb.SAdd(ctx, bs.keys.tenants(), id).Err(); err != nil {
+			return Keys{}, fmt.Errorf("failed to register tenant: %w", err)
+		}
+		bs.registered.Store(id, struct{}{})
+	}
+	return bs.keys.Tenant(id), nil
+}
+
 func (bs *BooksService) ListBooks(ctx context.Context, req *connect.Request[v1.ListBooksRequest]) (*connect.Response[v1.ListBooksResponse], error) {
+	keys, err := bs.tenantKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
 	ids := make([]string, 0, len(req.Msg.GetIds()))
 	for _, redisId := range req.Msg.GetIds() {
 		id, err := strconv.ParseInt(redisId, 10, 64)
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse ID: [id=%s] %w", redisId, err)
 		}
-		ids = append(ids, newBookID(id))
+		ids = append(ids, keys.book(id))
 	}
 	redisBooks, err := bs.rdb.MGet(ctx, ids...).Result()
 	if err != nil {
@@ -61,6 +109,7 @@ func (bs *BooksService) ListBooks(ctx context.Context, req *connect.Request[v1.L
 			Title:         bookObj.Title,
 			AuthorId:      strconv.FormatInt(bookObj.AuthorID, 10),
 			PublishedDate: bookObj.PublishedDate.Format(time.RFC3339),
+			Price:         bookObj.price(bs.currency).Proto(),
 		})
 	}
 
@@ -76,7 +125,14 @@ func (bs *BooksService) GetBook(ctx context.Context, req *connect.Request[v1.Get
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse ID: %w", err)
 	}
-	book, err := bs.rdb.Get(ctx, newBookID(id)).Result()
+	keys, err := bs.tenantKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	book, err := bs.rdb.Get(ctx, keys.book(id)).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("%w: [id=%d]", ErrBookNotFound, id))
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get book: [id=%d] %w", id, err)
 	}
@@ -93,12 +149,17 @@ func (bs *BooksService) GetBook(ctx context.Context, req *connect.Request[v1.Get
 				Title:         bookObj.Title,
 				AuthorId:      strconv.FormatInt(bookObj.AuthorID, 10),
 				PublishedDate: bookObj.PublishedDate.Format(time.RFC3339),
+				Price:         bookObj.price(bs.currency).Proto(),
 			},
 		},
 	}, nil
 }
 
 func (bs *BooksService) CreateBook(ctx context.Context, req *connect.Request[v1.CreateBookRequest]) (*connect.Response[v1.CreateBookResponse], error) {
+	return idempotency.Handle(ctx, bs.idempotency, req, bs.createBook)
+}
+
+func (bs *BooksService) createBook(ctx context.Context, req *connect.Request[v1.CreateBookRequest]) (*connect.Response[v1.CreateBookResponse], error) {
 	authorID, err := strconv.ParseInt(req.Msg.AuthorId, 10, 64)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse author ID: [author_id=%d] %w", authorID, err)
@@ -109,35 +170,101 @@ func (bs *BooksService) CreateBook(ctx context.Context, req *connect.Request[v1.
 		return nil, fmt.Errorf("failed to parse published date: [published_date=%s] %w", req.Msg.PublishedDate, err)
 	}
 
-	bookID := bs.GetNewRedisID()
-
-	book, err := NewBook(bookID, req.Msg.Title, authorID, publishedDate)
+	price, err := bs.parsePrice(req.Msg.Price)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create book: %w", err)
+		return nil, err
 	}
 
-	var buf bytes.Buffer
-	if err := gob.NewEncoder(&buf).Encode(book); err != nil {
-		return nil, fmt.Errorf("failed to encode book: %w", err)
+	keys, err := bs.writeKeys(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := bs.rdb.Set(ctx, newBookID(book.ID), buf.Bytes(), 0).Err(); err != nil {
-		return nil, fmt.Errorf("failed to save book: [id=%d] %w", book.ID, err)
+	// the ID is only taken when the book is written, so failed creates leave
+	// no gaps and concurrent creates retry with the next ID
+	var pbBook *v1.Book
+	err = bs.watch(ctx, func(tx *redis.Tx) error {
+		lastID, err := tx.Get(ctx, keys.idCounter()).Int64()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return fmt.Errorf("failed to read book ID counter: %w", err)
+		}
+
+		id := lastID + 1
+		key := keys.book(id)
+		exists, err := watchBook(ctx, tx, key)
+		if err != nil {
+			return err
+		}
+		if exists {
+			// the counter is behind the books, e.g. after it was reset, so
+			// it skips past the highest ID taken rather than overwrite a book
+			highest, err := bs.highestBookID(ctx, keys)
+			if err != nil {
+				return err
+			}
+			id = max(id, highest) + 1
+			key = keys.book(id)
+			if exists, err = watchBook(ctx, tx, key); err != nil {
+				return err
+			}
+			if exists {
+				// written since the scan, start over
+				return redis.TxFailedErr
+			}
+		}
+
+		book, err := NewBook(id, req.Msg.Title, authorID, publishedDate, price)
+		if err != nil {
+			return fmt.Errorf("failed to create book: %w", err)
+		}
+
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(book); err != nil {
+			return fmt.Errorf("failed to encode book: %w", err)
+		}
+
+		pbBook = &v1.Book{
+			Id:            strconv.FormatInt(book.ID, 10),
+			Title:         book.Title,
+			AuthorId:      strconv.FormatInt(book.AuthorID, 10),
+			PublishedDate: book.PublishedDate.Format(time.RFC3339),
+			Price:         book.price(bs.currency).Proto(),
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, keys.idCounter(), book.ID, 0)
+			pipe.Set(ctx, key, buf.Bytes(), 0)
+			pipe.Incr(ctx, keys.count())
+			if err := bs.addEvent(ctx, pipe, keys, v1.EventType_EVENT_TYPE_CREATED, pbBook); err != nil {
+				return err
+			}
+			return bs.addDomainEvent(ctx, pipe, keys, pbBook.Id, &eventsv1.BookCreated{
+				BookId:        pbBook.Id,
+				Title:         pbBook.Title,
+				AuthorId:      pbBook.AuthorId,
+				PublishedDate: pbBook.PublishedDate,
+				Price:         pbBook.Price,
+			})
+		})
+		return err
+	}, keys.idCounter())
+	if err != nil {
+		return nil, fmt.Errorf("failed to save book: %w", err)
 	}
 
 	return &connect.Response[v1.CreateBookResponse]{
 		Msg: &v1.CreateBookResponse{
-			Book: &v1.Book{
-				Id:            strconv.FormatInt(book.ID, 10),
-				Title:         book.Title,
-				AuthorId:      strconv.FormatInt(book.AuthorID, 10),
-				PublishedDate: book.PublishedDate.Format(time.RFC3339),
-			},
+			Book: pbBook,
 		},
 	}, nil
 }
 
 func (bs *BooksService) UpdateBook(ctx context.Context, req *connect.Request[v1.UpdateBookRequest]) (*connect.Response[v1.UpdateBookResponse], error) {
+	id, err := strconv.ParseInt(req.Msg.Id, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ID: %w", err)
+	}
+
 	authorID, err := strconv.ParseInt(req.Msg.AuthorId, 10, 64)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse author ID: [author_id=%d] %w", authorID, err)
@@ -148,30 +275,82 @@ func (bs *BooksService) UpdateBook(ctx context.Context, req *connect.Request[v1.
 		return nil, fmt.Errorf("failed to parse published date: [published_date=%s] %w", req.Msg.PublishedDate, err)
 	}
 
-	bookID := bs.GetNewRedisID()
+	// an update without a price keeps the stored one
+	var price *money.Money
+	if req.Msg.Price != nil {
+		p, err := bs.parsePrice(req.Msg.Price)
+		if err != nil {
+			return nil, err
+		}
+		price = &p
+	}
 
-	book, err := NewBook(bookID, req.Msg.Title, authorID, publishedDate)
+	keys, err := bs.writeKeys(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create book: %w", err)
+		return nil, err
 	}
 
-	var buf bytes.Buffer
-	if err := gob.NewEncoder(&buf).Encode(book); err != nil {
-		return nil, fmt.Errorf("failed to encode book: %w", err)
-	}
+	key := keys.book(id)
+	var pbBook *v1.Book
+	err = bs.watch(ctx, func(tx *redis.Tx) error {
+		stored, err := tx.Get(ctx, key).Bytes()
+		if errors.Is(err, redis.Nil) {
+			return connect.NewError(connect.CodeNotFound, ErrBookNotFound)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get book: %w", err)
+		}
 
-	if err := bs.rdb.Set(ctx, newBookID(book.ID), buf.Bytes(), 0).Err(); err != nil {
-		return nil, fmt.Errorf("failed to set book: [id=%d] %w", book.ID, err)
+		newPrice := price
+		if newPrice == nil {
+			var storedBook Book
+			if err := gob.NewDecoder(bytes.NewReader(stored)).Decode(&storedBook); err != nil {
+				return fmt.Errorf("failed to decode book: %w", err)
+			}
+			p := storedBook.price(bs.currency)
+			newPrice = &p
+		}
+
+		book, err := NewBook(id, req.Msg.Title, authorID, publishedDate, *newPrice)
+		if err != nil {
+			return fmt.Errorf("failed to create book: %w", err)
+		}
+
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(book); err != nil {
+			return fmt.Errorf("failed to encode book: %w", err)
+		}
+
+		pbBook = &v1.Book{
+			Id:            strconv.FormatInt(book.ID, 10),
+			Title:         book.Title,
+			AuthorId:      strconv.FormatInt(book.AuthorID, 10),
+			PublishedDate: book.PublishedDate.Format(time.RFC3339),
+			Price:         book.price(bs.currency).Proto(),
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, key, buf.Bytes(), 0)
+			if err := bs.addEvent(ctx, pipe, keys, v1.EventType_EVENT_TYPE_UPDATED, pbBook); err != nil {
+				return err
+			}
+			return bs.addDomainEvent(ctx, pipe, keys, pbBook.Id, &eventsv1.BookUpdated{
+				BookId:        pbBook.Id,
+				Title:         pbBook.Title,
+				AuthorId:      pbBook.AuthorId,
+				PublishedDate: pbBook.PublishedDate,
+				Price:         pbBook.Price,
+			})
+		})
+		return err
+	}, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set book: [id=%d] %w", id, err)
 	}
 
 	return &connect.Response[v1.UpdateBookResponse]{
 		Msg: &v1.UpdateBookResponse{
-			Book: &v1.Book{
-				Id:            strconv.FormatInt(book.ID, 10),
-				Title:         book.Title,
-				AuthorId:      strconv.FormatInt(book.AuthorID, 10),
-				PublishedDate: book.PublishedDate.Format(time.RFC3339),
-			},
+			Book: pbBook,
 		},
 	}, nil
 }
@@ -182,21 +361,122 @@ func (bs *BooksService) DeleteBook(ctx context.Context, req *connect.Request[v1.
 		return nil, fmt.Errorf("failed to parse ID: %w", err)
 	}
 
-	if err := bs.rdb.Del(ctx, newBookID(id)).Err(); err != nil {
+	keys, err := bs.writeKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key := keys.book(id)
+	var deleted bool
+	err = bs.watch(ctx, func(tx *redis.Tx) error {
+		exists, err := tx.Exists(ctx, key).Result()
+		if err != nil {
+			return fmt.Errorf("failed to check book: %w", err)
+		}
+		deleted = exists > 0
+		if !deleted {
+			return nil
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Del(ctx, key)
+			pipe.Decr(ctx, keys.count())
+			if err := bs.addEvent(ctx, pipe, keys, v1.EventType_EVENT_TYPE_DELETED, &v1.Book{Id: strconv.FormatInt(id, 10)}); err != nil {
+				return err
+			}
+			return bs.addDomainEvent(ctx, pipe, keys, strconv.FormatInt(id, 10), &eventsv1.BookDeleted{
+				BookId: strconv.FormatInt(id, 10),
+			})
+		})
+		return err
+	}, key)
+	if err != nil {
 		return nil, fmt.Errorf("failed to delete book: [id=%s] %w", req.Msg.Id, err)
 	}
 
 	return &connect.Response[v1.DeleteBookResponse]{
 		Msg: &v1.DeleteBookResponse{
-			Status: true,
+			Status: deleted,
 		},
 	}, nil
 }
 
-func (bs *BooksService) GetNewRedisID() int64 {
-	return bs.rdb.Incr(context.Background(), booksIDCounterKey).Val()
+// parsePrice reads the price of a request, which defaults to 0 in the
+// currency of the store.
+func (bs *BooksService) parsePrice(msg *moneyv1.Money) (money.Money, error) {
+	price, err := money.FromProto(msg, bs.currency)
+	if err != nil {
+		return money.Money{}, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("%w: %w", ErrInvalidPrice, err))
+	}
+	return price, nil
+}
+
+// ScanBooks calls fn for every book of the tenant of ctx in no particular
+// order.
+func (bs *BooksService) ScanBooks(ctx context.Context, fn func(*Book) error) error {
+	keys, err := bs.tenantKeys(ctx)
+	if err != nil {
+		return err
+	}
+	return bs.scanBooks(ctx, keys, fn)
+}
+
+func (bs *BooksService) scanBooks(ctx context.Context, keys Keys, fn func(*Book) error) error {
+	return scanKeys(ctx, bs.rdb, keys.bookPattern(), func(key string) error {
+		book, err := bs.rdb.Get(ctx, key).Result()
+		if errors.Is(err, redis.Nil) {
+			// deleted since the key was scanned
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get book: [key=%s] %w", key, err)
+		}
+
+		var bookObj Book
+		if err := gob.NewDecoder(bytes.NewReader([]byte(book))).Decode(&bookObj); err != nil {
+			return fmt.Errorf("failed to decode book: [key=%s] %w", key, err)
+		}
+
+		return fn(&bookObj)
+	})
+}
+
+// watchBook adds the book at key to the keys watched by tx and reports
+// whether it exists.
+func watchBook(ctx context.Context, tx *redis.Tx, key string) (bool, error) {
+	if err := tx.Watch(ctx, key).Err(); err != nil {
+		return false, fmt.Errorf("failed to watch book: [key=%s] %w", key, err)
+	}
+	exists, err := tx.Exists(ctx, key).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check book: [key=%s] %w", key, err)
+	}
+	return exists > 0, nil
+}
+
+// highestBookID returns the highest ID of the books of keys, 0 if there
+// are none.
+func (bs *BooksService) highestBookID(ctx context.Context, keys Keys) (int64, error) {
+	var highest int64
+	err := scanKeys(ctx, bs.rdb, keys.bookPattern(), func(key string) error {
+		id, err := strconv.ParseInt(key[strings.LastIndexByte(key, ':')+1:], 10, 64)
+		if err != nil {
+			return fmt.Errorf("failed to parse book ID: [key=%s] %w", key, err)
+		}
+		highest = max(highest, id)
+		return nil
+	})
+	return highest, err
 }
 
-func newBookID(id int64) string {
-	return booksKey + ":" + strconv.FormatInt(id, 10)
+// watch runs fn in an optimistic transaction on keys, retrying it when
+// another client changed them in between.
+func (bs *BooksService) watch(ctx context.Context, fn func(tx *redis.Tx) error, keys ...string) error {
+	for attempt := 0; attempt < maxTxAttempts; attempt++ {
+		err := bs.rdb.Watch(ctx, fn, keys...)
+		if !errors.Is(err, redis.TxFailedErr) {
+			return err
+		}
+	}
+	return connect.NewError(connect.CodeAborted, ErrConflict)
 }
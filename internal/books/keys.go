@@ -0,0 +1,97 @@
+package books
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	redis "github.com/redis/go-redis/v9"
+)
+
+// Keys names the Redis keys of the service. Every key of a tenant carries
+// the same hash tag, so that MGET and the transactions that write a book
+// together with its events stay on one slot of a Redis Cluster. The prefix
+// lets several environments share one Redis.
+type Keys struct {
+	prefix string
+	tag    string
+}
+
+func NewKeys(prefix string) Keys {
+	return Keys{prefix: prefix, tag: "{" + prefix + "books}"}
+}
+
+// Tenant returns the keys of the tenant with id, which has its own books,
+// ID counter, event stream and outbox.
+func (k Keys) Tenant(id string) Keys {
+	return Keys{prefix: k.prefix, tag: "{" + k.prefix + "books:" + id + "}"}
+}
+
+// tenants is the set of tenants that wrote books, for the relay and the
+// catalog metrics, which work across tenants.
+func (k Keys) tenants() string {
+	return "{" + k.prefix + "books}:tenants"
+}
+
+func (k Keys) book(id int64) string {
+	return k.tag + ":book:" + strconv.FormatInt(id, 10)
+}
+
+// bookPattern matches every book key in SCAN.
+func (k Keys) bookPattern() string {
+	return escapePattern(k.tag) + ":book:*"
+}
+
+func (k Keys) idCounter() string {
+	return k.tag + ":next_id"
+}
+
+// count is the number of books of the tenant, for the catalog metrics.
+func (k Keys) count() string {
+	return k.tag + ":count"
+}
+
+func (k Keys) events() string {
+	return k.tag + ":events"
+}
+
+func (k Keys) outbox() string {
+	return k.tag + ":outbox"
+}
+
+// escapePattern quotes the glob characters of s for MATCH.
+func escapePattern(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(`*?[]\`, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// scanKeys calls fn for every key matching pattern. A cluster is scanned
+// on every master, SCAN only covers the node it is sent to.
+func scanKeys(ctx context.Context, rdb redis.UniversalClient, pattern string, fn func(key string) error) error {
+	scan := func(ctx context.Context, node redis.UniversalClient) error {
+		iter := node.Scan(ctx, 0, pattern, 100).Iterator()
+		for iter.Next(ctx) {
+			if err := fn(iter.Val()); err != nil {
+				return err
+			}
+		}
+		if err := iter.Err(); err != nil {
+			return fmt.Errorf("failed to scan keys: %w", err)
+		}
+		return nil
+	}
+
+	if cluster, ok := rdb.(*redis.ClusterClient); ok {
+		return cluster.ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+			return scan(ctx, node)
+		})
+	}
+	return scan(ctx, rdb)
+}
@@ -0,0 +1,118 @@
+package books
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	redis "github.com/redis/go-redis/v9"
+)
+
+const catalogStatsTimeout = 10 * time.Second
+
+var booksDesc = prometheus.NewDesc(
+	"bookstore_books",
+	"Number of books in the catalog.",
+	nil, nil,
+)
+
+// CatalogCollector exports catalog gauges across tenants. It reads the book
+// count every tenant keeps next to its books, so a scrape costs a read per
+// tenant rather than per book.
+type CatalogCollector struct {
+	bs *BooksService
+}
+
+func NewCatalogCollector(bs *BooksService) *CatalogCollector {
+	return &CatalogCollector{bs: bs}
+}
+
+func (c *CatalogCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- booksDesc
+}
+
+func (c *CatalogCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), catalogStatsTimeout)
+	defer cancel()
+
+	total, err := c.bs.countAllBooks(ctx)
+	if err != nil {
+		slog.Error("failed to collect catalog metrics", "error", err)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(booksDesc, prometheus.GaugeValue, float64(total))
+}
+
+// countAllBooks sums the book counts of every tenant.
+func (bs *BooksService) countAllBooks(ctx context.Context) (int64, error) {
+	tenants, err := bs.rdb.SMembers(ctx, bs.keys.tenants()).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list tenants: %w", err)
+	}
+
+	cmds, err := bs.rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, id := range tenants {
+			pipe.Get(ctx, bs.keys.Tenant(id).count())
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return 0, fmt.Errorf("failed to get book counts: %w", err)
+	}
+
+	var total int64
+	for _, cmd := range cmds {
+		n, err := cmd.(*redis.StringCmd).Int64()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return 0, fmt.Errorf("failed to parse book count: %w", err)
+		}
+		total += n
+	}
+	return total, nil
+}
+
+// EnsureCounts counts the books of the tenants that were stored before
+// their book count was kept. It is meant to run at startup, writes keep the
+// counts up to date afterwards.
+func (bs *BooksService) EnsureCounts(ctx context.Context) error {
+	tenants, err := bs.rdb.SMembers(ctx, bs.keys.tenants()).Result()
+	if err != nil {
+		return fmt.Errorf("failed to list tenants: %w", err)
+	}
+
+	for _, id := range tenants {
+		keys := bs.keys.Tenant(id)
+		err := bs.watch(ctx, func(tx *redis.Tx) error {
+			exists, err := tx.Exists(ctx, keys.count()).Result()
+			if err != nil {
+				return fmt.Errorf("failed to check book count: %w", err)
+			}
+			if exists > 0 {
+				return nil
+			}
+
+			count := 0
+			err = scanKeys(ctx, bs.rdb, keys.bookPattern(), func(string) error {
+				count++
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+			// a create in between changes the count and retries the scan
+			_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				pipe.Set(ctx, keys.count(), count, 0)
+				return nil
+			})
+			return err
+		}, keys.count())
+		if err != nil {
+			return fmt.Errorf("failed to count books: [tenant=%s] %w", id, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package books
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	redis "github.com/redis/go-redis/v9"
+)
+
+// Keys of the books stored before keys were hash-tagged and scoped to
+// tenants.
+const (
+	legacyBookPrefix = "books:"
+	legacyIDCounter  = "books_id"
+)
+
+// MigrateLegacyKeys moves the books stored before keys were hash-tagged and
+// scoped to tenants, books:<id> and the books_id counter, to the tenant with
+// id. Books keep their IDs; a book whose ID the tenant already uses is left
+// in place and logged. It is meant to run at startup, after EnsureCounts,
+// and does nothing once the legacy keys are gone.
+func (bs *BooksService) MigrateLegacyKeys(ctx context.Context, id string) error {
+	keys := bs.keys.Tenant(id)
+	registered := false
+
+	err := scanKeys(ctx, bs.rdb, escapePattern(legacyBookPrefix)+"*", func(legacy string) error {
+		bookID, err := strconv.ParseInt(strings.TrimPrefix(legacy, legacyBookPrefix), 10, 64)
+		if err != nil {
+			slog.WarnContext(ctx, "skipped legacy key that is not a book", "key", legacy)
+			return nil
+		}
+		data, err := bs.rdb.Get(ctx, legacy).Bytes()
+		if errors.Is(err, redis.Nil) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get legacy book: [key=%s] %w", legacy, err)
+		}
+
+		if !registered {
+			if err := bs.rdb.SAdd(ctx, bs.keys.tenants(), id).Err(); err != nil {
+				return fmt.Errorf("failed to register tenant: %w", err)
+			}
+			registered = true
+		}
+
+		key := keys.book(bookID)
+		var taken bool
+		err = bs.watch(ctx, func(tx *redis.Tx) error {
+			if taken, err = watchBook(ctx, tx, key); err != nil || taken {
+				return err
+			}
+			_, err := tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				pipe.Set(ctx, key, data, 0)
+				pipe.Incr(ctx, keys.count())
+				return nil
+			})
+			return err
+		}, key)
+		if err != nil {
+			return fmt.Errorf("failed to migrate legacy book: [key=%s] %w", legacy, err)
+		}
+		if taken {
+			slog.WarnContext(ctx, "left legacy book in place, its ID is taken", "key", legacy, "tenant", id)
+			return nil
+		}
+
+		if err := bs.rdb.Del(ctx, legacy).Err(); err != nil {
+			return fmt.Errorf("failed to delete legacy book: [key=%s] %w", legacy, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return bs.migrateLegacyIDCounter(ctx, keys)
+}
+
+// migrateLegacyIDCounter moves the ID counter of keys up to the legacy one,
+// so that new books do not reuse the IDs of deleted legacy books.
+func (bs *BooksService) migrateLegacyIDCounter(ctx context.Context, keys Keys) error {
+	legacyID, err := bs.rdb.Get(ctx, legacyIDCounter).Int64()
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read legacy book ID counter: %w", err)
+	}
+
+	err = bs.watch(ctx, func(tx *redis.Tx) error {
+		lastID, err := tx.Get(ctx, keys.idCounter()).Int64()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return fmt.Errorf("failed to read book ID counter: %w", err)
+		}
+		if lastID >= legacyID {
+			return nil
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, keys.idCounter(), legacyID, 0)
+			return nil
+		})
+		return err
+	}, keys.idCounter())
+	if err != nil {
+		return fmt.Errorf("failed to migrate legacy book ID counter: %w", err)
+	}
+
+	if err := bs.rdb.Del(ctx, legacyIDCounter).Err(); err != nil {
+		return fmt.Errorf("failed to delete legacy book ID counter: %w", err)
+	}
+	return nil
+}
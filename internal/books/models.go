@@ -1,15 +1,25 @@
 package books
 
-import "time"
+import (
+	"time"
+
+	"github.com/iho/bookstore/internal/money"
+)
 
 type Book struct {
 	ID            int64
 	Title         string
 	AuthorID      int64
 	PublishedDate time.Time
+	// Price is the unit price in minor units of Currency, 0 when not for
+	// sale.
+	Price int64
+	// Currency is empty for books stored before prices had currencies, they
+	// are in the currency of the store.
+	Currency string
 }
 
-func NewBook(id int64, title string, authorID int64, publishedDate time.Time) (*Book, error) {
+func NewBook(id int64, title string, authorID int64, publishedDate time.Time, price money.Money) (*Book, error) {
 	if id == 0 {
 		return nil, ErrInvalidID
 	}
@@ -22,11 +32,25 @@ func NewBook(id int64, title string, authorID int64, publishedDate time.Time) (*
 	if publishedDate.IsZero() {
 		return nil, ErrInvalidPublishedDate
 	}
+	if price.Amount < 0 {
+		return nil, ErrInvalidPrice
+	}
 
 	return &Book{
 		ID:            id,
 		Title:         title,
 		AuthorID:      authorID,
 		PublishedDate: publishedDate,
+		Price:         price.Amount,
+		Currency:      price.Currency,
 	}, nil
 }
+
+// price returns the price of the book, in currency if it predates
+// currencies.
+func (b *Book) price(currency string) money.Money {
+	if b.Currency != "" {
+		currency = b.Currency
+	}
+	return money.New(b.Price, currency)
+}
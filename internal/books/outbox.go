@@ -0,0 +1,90 @@
+package books
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+
+	"github.com/iho/bookstore/internal/events"
+	redis "github.com/redis/go-redis/v9"
+	"google.golang.org/protobuf/proto"
+)
+
+// addDomainEvent queues a domain event about book id on the outbox stream.
+// Like addEvent it is meant to be used inside TxPipelined.
+func (bs *BooksService) addDomainEvent(ctx context.Context, pipe redis.Pipeliner, keys Keys, id string, payload proto.Message) error {
+	msg, err := events.NewMessage(ctx, events.TopicBooks, id, payload)
+	if err != nil {
+		return err
+	}
+
+	pipe.XAdd(ctx, &redis.XAddArgs{
+		Stream: keys.outbox(),
+		Values: map[string]any{
+			"id":      msg.ID,
+			"topic":   msg.Topic,
+			"payload": msg.Payload,
+		},
+	})
+	return nil
+}
+
+// OutboxStore reads the outbox streams of every tenant for events.Relay.
+// Published entries are deleted from the streams.
+type OutboxStore struct {
+	rdb  redis.UniversalClient
+	keys Keys
+}
+
+func NewOutboxStore(rdb redis.UniversalClient, keys Keys) *OutboxStore {
+	return &OutboxStore{rdb: rdb, keys: keys}
+}
+
+// Fetch visits the tenants in random order, so that a busy tenant cannot
+// hold back the events of the others. Entry keys are the tenant and the
+// stream entry ID.
+func (s *OutboxStore) Fetch(ctx context.Context, limit int) ([]events.OutboxEntry, error) {
+	tenants, err := s.rdb.SMembers(ctx, s.keys.tenants()).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tenants: %w", err)
+	}
+	rand.Shuffle(len(tenants), func(i, j int) {
+		tenants[i], tenants[j] = tenants[j], tenants[i]
+	})
+
+	var entries []events.OutboxEntry
+	for _, id := range tenants {
+		if len(entries) >= limit {
+			break
+		}
+		msgs, err := s.rdb.XRangeN(ctx, s.keys.Tenant(id).outbox(), "-", "+", int64(limit-len(entries))).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read outbox: [tenant=%s] %w", id, err)
+		}
+
+		for _, msg := range msgs {
+			entry := events.OutboxEntry{Key: id + "/" + msg.ID}
+			entry.Message.ID, _ = msg.Values["id"].(string)
+			entry.Message.Topic, _ = msg.Values["topic"].(string)
+			payload, _ := msg.Values["payload"].(string)
+			entry.Message.Payload = []byte(payload)
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (s *OutboxStore) MarkPublished(ctx context.Context, entries []events.OutboxEntry) error {
+	ids := make(map[string][]string)
+	for _, entry := range entries {
+		tenantID, id, _ := strings.Cut(entry.Key, "/")
+		ids[tenantID] = append(ids[tenantID], id)
+	}
+	for tenantID, ids := range ids {
+		if err := s.rdb.XDel(ctx, s.keys.Tenant(tenantID).outbox(), ids...).Err(); err != nil {
+			return fmt.Errorf("failed to delete outbox entries: [tenant=%s] %w", tenantID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+// Package caller authenticates the calls the gateway and the services make
+// to each other, and carries the verified subject of the end user along
+// with them. Services only trust what a call says about its end user when it
+// carries the internal token they share with their callers.
+package caller
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+
+	"connectrpc.com/connect"
+)
+
+// SubjectHeader carries the subject of the end user of a call.
+const SubjectHeader = "X-Subject"
+
+var (
+	ErrUntrusted = errors.New("caller: missing or invalid internal token")
+	ErrNoToken   = errors.New("caller: INTERNAL_TOKEN must be set, or INTERNAL_TOKEN_INSECURE=true to trust every call")
+)
+
+type (
+	subjectKey struct{}
+	trustedKey struct{}
+)
+
+// WithSubject stores the verified subject of the end user in ctx.
+func WithSubject(ctx context.Context, subject string) context.Context {
+	return context.WithValue(ctx, subjectKey{}, subject)
+}
+
+// Subject returns the verified subject stored in ctx.
+func Subject(ctx context.Context) (string, bool) {
+	subject, ok := ctx.Value(subjectKey{}).(string)
+	return subject, ok && subject != ""
+}
+
+// Trusted reports whether ctx belongs to a call made by an internal caller.
+func Trusted(ctx context.Context) bool {
+	trusted, _ := ctx.Value(trustedKey{}).(bool)
+	return trusted
+}
+
+// TokenFromEnv returns the internal token, INTERNAL_TOKEN. Without one it
+// fails with ErrNoToken, unless INTERNAL_TOKEN_INSECURE is "true": then it
+// returns an empty token, which trusts every caller and is only meant for
+// development.
+func TokenFromEnv() (string, error) {
+	token := os.Getenv("INTERNAL_TOKEN")
+	if token == "" && os.Getenv("INTERNAL_TOKEN_INSECURE") != "true" {
+		return "", ErrNoToken
+	}
+	return token, nil
+}
+
+type interceptor struct {
+	token string
+}
+
+// NewInterceptor authenticates internal calls by token. Clients send it as a
+// bearer token, along with the subject of the context. Handlers mark calls
+// carrying it as trusted and take their subject from the SubjectHeader;
+// other calls are let through untrusted, without a subject. An empty token
+// trusts every call, see TokenFromEnv.
+func NewInterceptor(token string) connect.Interceptor {
+	return interceptor{token: token}
+}
+
+func (i interceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if req.Spec().IsClient {
+			i.forward(ctx, req.Header())
+			return next(ctx, req)
+		}
+		return next(i.verify(ctx, req.Header()), req)
+	}
+}
+
+func (i interceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return func(ctx context.Context, spec connect.Spec) connect.StreamingClientConn {
+		conn := next(ctx, spec)
+		i.forward(ctx, conn.RequestHeader())
+		return conn
+	}
+}
+
+func (i interceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		return next(i.verify(ctx, conn.RequestHeader()), conn)
+	}
+}
+
+func (i interceptor) forward(ctx context.Context, header http.Header) {
+	if i.token != "" {
+		header.Set("Authorization", "Bearer "+i.token)
+	}
+	if subject, ok := Subject(ctx); ok {
+		header.Set(SubjectHeader, subject)
+	}
+}
+
+func (i interceptor) verify(ctx context.Context, header http.Header) context.Context {
+	if i.token != "" {
+		token := strings.TrimSpace(strings.TrimPrefix(header.Get("Authorization"), "Bearer "))
+		if subtle.ConstantTimeCompare([]byte(token), []byte(i.token)) != 1 {
+			return ctx
+		}
+	}
+
+	ctx = context.WithValue(ctx, trustedKey{}, true)
+	if subject := header.Get(SubjectHeader); subject != "" {
+		ctx = WithSubject(ctx, subject)
+	}
+	return ctx
+}
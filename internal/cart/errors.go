@@ -0,0 +1,15 @@
+package cart
+
+import "errors"
+
+var (
+	ErrInvalidID        = errors.New("cart: invalid id")
+	ErrInvalidQuantity  = errors.New("cart: invalid quantity")
+	ErrCartNotFound     = errors.New("cart: cart not found")
+	ErrUnknownBook      = errors.New("cart: unknown book")
+	ErrTooManyLines     = errors.New("cart: too many lines")
+	ErrCheckoutRunning  = errors.New("cart: cart is being checked out")
+	ErrEmptyCart        = errors.New("cart: cart is empty")
+	ErrUnavailableBooks = errors.New("cart: cart has books that are not for sale")
+	ErrTotalOutOfBounds = errors.New("cart: total price out of bounds")
+)
@@ -0,0 +1,339 @@
+// Package cart implements the CartService: shopping carts kept in Redis
+// that are priced against the books service and checked out into orders.
+package cart
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	"github.com/iho/bookstore/internal/idempotency"
+	"github.com/iho/bookstore/internal/money"
+	booksV1 "github.com/iho/bookstore/protos/gen/books/v1"
+	v1 "github.com/iho/bookstore/protos/gen/cart/v1"
+	ordersV1 "github.com/iho/bookstore/protos/gen/orders/v1"
+	redis "github.com/redis/go-redis/v9"
+)
+
+// BookStore is the part of the books service carts need.
+type BookStore interface {
+	GetBook(context.Context, *connect.Request[booksV1.GetBookRequest]) (*connect.Response[booksV1.GetBookResponse], error)
+}
+
+// OrderStore is the part of the orders API checkout needs. The Connect
+// client satisfies it.
+type OrderStore interface {
+	CreateOrder(context.Context, *connect.Request[ordersV1.CreateOrderRequest]) (*connect.Response[ordersV1.CreateOrderResponse], error)
+}
+
+type CartService struct {
+	store       *store
+	books       BookStore
+	orders      OrderStore
+	idempotency idempotency.Store
+	currency    string
+	rates       *money.Rates
+}
+
+// NewCartService creates the service. Carts are kept under keys starting
+// with prefix and expire ttl after their last change. Their totals are in
+// currency, converted with rates. idem may be nil, in which case
+// idempotency keys are ignored.
+func NewCartService(rdb redis.UniversalClient, prefix string, ttl time.Duration, books BookStore, orders OrderStore, idem idempotency.Store, currency string, rates *money.Rates) *CartService {
+	return &CartService{
+		store:       &store{rdb: rdb, prefix: prefix, ttl: ttl},
+		books:       books,
+		orders:      orders,
+		idempotency: idem,
+		currency:    currency,
+		rates:       rates,
+	}
+}
+
+// CreateCart creates at most one cart per Idempotency-Key header.
+func (cs *CartService) CreateCart(ctx context.Context, req *connect.Request[v1.CreateCartRequest]) (*connect.Response[v1.CreateCartResponse], error) {
+	return idempotency.Handle(ctx, cs.idempotency, req, cs.createCart)
+}
+
+func (cs *CartService) createCart(ctx context.Context, req *connect.Request[v1.CreateCartRequest]) (*connect.Response[v1.CreateCartResponse], error) {
+	id := uuid.NewString()
+	if err := cs.store.create(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return &connect.Response[v1.CreateCartResponse]{
+		Msg: &v1.CreateCartResponse{
+			Cart: &v1.Cart{
+				Id:        id,
+				ExpiresAt: time.Now().Add(cs.store.ttl).UTC().Format(time.RFC3339),
+			},
+		},
+	}, nil
+}
+
+func (cs *CartService) GetCart(ctx context.Context, req *connect.Request[v1.GetCartRequest]) (*connect.Response[v1.GetCartResponse], error) {
+	cart, err := cs.cart(ctx, req.Msg.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &connect.Response[v1.GetCartResponse]{
+		Msg: &v1.GetCartResponse{
+			Cart: cart,
+		},
+	}, nil
+}
+
+func (cs *CartService) AddItem(ctx context.Context, req *connect.Request[v1.AddItemRequest]) (*connect.Response[v1.AddItemResponse], error) {
+	if req.Msg.Quantity < 1 {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("%w: must be greater than 0", ErrInvalidQuantity))
+	}
+	bookID, err := cs.checkBook(ctx, req.Msg.BookId)
+	if err != nil {
+		return nil, err
+	}
+	if err := cs.store.update(ctx, req.Msg.CartId, bookID, req.Msg.Quantity, addQuantity); err != nil {
+		return nil, err
+	}
+
+	cart, err := cs.cart(ctx, req.Msg.CartId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &connect.Response[v1.AddItemResponse]{
+		Msg: &v1.AddItemResponse{
+			Cart: cart,
+		},
+	}, nil
+}
+
+func (cs *CartService) RemoveItem(ctx context.Context, req *connect.Request[v1.RemoveItemRequest]) (*connect.Response[v1.RemoveItemResponse], error) {
+	bookID, err := parseID(req.Msg.BookId)
+	if err != nil {
+		return nil, err
+	}
+	if err := cs.store.update(ctx, req.Msg.CartId, bookID, 0, setQuantity); err != nil {
+		return nil, err
+	}
+
+	cart, err := cs.cart(ctx, req.Msg.CartId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &connect.Response[v1.RemoveItemResponse]{
+		Msg: &v1.RemoveItemResponse{
+			Cart: cart,
+		},
+	}, nil
+}
+
+func (cs *CartService) SetItemQuantity(ctx context.Context, req *connect.Request[v1.SetItemQuantityRequest]) (*connect.Response[v1.SetItemQuantityResponse], error) {
+	if req.Msg.Quantity < 0 {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("%w: must not be negative", ErrInvalidQuantity))
+	}
+
+	var bookID int64
+	var err error
+	if req.Msg.Quantity == 0 {
+		// removing a book that was deleted in the meantime is fine
+		bookID, err = parseID(req.Msg.BookId)
+	} else {
+		bookID, err = cs.checkBook(ctx, req.Msg.BookId)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := cs.store.update(ctx, req.Msg.CartId, bookID, req.Msg.Quantity, setQuantity); err != nil {
+		return nil, err
+	}
+
+	cart, err := cs.cart(ctx, req.Msg.CartId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &connect.Response[v1.SetItemQuantityResponse]{
+		Msg: &v1.SetItemQuantityResponse{
+			Cart: cart,
+		},
+	}, nil
+}
+
+func (cs *CartService) Checkout(ctx context.Context, req *connect.Request[v1.CheckoutRequest]) (*connect.Response[v1.CheckoutResponse], error) {
+	orderDate, err := cs.store.startCheckout(ctx, req.Msg.CartId)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := cs.checkout(ctx, req.Msg, orderDate)
+	if err != nil {
+		if retryable(err) {
+			// the order may exist, a retry has to send the same request
+			return nil, err
+		}
+		if err := cs.store.cancelCheckout(context.WithoutCancel(ctx), req.Msg.CartId); err != nil {
+			slog.ErrorContext(ctx, "failed to cancel checkout", "cart_id", req.Msg.CartId, "error", err)
+		}
+		return nil, err
+	}
+
+	if err := cs.store.delete(ctx, req.Msg.CartId); err != nil {
+		// the order exists, the cart expires eventually and checking it out
+		// again returns the same order
+		slog.ErrorContext(ctx, "failed to delete checked out cart", "cart_id", req.Msg.CartId, "error", err)
+	}
+
+	return &connect.Response[v1.CheckoutResponse]{
+		Msg: &v1.CheckoutResponse{
+			Order: order,
+		},
+	}, nil
+}
+
+func (cs *CartService) checkout(ctx context.Context, msg *v1.CheckoutRequest, orderDate string) (*ordersV1.Order, error) {
+	id := msg.CartId
+	cart, err := cs.cart(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(cart.Lines) == 0 {
+		return nil, connect.NewError(connect.CodeFailedPrecondition, ErrEmptyCart)
+	}
+
+	lines := make([]*ordersV1.OrderLine, 0, len(cart.Lines))
+	for _, line := range cart.Lines {
+		if !line.Available {
+			return nil, connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("%w: [book_id=%s]", ErrUnavailableBooks, line.BookId))
+		}
+		lines = append(lines, &ordersV1.OrderLine{
+			BookId:   line.BookId,
+			Quantity: line.Quantity,
+		})
+	}
+
+	req := connect.NewRequest(&ordersV1.CreateOrderRequest{
+		OrderLines:      lines,
+		OrderDate:       orderDate,
+		CouponCode:      msg.CouponCode,
+		Currency:        msg.Currency,
+		TaxRegion:       msg.TaxRegion,
+		ShippingAddress: msg.ShippingAddress,
+		BillingAddress:  msg.BillingAddress,
+		CustomerId:      msg.CustomerId,
+	})
+	// the cart is deleted once checked out, so its ID names the order
+	req.Header().Set(idempotency.Header, "cart:"+id)
+
+	res, err := cs.orders.CreateOrder(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create order: %w", err)
+	}
+	return res.Msg.Order, nil
+}
+
+// cart loads a cart and prices it at the current book prices.
+func (cs *CartService) cart(ctx context.Context, id string) (*v1.Cart, error) {
+	rec, err := cs.store.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	cart := &v1.Cart{
+		Id:        id,
+		Lines:     make([]*v1.CartLine, 0, len(rec.lines)),
+		ExpiresAt: rec.expiresAt.UTC().Format(time.RFC3339),
+	}
+	total := money.New(0, cs.currency)
+	for _, l := range rec.lines {
+		line := &v1.CartLine{
+			BookId:   strconv.FormatInt(l.bookID, 10),
+			Quantity: l.quantity,
+		}
+		book, err := cs.book(ctx, line.BookId)
+		if err != nil {
+			return nil, err
+		}
+		if book != nil {
+			price, err := money.FromProto(book.Price, cs.currency)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read book price: [id=%s] %w", line.BookId, err)
+			}
+			lineTotal, err := price.Mul(int64(l.quantity))
+			if err != nil {
+				return nil, connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("%w: %w", ErrTotalOutOfBounds, err))
+			}
+			line.Title = book.Title
+			line.UnitPrice = price.Proto()
+			line.TotalPrice = lineTotal.Proto()
+			line.Available = price.Amount > 0
+
+			if line.Available {
+				converted, err := cs.rates.Convert(lineTotal, cs.currency)
+				if err != nil {
+					return nil, connect.NewError(connect.CodeFailedPrecondition, err)
+				}
+				if total, err = total.Add(converted); err != nil {
+					return nil, connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("%w: %w", ErrTotalOutOfBounds, err))
+				}
+			}
+		}
+		cart.Lines = append(cart.Lines, line)
+	}
+
+	cart.TotalPrice = total.Proto()
+	return cart, nil
+}
+
+// book returns the book with id, nil if it does not exist.
+func (cs *CartService) book(ctx context.Context, id string) (*booksV1.Book, error) {
+	res, err := cs.books.GetBook(ctx, connect.NewRequest(&booksV1.GetBookRequest{Id: id}))
+	if connect.CodeOf(err) == connect.CodeNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get book: [id=%s] %w", id, err)
+	}
+	return res.Msg.Book, nil
+}
+
+// checkBook parses a book ID and checks that the book can be bought.
+func (cs *CartService) checkBook(ctx context.Context, id string) (int64, error) {
+	bookID, err := parseID(id)
+	if err != nil {
+		return 0, err
+	}
+	book, err := cs.book(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	if book == nil {
+		return 0, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("%w: [book_id=%s]", ErrUnknownBook, id))
+	}
+	if book.Price.GetAmount() <= 0 {
+		return 0, connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("%w: [book_id=%s]", ErrUnavailableBooks, id))
+	}
+	return bookID, nil
+}
+
+func parseID(id string) (int64, error) {
+	bookID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil || bookID <= 0 {
+		return 0, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("%w: [book_id=%s]", ErrInvalidID, id))
+	}
+	return bookID, nil
+}
+
+// retryable reports whether err leaves it open if the order was created.
+func retryable(err error) bool {
+	switch connect.CodeOf(err) {
+	case connect.CodeUnavailable, connect.CodeDeadlineExceeded, connect.CodeCanceled:
+		return true
+	}
+	return false
+}
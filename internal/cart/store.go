@@ -0,0 +1,231 @@
+package cart
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/iho/bookstore/internal/tenant"
+	redis "github.com/redis/go-redis/v9"
+)
+
+// A cart is a hash holding a field per line, "line:<book id>" set to the
+// quantity, the creation time and, while the cart is checked out, the date
+// of the order being created.
+const (
+	linePrefix     = "line:"
+	createdField   = "created_at"
+	checkoutField  = "checkout"
+	maxQuantity    = 999
+	maxLines       = 100
+	setQuantity    = "set"
+	addQuantity    = "add"
+	missingCart    = -1
+	checkedOut     = -2
+	quantityTooBig = -3
+	tooManyLines   = -4
+)
+
+// updateLine changes the quantity of a line of an existing cart that is not
+// checked out and renews the TTL of the cart. It returns the new quantity or
+// one of the negative status codes above.
+var updateLine = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	return -1
+end
+if redis.call("HEXISTS", KEYS[1], "checkout") == 1 then
+	return -2
+end
+local current = tonumber(redis.call("HGET", KEYS[1], ARGV[1]) or "0")
+local quantity = tonumber(ARGV[2])
+if ARGV[3] == "add" then
+	quantity = current + quantity
+end
+if quantity > tonumber(ARGV[4]) then
+	return -3
+end
+if quantity <= 0 then
+	redis.call("HDEL", KEYS[1], ARGV[1])
+else
+	if current == 0 and redis.call("HLEN", KEYS[1]) > tonumber(ARGV[5]) then
+		return -4
+	end
+	redis.call("HSET", KEYS[1], ARGV[1], quantity)
+end
+redis.call("PEXPIRE", KEYS[1], ARGV[6])
+return quantity
+`)
+
+// startCheckout marks an existing cart as checked out with the order date in
+// ARGV[1], unless it already is, and returns the order date of the checkout.
+var startCheckout = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	return false
+end
+redis.call("HSETNX", KEYS[1], "checkout", ARGV[1])
+return redis.call("HGET", KEYS[1], "checkout")
+`)
+
+type line struct {
+	bookID   int64
+	quantity int32
+}
+
+type record struct {
+	lines     []line
+	expiresAt time.Time
+}
+
+// store keeps carts in Redis. Carts of a tenant share a key prefix, they
+// are never read together so they need no hash tag.
+type store struct {
+	rdb    redis.UniversalClient
+	prefix string
+	ttl    time.Duration
+}
+
+func (s *store) key(ctx context.Context, id string) (string, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return "", err
+	}
+	return s.prefix + "carts:" + tenantID + ":" + id, nil
+}
+
+func (s *store) create(ctx context.Context, id string) error {
+	key, err := s.key(ctx, id)
+	if err != nil {
+		return err
+	}
+	_, err = s.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, createdField, time.Now().Unix())
+		pipe.PExpire(ctx, key, s.ttl)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create cart: %w", err)
+	}
+	return nil
+}
+
+func (s *store) load(ctx context.Context, id string) (*record, error) {
+	key, err := s.key(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields *redis.MapStringStringCmd
+	var ttl *redis.DurationCmd
+	_, err = s.rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		fields = pipe.HGetAll(ctx, key)
+		ttl = pipe.PTTL(ctx, key)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load cart: %w", err)
+	}
+	if len(fields.Val()) == 0 {
+		return nil, connect.NewError(connect.CodeNotFound, ErrCartNotFound)
+	}
+
+	rec := &record{expiresAt: time.Now().Add(ttl.Val())}
+	for field, value := range fields.Val() {
+		id, ok := strings.CutPrefix(field, linePrefix)
+		if !ok {
+			continue
+		}
+		bookID, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse cart line: [field=%s] %w", field, err)
+		}
+		quantity, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse cart line: [field=%s] %w", field, err)
+		}
+		rec.lines = append(rec.lines, line{bookID: bookID, quantity: int32(quantity)})
+	}
+	sort.Slice(rec.lines, func(i, j int) bool {
+		return rec.lines[i].bookID < rec.lines[j].bookID
+	})
+	return rec, nil
+}
+
+// update sets the quantity of the line of bookID, or adds to it with mode
+// addQuantity. A quantity of 0 or less removes the line.
+func (s *store) update(ctx context.Context, id string, bookID int64, quantity int32, mode string) error {
+	key, err := s.key(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	status, err := updateLine.Run(ctx, s.rdb, []string{key},
+		linePrefix+strconv.FormatInt(bookID, 10),
+		quantity,
+		mode,
+		maxQuantity,
+		maxLines,
+		s.ttl.Milliseconds(),
+	).Int64()
+	if err != nil {
+		return fmt.Errorf("failed to update cart: %w", err)
+	}
+
+	switch status {
+	case missingCart:
+		return connect.NewError(connect.CodeNotFound, ErrCartNotFound)
+	case checkedOut:
+		return connect.NewError(connect.CodeFailedPrecondition, ErrCheckoutRunning)
+	case quantityTooBig:
+		return connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("%w: at most %d per book", ErrInvalidQuantity, maxQuantity))
+	case tooManyLines:
+		return connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("%w: at most %d", ErrTooManyLines, maxLines))
+	}
+	return nil
+}
+
+// startCheckout marks the cart as checked out and returns the order date.
+// A checkout that was left running returns the date it started with, so that
+// a retry creates the same order.
+func (s *store) startCheckout(ctx context.Context, id string) (string, error) {
+	key, err := s.key(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	date, err := startCheckout.Run(ctx, s.rdb, []string{key}, time.Now().UTC().Format(time.RFC3339)).Text()
+	if errors.Is(err, redis.Nil) {
+		return "", connect.NewError(connect.CodeNotFound, ErrCartNotFound)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to start checkout: %w", err)
+	}
+	return date, nil
+}
+
+// cancelCheckout lets the cart change again after a failed checkout.
+func (s *store) cancelCheckout(ctx context.Context, id string) error {
+	key, err := s.key(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.rdb.HDel(ctx, key, checkoutField).Err(); err != nil {
+		return fmt.Errorf("failed to cancel checkout: %w", err)
+	}
+	return nil
+}
+
+func (s *store) delete(ctx context.Context, id string) error {
+	key, err := s.key(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.rdb.Del(ctx, key).Err(); err != nil {
+		return fmt.Errorf("failed to delete cart: %w", err)
+	}
+	return nil
+}
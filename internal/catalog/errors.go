@@ -0,0 +1,15 @@
+package catalog
+
+import "errors"
+
+var (
+	ErrEmptyRecord          = errors.New("catalog: empty record")
+	ErrInvalidAuthorName    = errors.New("catalog: invalid author name")
+	ErrInvalidTitle         = errors.New("catalog: invalid title")
+	ErrMissingAuthor        = errors.New("catalog: author_id or author_name must be set")
+	ErrUnknownAuthor        = errors.New("catalog: unknown author")
+	ErrInvalidPublishedDate = errors.New("catalog: invalid published date")
+	ErrInvalidPrice         = errors.New("catalog: invalid price")
+	ErrUnknownFormat        = errors.New("catalog: unknown format")
+	ErrUnknownKind          = errors.New("catalog: unknown record kind")
+)
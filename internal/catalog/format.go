@@ -0,0 +1,311 @@
+package catalog
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	v1 "github.com/iho/bookstore/protos/gen/catalog/v1"
+	moneyv1 "github.com/iho/bookstore/protos/gen/money/v1"
+)
+
+type Format string
+
+const (
+	FormatCSV   Format = "csv"
+	FormatJSONL Format = "jsonl"
+
+	kindAuthor = "author"
+	kindBook   = "book"
+)
+
+// csvHeader is written by the CSV writer. The reader maps columns by name,
+// so files may order or omit columns freely as long as "kind" is present.
+var csvHeader = []string{"kind", "id", "name", "title", "author_id", "author_name", "published_date", "price", "currency"}
+
+// ParseFormat accepts a format name, falling back to the extension of path
+// when name is empty.
+func ParseFormat(name, path string) (Format, error) {
+	if name == "" {
+		name = strings.TrimPrefix(filepath.Ext(path), ".")
+	}
+	switch strings.ToLower(name) {
+	case "csv":
+		return FormatCSV, nil
+	case "jsonl", "ndjson":
+		return FormatJSONL, nil
+	}
+	return "", fmt.Errorf("%w: [format=%s]", ErrUnknownFormat, name)
+}
+
+// RowError is returned by readers for a single malformed row. The caller can
+// record it and keep reading.
+type RowError struct {
+	Row int64
+	Err error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("row %d: %v", e.Row, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// RecordReader reads catalog records one at a time. Read returns io.EOF once
+// the input is exhausted and a *RowError for rows that cannot be decoded.
+type RecordReader interface {
+	Read() (record *v1.CatalogRecord, row int64, err error)
+}
+
+// RecordWriter writes catalog records. Flush must be called once at the end.
+type RecordWriter interface {
+	Write(*v1.CatalogRecord) error
+	Flush() error
+}
+
+func NewReader(format Format, r io.Reader) (RecordReader, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVReader(r)
+	case FormatJSONL:
+		return &jsonlReader{scanner: bufio.NewScanner(r)}, nil
+	}
+	return nil, fmt.Errorf("%w: [format=%s]", ErrUnknownFormat, format)
+}
+
+func NewWriter(format Format, w io.Writer) (RecordWriter, error) {
+	switch format {
+	case FormatCSV:
+		return &csvWriter{w: csv.NewWriter(w)}, nil
+	case FormatJSONL:
+		bw := bufio.NewWriter(w)
+		return &jsonlWriter{w: bw, enc: json.NewEncoder(bw)}, nil
+	}
+	return nil, fmt.Errorf("%w: [format=%s]", ErrUnknownFormat, format)
+}
+
+// record is the flat representation shared by the CSV and JSONL formats.
+type record struct {
+	Kind          string `json:"kind"`
+	ID            string `json:"id,omitempty"`
+	Name          string `json:"name,omitempty"`
+	Title         string `json:"title,omitempty"`
+	AuthorID      string `json:"author_id,omitempty"`
+	AuthorName    string `json:"author_name,omitempty"`
+	PublishedDate string `json:"published_date,omitempty"`
+	// Price is in minor units of Currency, which defaults to the currency
+	// of the store.
+	Price    int64  `json:"price,omitempty"`
+	Currency string `json:"currency,omitempty"`
+}
+
+func (r *record) toProto() (*v1.CatalogRecord, error) {
+	switch strings.ToLower(strings.TrimSpace(r.Kind)) {
+	case kindAuthor:
+		return &v1.CatalogRecord{
+			Record: &v1.CatalogRecord_Author{
+				Author: &v1.AuthorRecord{
+					Id:   r.ID,
+					Name: r.Name,
+				},
+			},
+		}, nil
+	case kindBook:
+		return &v1.CatalogRecord{
+			Record: &v1.CatalogRecord_Book{
+				Book: &v1.BookRecord{
+					Id:            r.ID,
+					Title:         r.Title,
+					AuthorId:      r.AuthorID,
+					AuthorName:    r.AuthorName,
+					PublishedDate: r.PublishedDate,
+					Price:         &moneyv1.Money{Amount: r.Price, Currency: r.Currency},
+				},
+			},
+		}, nil
+	}
+	return nil, fmt.Errorf("%w: [kind=%s]", ErrUnknownKind, r.Kind)
+}
+
+func recordFromProto(rec *v1.CatalogRecord) (*record, error) {
+	if author := rec.GetAuthor(); author != nil {
+		return &record{
+			Kind: kindAuthor,
+			ID:   author.Id,
+			Name: author.Name,
+		}, nil
+	}
+	if book := rec.GetBook(); book != nil {
+		return &record{
+			Kind:          kindBook,
+			ID:            book.Id,
+			Title:         book.Title,
+			AuthorID:      book.AuthorId,
+			AuthorName:    book.AuthorName,
+			PublishedDate: book.PublishedDate,
+			Price:         book.GetPrice().GetAmount(),
+			Currency:      book.GetPrice().GetCurrency(),
+		}, nil
+	}
+	return nil, ErrEmptyRecord
+}
+
+type csvReader struct {
+	r       *csv.Reader
+	columns map[string]int
+	// line is the line of the last row read.
+	line int64
+}
+
+func newCSVReader(r io.Reader) (*csvReader, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read csv header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["kind"]; !ok {
+		return nil, errors.New("catalog: csv header must contain a kind column")
+	}
+
+	return &csvReader{r: cr, columns: columns}, nil
+}
+
+func (r *csvReader) Read() (*v1.CatalogRecord, int64, error) {
+	fields, err := r.r.Read()
+	if err != nil {
+		// FieldPos may only be called after a successful Read
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return nil, int64(parseErr.Line), &RowError{Row: int64(parseErr.Line), Err: parseErr.Err}
+		}
+		return nil, r.line, err
+	}
+	line, _ := r.r.FieldPos(0)
+	r.line = int64(line)
+	row := r.line
+
+	field := func(name string) string {
+		i, ok := r.columns[name]
+		if !ok || i >= len(fields) {
+			return ""
+		}
+		return fields[i]
+	}
+
+	rec := &record{
+		Kind:          field("kind"),
+		ID:            field("id"),
+		Name:          field("name"),
+		Title:         field("title"),
+		AuthorID:      field("author_id"),
+		AuthorName:    field("author_name"),
+		PublishedDate: field("published_date"),
+		Currency:      field("currency"),
+	}
+	if price := strings.TrimSpace(field("price")); price != "" {
+		if rec.Price, err = strconv.ParseInt(price, 10, 64); err != nil {
+			return nil, row, &RowError{Row: row, Err: fmt.Errorf("%w: [price=%s]", ErrInvalidPrice, price)}
+		}
+	}
+	msg, err := rec.toProto()
+	if err != nil {
+		return nil, row, &RowError{Row: row, Err: err}
+	}
+	return msg, row, nil
+}
+
+type jsonlReader struct {
+	scanner *bufio.Scanner
+	line    int64
+}
+
+func (r *jsonlReader) Read() (*v1.CatalogRecord, int64, error) {
+	for r.scanner.Scan() {
+		r.line++
+		text := strings.TrimSpace(r.scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var rec record
+		if err := json.Unmarshal([]byte(text), &rec); err != nil {
+			return nil, r.line, &RowError{Row: r.line, Err: err}
+		}
+		msg, err := rec.toProto()
+		if err != nil {
+			return nil, r.line, &RowError{Row: r.line, Err: err}
+		}
+		return msg, r.line, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return nil, r.line, err
+	}
+	return nil, r.line, io.EOF
+}
+
+type csvWriter struct {
+	w             *csv.Writer
+	headerWritten bool
+}
+
+func (w *csvWriter) Write(msg *v1.CatalogRecord) error {
+	if !w.headerWritten {
+		if err := w.w.Write(csvHeader); err != nil {
+			return err
+		}
+		w.headerWritten = true
+	}
+
+	rec, err := recordFromProto(msg)
+	if err != nil {
+		return err
+	}
+	price := ""
+	if rec.Kind == kindBook {
+		price = strconv.FormatInt(rec.Price, 10)
+	}
+	return w.w.Write([]string{rec.Kind, rec.ID, rec.Name, rec.Title, rec.AuthorID, rec.AuthorName, rec.PublishedDate, price, rec.Currency})
+}
+
+func (w *csvWriter) Flush() error {
+	if !w.headerWritten {
+		if err := w.w.Write(csvHeader); err != nil {
+			return err
+		}
+		w.headerWritten = true
+	}
+	w.w.Flush()
+	return w.w.Error()
+}
+
+type jsonlWriter struct {
+	w   *bufio.Writer
+	enc *json.Encoder
+}
+
+func (w *jsonlWriter) Write(msg *v1.CatalogRecord) error {
+	rec, err := recordFromProto(msg)
+	if err != nil {
+		return err
+	}
+	return w.enc.Encode(rec)
+}
+
+func (w *jsonlWriter) Flush() error {
+	return w.w.Flush()
+}
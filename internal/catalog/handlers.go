@@ -0,0 +1,312 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/iho/bookstore/internal/books"
+	"github.com/iho/bookstore/internal/money"
+	authorsV1 "github.com/iho/bookstore/protos/gen/authors/v1"
+	booksV1 "github.com/iho/bookstore/protos/gen/books/v1"
+	v1 "github.com/iho/bookstore/protos/gen/catalog/v1"
+	moneyv1 "github.com/iho/bookstore/protos/gen/money/v1"
+)
+
+const authorsPageSize = 100
+
+// AuthorStore is the part of the authors API the catalog needs. Both the
+// Connect client and the service implementation satisfy it.
+type AuthorStore interface {
+	ListAuthors(context.Context, *connect.Request[authorsV1.ListAuthorsRequest]) (*connect.Response[authorsV1.ListAuthorsResponse], error)
+	CreateAuthor(context.Context, *connect.Request[authorsV1.CreateAuthorRequest]) (*connect.Response[authorsV1.CreateAuthorResponse], error)
+}
+
+// BookStore is the part of the books service the catalog needs.
+type BookStore interface {
+	CreateBook(context.Context, *connect.Request[booksV1.CreateBookRequest]) (*connect.Response[booksV1.CreateBookResponse], error)
+	ScanBooks(ctx context.Context, fn func(*books.Book) error) error
+}
+
+type CatalogService struct {
+	authors AuthorStore
+	books   BookStore
+}
+
+func NewCatalogService(authors AuthorStore, books BookStore) *CatalogService {
+	return &CatalogService{
+		authors: authors,
+		books:   books,
+	}
+}
+
+func (cs *CatalogService) ImportCatalog(ctx context.Context, stream *connect.ClientStream[v1.ImportCatalogRequest]) (*connect.Response[v1.ImportCatalogResponse], error) {
+	index, err := cs.loadAuthors(ctx)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeUnavailable, err)
+	}
+
+	res := &v1.ImportCatalogResponse{}
+	for stream.Receive() {
+		msg := stream.Msg()
+		if res.Rows == 0 {
+			res.DryRun = msg.GetDryRun()
+		}
+		res.Rows++
+
+		row := msg.GetRow()
+		if row == 0 {
+			row = int64(res.Rows)
+		}
+
+		if err := cs.importRecord(ctx, index, res, msg.GetRecord()); err != nil {
+			if ctx.Err() != nil {
+				return nil, connect.NewError(connect.CodeCanceled, ctx.Err())
+			}
+			res.Errors = append(res.Errors, &v1.RowError{
+				Row:     row,
+				Message: err.Error(),
+			})
+		}
+	}
+	if err := stream.Err(); err != nil {
+		return nil, err
+	}
+
+	return &connect.Response[v1.ImportCatalogResponse]{
+		Msg: res,
+	}, nil
+}
+
+func (cs *CatalogService) ExportCatalog(ctx context.Context, req *connect.Request[v1.ExportCatalogRequest], stream *connect.ServerStream[v1.ExportCatalogResponse]) error {
+	authorNames := make(map[string]string)
+	for offset := int32(0); ; offset += authorsPageSize {
+		res, err := cs.authors.ListAuthors(ctx, connect.NewRequest(&authorsV1.ListAuthorsRequest{
+			Offset: offset,
+			Limit:  authorsPageSize,
+		}))
+		if err != nil {
+			return fmt.Errorf("failed to list authors: %w", err)
+		}
+
+		for _, author := range res.Msg.GetAuthors() {
+			authorNames[author.Id] = author.Name
+			err := stream.Send(&v1.ExportCatalogResponse{
+				Record: &v1.CatalogRecord{
+					Record: &v1.CatalogRecord_Author{
+						Author: &v1.AuthorRecord{
+							Id:   author.Id,
+							Name: author.Name,
+						},
+					},
+				},
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(res.Msg.GetAuthors()) < authorsPageSize {
+			break
+		}
+	}
+
+	err := cs.books.ScanBooks(ctx, func(book *books.Book) error {
+		authorID := strconv.FormatInt(book.AuthorID, 10)
+		return stream.Send(&v1.ExportCatalogResponse{
+			Record: &v1.CatalogRecord{
+				Record: &v1.CatalogRecord_Book{
+					Book: &v1.BookRecord{
+						Id:            strconv.FormatInt(book.ID, 10),
+						Title:         book.Title,
+						AuthorId:      authorID,
+						AuthorName:    authorNames[authorID],
+						PublishedDate: book.PublishedDate.Format(time.RFC3339),
+						Price:         &moneyv1.Money{Amount: book.Price, Currency: book.Currency},
+					},
+				},
+			},
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to export books: %w", err)
+	}
+
+	return nil
+}
+
+// authorIndex resolves author names and IDs during a single import.
+// imported maps the IDs of author rows in the import, which are the IDs of
+// the store they were exported from, to the IDs of the same authors here.
+type authorIndex struct {
+	byName   map[string]string
+	ids      map[string]bool
+	imported map[string]string
+}
+
+func (cs *CatalogService) loadAuthors(ctx context.Context) (*authorIndex, error) {
+	index := &authorIndex{
+		byName:   make(map[string]string),
+		ids:      make(map[string]bool),
+		imported: make(map[string]string),
+	}
+
+	for offset := int32(0); ; offset += authorsPageSize {
+		res, err := cs.authors.ListAuthors(ctx, connect.NewRequest(&authorsV1.ListAuthorsRequest{
+			Offset: offset,
+			Limit:  authorsPageSize,
+		}))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list authors: %w", err)
+		}
+
+		for _, author := range res.Msg.GetAuthors() {
+			index.byName[author.Name] = author.Id
+			index.ids[author.Id] = true
+		}
+
+		if len(res.Msg.GetAuthors()) < authorsPageSize {
+			return index, nil
+		}
+	}
+}
+
+func (cs *CatalogService) importRecord(ctx context.Context, index *authorIndex, res *v1.ImportCatalogResponse, record *v1.CatalogRecord) error {
+	switch {
+	case record.GetAuthor() != nil:
+		created, err := cs.importAuthor(ctx, index, res.DryRun, record.GetAuthor())
+		if err != nil {
+			return err
+		}
+		if created {
+			res.AuthorsCreated++
+		}
+	case record.GetBook() != nil:
+		if err := cs.importBook(ctx, index, res.DryRun, record.GetBook()); err != nil {
+			return err
+		}
+		res.BooksCreated++
+	default:
+		return ErrEmptyRecord
+	}
+
+	return nil
+}
+
+// importAuthor creates the author unless one with the same name already
+// exists, so re-importing an export is a no-op for authors.
+func (cs *CatalogService) importAuthor(ctx context.Context, index *authorIndex, dryRun bool, author *v1.AuthorRecord) (bool, error) {
+	name := strings.TrimSpace(author.GetName())
+	if name == "" {
+		return false, ErrInvalidAuthorName
+	}
+
+	if id, ok := index.byName[name]; ok {
+		index.imported[author.GetId()] = id
+		return false, nil
+	}
+
+	if dryRun {
+		// Remember the name so later book rows in the same file validate.
+		index.byName[name] = ""
+		index.imported[author.GetId()] = ""
+		return true, nil
+	}
+
+	res, err := cs.authors.CreateAuthor(ctx, connect.NewRequest(&authorsV1.CreateAuthorRequest{
+		Name: name,
+	}))
+	if err != nil {
+		return false, fmt.Errorf("failed to create author: %w", err)
+	}
+
+	index.byName[name] = res.Msg.Author.Id
+	index.ids[res.Msg.Author.Id] = true
+	index.imported[author.GetId()] = res.Msg.Author.Id
+	return true, nil
+}
+
+// author resolves the author of a book row. author_id is looked up among
+// the author rows imported before it, then among the authors of the store;
+// author_name is used when neither has it, as rows exported from another
+// store refer to authors by the IDs they had there.
+func (index *authorIndex) author(book *v1.BookRecord) (string, error) {
+	if id := book.GetAuthorId(); id != "" {
+		if imported, ok := index.imported[id]; ok {
+			return imported, nil
+		}
+		if index.ids[id] {
+			return id, nil
+		}
+		if book.GetAuthorName() == "" {
+			return "", fmt.Errorf("%w: [author_id=%s]", ErrUnknownAuthor, id)
+		}
+	}
+	if name := book.GetAuthorName(); name != "" {
+		id, ok := index.byName[strings.TrimSpace(name)]
+		if !ok {
+			return "", fmt.Errorf("%w: [author_name=%s]", ErrUnknownAuthor, name)
+		}
+		return id, nil
+	}
+	return "", ErrMissingAuthor
+}
+
+func (cs *CatalogService) importBook(ctx context.Context, index *authorIndex, dryRun bool, book *v1.BookRecord) error {
+	title := strings.TrimSpace(book.GetTitle())
+	if title == "" {
+		return ErrInvalidTitle
+	}
+
+	authorID, err := index.author(book)
+	if err != nil {
+		return err
+	}
+
+	publishedDate, err := parsePublishedDate(book.GetPublishedDate())
+	if err != nil {
+		return err
+	}
+
+	// the books service checks the price too, but dry runs do not reach it
+	if price, err := money.FromProto(book.GetPrice(), ""); err != nil || price.Amount < 0 {
+		return fmt.Errorf("%w: [price=%d %s]", ErrInvalidPrice, book.GetPrice().GetAmount(), book.GetPrice().GetCurrency())
+	}
+
+	if dryRun {
+		return nil
+	}
+
+	_, err = cs.books.CreateBook(ctx, connect.NewRequest(&booksV1.CreateBookRequest{
+		Title:         title,
+		AuthorId:      authorID,
+		PublishedDate: publishedDate.UTC().Format(books.JSONDateFormat),
+		Price:         book.GetPrice(),
+	}))
+	if err != nil {
+		return fmt.Errorf("failed to create book: %w", err)
+	}
+
+	return nil
+}
+
+// publishedDateFormats are tried in order. The books service only accepts
+// books.JSONDateFormat but returns RFC 3339, so both must round-trip.
+var publishedDateFormats = []string{
+	books.JSONDateFormat,
+	time.RFC3339,
+	time.DateOnly,
+}
+
+func parsePublishedDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range publishedDateFormats {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: [published_date=%s]", ErrInvalidPublishedDate, value)
+}
@@ -1,7 +1,46 @@
 package cfg
 
+import (
+	"time"
+
+	"github.com/iho/bookstore/internal/clients"
+	redis "github.com/redis/go-redis/v9"
+)
+
 type Config struct {
-	AuthorSericeUrl string
-	BookServiceUrl  string
-	OrderServiceUrl string
+	// Authors, Books, Orders and Cart configure the clients of the
+	// downstream services.
+	Authors clients.Service
+	Books   clients.Service
+	Orders  clients.Service
+	Cart    clients.Service
+	// APITokens are the bearer tokens accepted by the gateway, besides JWTs
+	// signed with JWTSecret. Empty both allows anonymous access.
+	APITokens []string
+	JWTSecret string
+	// DefaultTenant is the tenant of requests that name none, either by a
+	// token claim or a header. Empty rejects them.
+	DefaultTenant string
+	// MaxComplexity and MaxDepth bound GraphQL operations. ListSize is the
+	// number of items assumed for lists of unknown length when computing
+	// complexity.
+	MaxComplexity int
+	MaxDepth      int
+	ListSize      int
+	// OperationTimeout bounds queries and mutations.
+	OperationTimeout time.Duration
+	// PersistedQueriesManifest is the path of a JSON file mapping query
+	// hashes to queries. With StrictPersistedQueries only these operations
+	// are accepted.
+	PersistedQueriesManifest string
+	StrictPersistedQueries   bool
+	// APQRedis stores automatic persisted queries in Redis instead of in
+	// memory when set.
+	APQRedis *redis.UniversalOptions
+	// CacheTTL is how long books and authors are cached across requests,
+	// zero disables the cache. CacheSize bounds the in-memory cache, which
+	// is replaced by Redis when CacheRedis is set.
+	CacheTTL   time.Duration
+	CacheSize  int
+	CacheRedis *redis.UniversalOptions
 }
@@ -0,0 +1,199 @@
+// Package clients builds the Connect clients services use to call each
+// other. All clients of a process share one connection pool per kind of
+// transport instead of opening new connections per client.
+package clients
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	"golang.org/x/net/http2"
+)
+
+// Protocols a client can speak.
+const (
+	ProtocolConnect = "connect"
+	ProtocolGRPC    = "grpc"
+	ProtocolGRPCWeb = "grpcweb"
+)
+
+// Compressions for request messages. Responses are always accepted gzipped.
+const (
+	CompressionNone = "none"
+	CompressionGzip = "gzip"
+)
+
+const defaultTimeout = 5 * time.Second
+
+var (
+	ErrUnknownProtocol    = errors.New("clients: unknown protocol")
+	ErrUnknownCompression = errors.New("clients: unknown compression")
+)
+
+// Service configures the client of one downstream service.
+type Service struct {
+	// Name labels the metrics of the client.
+	Name string
+	URL  string
+	// Timeout bounds every unary call. Calls whose context has an earlier
+	// deadline keep it. Streams are not bounded.
+	Timeout     time.Duration
+	Protocol    string
+	Compression string
+	// Retries is how often failed calls without side effects are retried.
+	Retries int
+	// MaxConcurrency bounds the unary calls in flight.
+	MaxConcurrency int
+}
+
+// FromEnv reads the configuration of a service from <prefix>_URL,
+// <prefix>_TIMEOUT, <prefix>_PROTOCOL, <prefix>_COMPRESSION,
+// <prefix>_RETRIES and <prefix>_MAX_CONCURRENCY, e.g. BOOKS_URL for prefix
+// BOOKS.
+func FromEnv(prefix, defaultURL string) (Service, error) {
+	svc := Service{
+		Name:           strings.ToLower(prefix),
+		URL:            defaultURL,
+		Timeout:        defaultTimeout,
+		Protocol:       ProtocolConnect,
+		Compression:    CompressionNone,
+		Retries:        defaultRetries,
+		MaxConcurrency: defaultMaxConcurrency,
+	}
+	if value := os.Getenv(prefix + "_URL"); value != "" {
+		svc.URL = value
+	}
+	if value := os.Getenv(prefix + "_TIMEOUT"); value != "" {
+		timeout, err := time.ParseDuration(value)
+		if err != nil {
+			return Service{}, fmt.Errorf("invalid %s_TIMEOUT: %w", prefix, err)
+		}
+		svc.Timeout = timeout
+	}
+	if value := os.Getenv(prefix + "_PROTOCOL"); value != "" {
+		svc.Protocol = value
+	}
+	if value := os.Getenv(prefix + "_COMPRESSION"); value != "" {
+		svc.Compression = value
+	}
+	if value := os.Getenv(prefix + "_RETRIES"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return Service{}, fmt.Errorf("invalid %s_RETRIES: %w", prefix, err)
+		}
+		svc.Retries = n
+	}
+	if value := os.Getenv(prefix + "_MAX_CONCURRENCY"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return Service{}, fmt.Errorf("invalid %s_MAX_CONCURRENCY: %w", prefix, err)
+		}
+		svc.MaxConcurrency = n
+	}
+	return svc, svc.validate()
+}
+
+func (s Service) validate() error {
+	switch s.Protocol {
+	case ProtocolConnect, ProtocolGRPC, ProtocolGRPCWeb:
+	default:
+		return fmt.Errorf("%w: [protocol=%s]", ErrUnknownProtocol, s.Protocol)
+	}
+	switch s.Compression {
+	case CompressionNone, CompressionGzip:
+	default:
+		return fmt.Errorf("%w: [compression=%s]", ErrUnknownCompression, s.Compression)
+	}
+	if s.MaxConcurrency < 1 {
+		return fmt.Errorf("clients: max concurrency must be at least 1: [service=%s]", s.Name)
+	}
+	return nil
+}
+
+// Factory creates clients sharing its transports and interceptors.
+type Factory struct {
+	// h2c speaks HTTP/2 without TLS to plain http:// URLs, which every
+	// service serves and gRPC requires.
+	h2c          *http.Client
+	tls          *http.Client
+	interceptors []connect.Interceptor
+}
+
+// NewFactory returns a factory adding interceptors to every client.
+func NewFactory(interceptors ...connect.Interceptor) *Factory {
+	h2c := &http2.Transport{
+		AllowHTTP: true,
+		DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, addr)
+		},
+		ReadIdleTimeout: 30 * time.Second,
+		PingTimeout:     10 * time.Second,
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ForceAttemptHTTP2 = true
+	transport.MaxIdleConnsPerHost = 100
+
+	return &Factory{
+		h2c:          &http.Client{Transport: h2c},
+		tls:          &http.Client{Transport: transport},
+		interceptors: interceptors,
+	}
+}
+
+// New creates a client for svc with newClient, one of the generated
+// New...ServiceClient functions. Unary calls are bounded by svc.Timeout
+// overall, retried, and pass a circuit breaker and a bulkhead of their own
+// per client on every attempt.
+func New[T any](f *Factory, svc Service, newClient func(connect.HTTPClient, string, ...connect.ClientOption) T) T {
+	httpClient := f.h2c
+	if strings.HasPrefix(svc.URL, "https://") {
+		httpClient = f.tls
+	}
+
+	interceptors := append([]connect.Interceptor{
+		tag(svc.Name),
+		timeout(svc.Timeout),
+		retry(svc.Name, svc.Retries),
+		newBreaker(svc.Name).interceptor(),
+		bulkhead(svc.Name, svc.MaxConcurrency),
+	}, f.interceptors...)
+	opts := []connect.ClientOption{connect.WithInterceptors(interceptors...)}
+	switch svc.Protocol {
+	case ProtocolGRPC:
+		opts = append(opts, connect.WithGRPC())
+	case ProtocolGRPCWeb:
+		opts = append(opts, connect.WithGRPCWeb())
+	}
+	if svc.Compression == CompressionGzip {
+		opts = append(opts, connect.WithSendGzip())
+	}
+
+	return newClient(httpClient, svc.URL, opts...)
+}
+
+// timeout bounds unary calls to d. The deadline of the caller's context,
+// e.g. the GraphQL operation timeout, wins when it is earlier, and Connect
+// sends the resulting deadline on to the service.
+func timeout(d time.Duration) connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			if d <= 0 {
+				return next(ctx, req)
+			}
+			ctx, cancel := context.WithTimeout(ctx, d)
+			defer cancel()
+			return next(ctx, req)
+		}
+	}
+}
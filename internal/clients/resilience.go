@@ -0,0 +1,264 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/iho/bookstore/internal/idempotency"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Defaults for the resilience interceptors.
+const (
+	defaultRetries        = 2
+	defaultMaxConcurrency = 100
+
+	retryBaseDelay = 50 * time.Millisecond
+	retryMaxDelay  = time.Second
+
+	breakerFailures = 5
+	breakerCooldown = 10 * time.Second
+)
+
+var (
+	ErrCircuitOpen  = errors.New("clients: circuit breaker open")
+	ErrBulkheadFull = errors.New("clients: too many concurrent calls")
+)
+
+var (
+	retries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "client_retries_total",
+		Help: "Retried downstream calls by service and procedure.",
+	}, []string{"service", "procedure"})
+	breakerState = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "client_circuit_breaker_state",
+		Help: "Circuit breaker state by service: 0 closed, 1 half-open, 2 open.",
+	}, []string{"service"})
+	rejections = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "client_rejections_total",
+		Help: "Calls rejected without reaching the service, by service and reason.",
+	}, []string{"service", "reason"})
+	inFlight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "client_in_flight_requests",
+		Help: "Concurrent unary calls by service.",
+	}, []string{"service"})
+)
+
+// RegisterMetrics registers the retry, circuit breaker and bulkhead metrics.
+func RegisterMetrics(reg prometheus.Registerer) {
+	reg.MustRegister(retries, breakerState, rejections, inFlight)
+}
+
+// ServiceError tags errors of a client with the service it called.
+type ServiceError struct {
+	Service string
+	Err     error
+}
+
+func (e *ServiceError) Error() string { return e.Service + ": " + e.Err.Error() }
+func (e *ServiceError) Unwrap() error { return e.Err }
+
+// Unavailable reports whether err means the service could not serve the
+// call at all, as opposed to rejecting it.
+func Unavailable(err error) bool {
+	switch connect.CodeOf(err) {
+	case connect.CodeUnavailable, connect.CodeDeadlineExceeded, connect.CodeResourceExhausted:
+		return true
+	}
+	return false
+}
+
+func tag(service string) connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			res, err := next(ctx, req)
+			if err != nil {
+				err = &ServiceError{Service: service, Err: err}
+			}
+			return res, err
+		}
+	}
+}
+
+// retry retries unary calls to procedures without side effects, and calls
+// carrying an idempotency key, up to n times on transient errors, with full
+// jitter exponential backoff.
+func retry(service string, n int) connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			res, err := next(ctx, req)
+			if req.Spec().IdempotencyLevel != connect.IdempotencyNoSideEffects && req.Header().Get(idempotency.Header) == "" {
+				return res, err
+			}
+			for attempt := 0; attempt < n && retryable(err); attempt++ {
+				delay := min(retryBaseDelay<<attempt, retryMaxDelay)
+				if !sleep(ctx, rand.N(delay)) {
+					return res, err
+				}
+				retries.WithLabelValues(service, req.Spec().Procedure).Inc()
+				res, err = next(ctx, req)
+			}
+			return res, err
+		}
+	}
+}
+
+func retryable(err error) bool {
+	if err == nil || errors.Is(err, ErrCircuitOpen) {
+		return false
+	}
+	switch connect.CodeOf(err) {
+	case connect.CodeUnavailable, connect.CodeResourceExhausted, connect.CodeAborted:
+		return true
+	}
+	return false
+}
+
+// sleep waits for d and reports false if ctx ends or would end first.
+func sleep(ctx context.Context, d time.Duration) bool {
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < d {
+		return false
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+type circuitState int
+
+const (
+	stateClosed circuitState = iota
+	stateHalfOpen
+	stateOpen
+)
+
+// breaker fails calls fast once a service failed breakerFailures times in
+// a row. After breakerCooldown it lets a single call through and closes
+// again if that one succeeds.
+type breaker struct {
+	service string
+
+	mu       sync.Mutex
+	state    circuitState
+	failures int
+	openedAt time.Time
+}
+
+func newBreaker(service string) *breaker {
+	b := &breaker{service: service}
+	breakerState.WithLabelValues(service).Set(float64(stateClosed))
+	return b
+}
+
+func (b *breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case stateOpen:
+		if time.Since(b.openedAt) < breakerCooldown {
+			return false
+		}
+		b.setState(stateHalfOpen)
+		return true
+	case stateHalfOpen:
+		// a probe is already in flight
+		return false
+	}
+	return true
+}
+
+func (b *breaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !failure(err) {
+		b.failures = 0
+		b.setState(stateClosed)
+		return
+	}
+	b.failures++
+	if b.state == stateHalfOpen || b.failures >= breakerFailures {
+		b.openedAt = time.Now()
+		b.setState(stateOpen)
+	}
+}
+
+// abandon lets the next call probe again if a canceled call was the probe.
+func (b *breaker) abandon() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == stateHalfOpen {
+		b.openedAt = time.Now().Add(-breakerCooldown)
+		b.setState(stateOpen)
+	}
+}
+
+func (b *breaker) setState(state circuitState) {
+	b.state = state
+	breakerState.WithLabelValues(b.service).Set(float64(state))
+}
+
+// failure reports whether err says the service is unhealthy, rather than
+// the request being wrong. Internal and unknown errors are not counted, the
+// services return them for bad input too, and one client sending that must
+// not open the breaker for everyone.
+func failure(err error) bool {
+	if err == nil {
+		return false
+	}
+	switch connect.CodeOf(err) {
+	case connect.CodeUnavailable, connect.CodeDeadlineExceeded:
+		return true
+	}
+	return false
+}
+
+func (b *breaker) interceptor() connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			if !b.allow() {
+				rejections.WithLabelValues(b.service, "circuit_open").Inc()
+				return nil, connect.NewError(connect.CodeUnavailable, ErrCircuitOpen)
+			}
+			res, err := next(ctx, req)
+			if errors.Is(err, context.Canceled) {
+				// says nothing about the service
+				b.abandon()
+			} else {
+				b.record(err)
+			}
+			return res, err
+		}
+	}
+}
+
+// bulkhead rejects unary calls while max calls to the service are in
+// flight, so that a slow service cannot tie up every goroutine.
+func bulkhead(service string, max int) connect.UnaryInterceptorFunc {
+	slots := make(chan struct{}, max)
+	gauge := inFlight.WithLabelValues(service)
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			select {
+			case slots <- struct{}{}:
+			default:
+				rejections.WithLabelValues(service, "bulkhead_full").Inc()
+				return nil, connect.NewError(connect.CodeResourceExhausted, ErrBulkheadFull)
+			}
+			gauge.Inc()
+			defer func() {
+				gauge.Dec()
+				<-slots
+			}()
+			return next(ctx, req)
+		}
+	}
+}
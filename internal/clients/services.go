@@ -0,0 +1,25 @@
+package clients
+
+import (
+	"github.com/iho/bookstore/protos/gen/authors/v1/authorsv1connect"
+	"github.com/iho/bookstore/protos/gen/books/v1/booksv1connect"
+	"github.com/iho/bookstore/protos/gen/cart/v1/cartv1connect"
+	"github.com/iho/bookstore/protos/gen/orders/v1/ordersv1connect"
+)
+
+// Services holds a client for every downstream service of the gateway.
+type Services struct {
+	Authors authorsv1connect.AuthorsServiceClient
+	Books   booksv1connect.BooksServiceClient
+	Orders  ordersv1connect.OrdersServiceClient
+	Cart    cartv1connect.CartServiceClient
+}
+
+func NewServices(f *Factory, authors, books, orders, cart Service) *Services {
+	return &Services{
+		Authors: New(f, authors, authorsv1connect.NewAuthorsServiceClient),
+		Books:   New(f, books, booksv1connect.NewBooksServiceClient),
+		Orders:  New(f, orders, ordersv1connect.NewOrdersServiceClient),
+		Cart:    New(f, cart, cartv1connect.NewCartServiceClient),
+	}
+}
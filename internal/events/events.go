@@ -0,0 +1,88 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/iho/bookstore/internal/tenant"
+	v1 "github.com/iho/bookstore/protos/gen/events/v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Topics events are published on, one per aggregate so that consumers see
+// the events of a single entity in order.
+const (
+	TopicAuthors = "authors"
+	TopicBooks   = "books"
+	TopicOrders  = "orders"
+)
+
+var (
+	ErrUnknownBroker = errors.New("events: unknown broker")
+	// ErrNoSubscribers is returned by brokers that cannot keep a message for
+	// later, so that it stays in the outbox until someone subscribes.
+	ErrNoSubscribers = errors.New("events: no subscribers")
+)
+
+// Message is a serialized v1.Event on its way to a broker.
+type Message struct {
+	// ID is the event ID. Brokers and consumers use it for deduplication.
+	ID      string
+	Topic   string
+	Payload []byte
+}
+
+// Handler processes one message. Returning an error leaves the message
+// unacknowledged so it is delivered again.
+type Handler func(ctx context.Context, msg Message) error
+
+// Broker delivers messages to subscribers at least once.
+type Broker interface {
+	// Publish sends msg to every subscriber group of its topic. Publishing a
+	// message with an ID that was already published is a no-op.
+	Publish(ctx context.Context, msg Message) error
+	// Subscribe calls handler for every message on topic until ctx is done.
+	// Subscribers sharing a group split the messages between them.
+	Subscribe(ctx context.Context, topic, group string, handler Handler) error
+}
+
+// NewMessage wraps payload in an event envelope with a fresh ID and the
+// tenant of ctx.
+func NewMessage(ctx context.Context, topic, aggregateID string, payload proto.Message) (Message, error) {
+	body, err := anypb.New(payload)
+	if err != nil {
+		return Message{}, fmt.Errorf("failed to wrap event: %w", err)
+	}
+
+	event := &v1.Event{
+		Id:          uuid.NewString(),
+		AggregateId: aggregateID,
+		OccurredAt:  timestamppb.Now(),
+		Payload:     body,
+	}
+	event.TenantId, _ = tenant.FromContext(ctx)
+
+	data, err := proto.Marshal(event)
+	if err != nil {
+		return Message{}, fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	return Message{
+		ID:      event.Id,
+		Topic:   topic,
+		Payload: data,
+	}, nil
+}
+
+// Decode unmarshals the event envelope carried by msg.
+func Decode(msg Message) (*v1.Event, error) {
+	event := new(v1.Event)
+	if err := proto.Unmarshal(msg.Payload, event); err != nil {
+		return nil, fmt.Errorf("failed to decode event: [id=%s] %w", msg.ID, err)
+	}
+	return event, nil
+}
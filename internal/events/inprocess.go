@@ -0,0 +1,100 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	inProcessBuffer     = 256
+	inProcessSeenIDs    = 4096
+	inProcessRetryDelay = time.Second
+)
+
+// InProcessBroker delivers messages between goroutines of one process. It is
+// meant for tests and single binary setups; nothing survives a restart.
+// Messages published before a subscriber of their topic exists are refused
+// with ErrNoSubscribers, as they would otherwise be lost.
+type InProcessBroker struct {
+	mu     sync.Mutex
+	groups map[string]map[string]chan Message // topic -> group -> queue
+	seen   map[string]struct{}
+	order  []string
+}
+
+func NewInProcessBroker() *InProcessBroker {
+	return &InProcessBroker{
+		groups: make(map[string]map[string]chan Message),
+		seen:   make(map[string]struct{}),
+	}
+}
+
+func (b *InProcessBroker) Publish(ctx context.Context, msg Message) error {
+	b.mu.Lock()
+	if _, ok := b.seen[msg.ID]; ok {
+		b.mu.Unlock()
+		return nil
+	}
+	if len(b.groups[msg.Topic]) == 0 {
+		b.mu.Unlock()
+		return fmt.Errorf("%w: [topic=%s]", ErrNoSubscribers, msg.Topic)
+	}
+	b.remember(msg.ID)
+
+	queues := make([]chan Message, 0, len(b.groups[msg.Topic]))
+	for _, queue := range b.groups[msg.Topic] {
+		queues = append(queues, queue)
+	}
+	b.mu.Unlock()
+
+	for _, queue := range queues {
+		select {
+		case queue <- msg:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// remember records id as published, forgetting the oldest IDs beyond
+// inProcessSeenIDs. Callers hold b.mu.
+func (b *InProcessBroker) remember(id string) {
+	b.seen[id] = struct{}{}
+	b.order = append(b.order, id)
+	if len(b.order) > inProcessSeenIDs {
+		delete(b.seen, b.order[0])
+		b.order = b.order[1:]
+	}
+}
+
+func (b *InProcessBroker) Subscribe(ctx context.Context, topic, group string, handler Handler) error {
+	b.mu.Lock()
+	if b.groups[topic] == nil {
+		b.groups[topic] = make(map[string]chan Message)
+	}
+	queue, ok := b.groups[topic][group]
+	if !ok {
+		queue = make(chan Message, inProcessBuffer)
+		b.groups[topic][group] = queue
+	}
+	b.mu.Unlock()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case msg := <-queue:
+			// retry until handled, there is nowhere else to keep the message
+			for handler(ctx, msg) != nil {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(inProcessRetryDelay):
+				}
+			}
+		}
+	}
+}
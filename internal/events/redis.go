@@ -0,0 +1,160 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/iho/bookstore/internal/redisclient"
+	redis "github.com/redis/go-redis/v9"
+)
+
+const (
+	redisStreamPrefix  = "events:"
+	redisDedupePrefix  = "events_published:"
+	redisDedupeTTL     = 24 * time.Hour
+	redisStreamMaxLen  = 100000
+	redisReadBatchSize = 100
+	redisReadBlock     = 5 * time.Second
+	redisRetryDelay    = time.Second
+)
+
+// publishScript adds the message to the topic stream unless its ID was
+// published within redisDedupeTTL. Both steps run atomically, so a failed
+// XADD never marks the ID as published.
+var publishScript = redis.NewScript(`
+if redis.call("SET", KEYS[1], "1", "NX", "EX", ARGV[1]) then
+  redis.call("XADD", KEYS[2], "MAXLEN", "~", ARGV[2], "*", "id", ARGV[3], "payload", ARGV[4])
+  return 1
+end
+return 0
+`)
+
+// RedisBroker publishes messages to Redis Streams, one stream per topic, and
+// delivers them through consumer groups. Unacknowledged messages are
+// redelivered to the same consumer when it restarts.
+type RedisBroker struct {
+	rdb      redis.UniversalClient
+	consumer string
+}
+
+func NewRedisBroker(rdb redis.UniversalClient) *RedisBroker {
+	consumer, err := os.Hostname()
+	if err != nil {
+		consumer = "consumer"
+	}
+	return &RedisBroker{
+		rdb:      rdb,
+		consumer: consumer,
+	}
+}
+
+func (b *RedisBroker) Publish(ctx context.Context, msg Message) error {
+	// the dedupe key is tagged with the stream, so that both are on the
+	// same slot of a Redis Cluster
+	stream := redisStreamPrefix + msg.Topic
+	keys := []string{redisDedupePrefix + "{" + stream + "}:" + msg.ID, stream}
+	args := []any{int(redisDedupeTTL.Seconds()), redisStreamMaxLen, msg.ID, msg.Payload}
+	if err := publishScript.Run(ctx, b.rdb, keys, args...).Err(); err != nil {
+		return fmt.Errorf("failed to publish event: [id=%s] %w", msg.ID, err)
+	}
+	return nil
+}
+
+func (b *RedisBroker) Subscribe(ctx context.Context, topic, group string, handler Handler) error {
+	stream := redisStreamPrefix + topic
+
+	err := b.rdb.XGroupCreateMkStream(ctx, stream, group, "$").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return fmt.Errorf("failed to create consumer group: [stream=%s group=%s] %w", stream, group, err)
+	}
+
+	// "0" first returns messages delivered to this consumer but never
+	// acknowledged, ">" then waits for new ones.
+	lastID := "0"
+	for {
+		streams, err := b.rdb.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    group,
+			Consumer: b.consumer,
+			Streams:  []string{stream, lastID},
+			Count:    redisReadBatchSize,
+			Block:    redisReadBlock,
+		}).Result()
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to read events", "stream", stream, "error", err)
+			if !sleep(ctx, redisRetryDelay) {
+				return ctx.Err()
+			}
+			continue
+		}
+
+		received, failed := 0, false
+		for _, s := range streams {
+			received += len(s.Messages)
+			for _, entry := range s.Messages {
+				msg := Message{Topic: topic}
+				msg.ID, _ = entry.Values["id"].(string)
+				payload, _ := entry.Values["payload"].(string)
+				msg.Payload = []byte(payload)
+
+				if err := handler(ctx, msg); err != nil {
+					slog.ErrorContext(ctx, "failed to handle event", "stream", stream, "event_id", msg.ID, "error", err)
+					failed = true
+					continue
+				}
+				if err := b.rdb.XAck(ctx, stream, group, entry.ID).Err(); err != nil {
+					slog.ErrorContext(ctx, "failed to ack event", "stream", stream, "event_id", msg.ID, "error", err)
+				}
+			}
+		}
+
+		switch {
+		case failed:
+			// go back to the pending messages after a pause
+			lastID = "0"
+			if !sleep(ctx, redisRetryDelay) {
+				return ctx.Err()
+			}
+		case lastID == "0" && received == 0:
+			lastID = ">"
+		}
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(d):
+		return true
+	}
+}
+
+// NewBrokerFromEnv builds the broker named by BROKER: "redis" (the default)
+// configured by BROKER_REDIS_ADDRS and the other variables of
+// redisclient.OptionsFromEnv, or "inprocess", which keeps messages in
+// memory.
+func NewBrokerFromEnv() (Broker, error) {
+	switch kind := os.Getenv("BROKER"); kind {
+	case "", "redis":
+		rdb, err := redisclient.FromEnv("BROKER_REDIS", "redis:6379")
+		if err != nil {
+			return nil, err
+		}
+		return NewRedisBroker(rdb), nil
+	case "inprocess":
+		return NewInProcessBroker(), nil
+	default:
+		return nil, fmt.Errorf("%w: [broker=%s]", ErrUnknownBroker, kind)
+	}
+}
@@ -0,0 +1,94 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+const (
+	relayBatchSize    = 100
+	relayPollInterval = time.Second
+)
+
+// OutboxEntry is a message waiting in a service's outbox.
+type OutboxEntry struct {
+	// Key identifies the entry in its store, e.g. a row or stream entry ID.
+	Key     string
+	Message Message
+}
+
+// OutboxStore is the outbox a service writes events to in the same
+// transaction as its state changes.
+type OutboxStore interface {
+	// Fetch returns up to limit unpublished entries, oldest first.
+	Fetch(ctx context.Context, limit int) ([]OutboxEntry, error)
+	// MarkPublished removes entries from the set Fetch returns.
+	MarkPublished(ctx context.Context, entries []OutboxEntry) error
+}
+
+// Relay moves messages from an outbox to a broker. An entry is only marked
+// published after the broker accepted it, so a crash in between publishes it
+// again; brokers drop the duplicate by message ID.
+type Relay struct {
+	store  OutboxStore
+	broker Broker
+}
+
+func NewRelay(store OutboxStore, broker Broker) *Relay {
+	return &Relay{
+		store:  store,
+		broker: broker,
+	}
+}
+
+// Run relays messages until ctx is done.
+func (r *Relay) Run(ctx context.Context) error {
+	for {
+		n, err := r.relayBatch(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		switch {
+		case errors.Is(err, ErrNoSubscribers):
+			// entries wait in the outbox until a subscriber shows up
+			slog.DebugContext(ctx, "outbox relay has no subscribers", "error", err)
+		case err != nil:
+			slog.ErrorContext(ctx, "outbox relay failed", "error", err)
+		}
+		if err == nil && n == relayBatchSize {
+			// more entries are probably waiting
+			continue
+		}
+
+		if !sleep(ctx, relayPollInterval) {
+			return ctx.Err()
+		}
+	}
+}
+
+func (r *Relay) relayBatch(ctx context.Context) (int, error) {
+	entries, err := r.store.Fetch(ctx, relayBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch outbox: %w", err)
+	}
+
+	published := 0
+	var publishErr error
+	for _, entry := range entries {
+		if publishErr = r.broker.Publish(ctx, entry.Message); publishErr != nil {
+			break
+		}
+		published++
+	}
+
+	if published > 0 {
+		if err := r.store.MarkPublished(ctx, entries[:published]); err != nil {
+			return published, fmt.Errorf("failed to mark outbox entries published: %w", err)
+		}
+	}
+
+	return published, publishErr
+}
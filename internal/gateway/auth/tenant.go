@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/iho/bookstore/internal/tenant"
+)
+
+// Tenants picks the tenant of gateway requests from their verified caller:
+// the tenant_id claim of its JWT. Callers without one get Default, and are
+// rejected when it is empty. Whatever else a request says about its tenant
+// is ignored, so it has to run after Authenticator.Middleware.
+type Tenants struct {
+	Default string
+}
+
+// Middleware stores the tenant of the request in its context. Responses
+// vary by tenant, so they are marked as such for shared caches. Websocket
+// upgrades not authenticated yet are let through; WebsocketInit picks the
+// tenant once connection_init has authenticated them.
+func (t Tenants) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Authorization")
+
+		identity, ok := FromContext(r.Context())
+		if !ok && strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+			next.ServeHTTP(w, r)
+			return
+		}
+		id, err := t.pick(identity)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(tenant.WithID(r.Context(), id)))
+	})
+}
+
+// WebsocketInit picks the tenant of a graphql-ws connection from the caller
+// authenticated by Authenticator.WebsocketInit, unless Middleware already
+// did from the upgrade request.
+func (t Tenants) WebsocketInit(ctx context.Context, _ transport.InitPayload) (context.Context, *transport.InitPayload, error) {
+	if _, ok := tenant.FromContext(ctx); ok {
+		return ctx, nil, nil
+	}
+	identity, _ := FromContext(ctx)
+	id, err := t.pick(identity)
+	if err != nil {
+		return ctx, nil, err
+	}
+	return tenant.WithID(ctx, id), nil, nil
+}
+
+func (t Tenants) pick(identity Identity) (string, error) {
+	id := identity.Tenant
+	if id == "" {
+		id = t.Default
+	}
+	if id == "" {
+		return "", tenant.ErrMissing
+	}
+	if err := tenant.Validate(id); err != nil {
+		return "", err
+	}
+	return id, nil
+}
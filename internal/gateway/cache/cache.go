@@ -0,0 +1,129 @@
+// Package cache keeps books and authors fetched by the gateway across
+// requests. It sits underneath the request scoped data loaders, so a batch
+// only asks the services for the entities that are not cached.
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+
+	"github.com/iho/bookstore/internal/gateway/graph/model"
+	"github.com/iho/bookstore/internal/tenant"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var lookups = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_cache_lookups_total",
+	Help: "Entity cache lookups by kind and result.",
+}, []string{"kind", "result"})
+
+// RegisterMetrics registers the cache metrics.
+func RegisterMetrics(reg prometheus.Registerer) {
+	reg.MustRegister(lookups)
+}
+
+// Entities caches one kind of entity by tenant and ID.
+type Entities[V any] struct {
+	store Store
+	kind  string
+	// seen is told about the context of every lookup.
+	seen func(ctx context.Context)
+}
+
+func NewEntities[V any](store Store, kind string) *Entities[V] {
+	return &Entities[V]{store: store, kind: kind}
+}
+
+func (e *Entities[V]) key(ctx context.Context, id string) string {
+	tenantID, _ := tenant.FromContext(ctx)
+	return tenantID + ":" + e.kind + ":" + id
+}
+
+// GetMany returns the cached entities for ids, nil for misses. Store
+// failures are logged and count as misses, the services are the source of
+// truth.
+func (e *Entities[V]) GetMany(ctx context.Context, ids []string) []*V {
+	if e.seen != nil {
+		e.seen(ctx)
+	}
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = e.key(ctx, id)
+	}
+
+	found := make([]*V, len(ids))
+	values, err := e.store.Get(ctx, keys)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to read cache", "kind", e.kind, "error", err)
+		lookups.WithLabelValues(e.kind, "miss").Add(float64(len(ids)))
+		return found
+	}
+
+	hits := 0
+	for i, value := range values {
+		if value == nil {
+			continue
+		}
+		var v V
+		if err := json.Unmarshal(value, &v); err != nil {
+			continue
+		}
+		found[i] = &v
+		hits++
+	}
+	lookups.WithLabelValues(e.kind, "hit").Add(float64(hits))
+	lookups.WithLabelValues(e.kind, "miss").Add(float64(len(ids) - hits))
+	return found
+}
+
+func (e *Entities[V]) Set(ctx context.Context, id string, v *V) {
+	value, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	if err := e.store.Set(ctx, e.key(ctx, id), value); err != nil {
+		slog.WarnContext(ctx, "failed to write cache", "kind", e.kind, "error", err)
+	}
+}
+
+// Invalidate drops the entities with the given ids.
+func (e *Entities[V]) Invalidate(ctx context.Context, ids ...string) {
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = e.key(ctx, id)
+	}
+	if err := e.store.Delete(ctx, keys...); err != nil {
+		slog.ErrorContext(ctx, "failed to invalidate cache", "kind", e.kind, "ids", ids, "error", err)
+	}
+}
+
+// Caches are the entity caches shared by all requests.
+type Caches struct {
+	store   Store
+	Books   *Entities[model.Book]
+	Authors *Entities[model.Author]
+
+	mu          sync.Mutex
+	watchTenant func(tenantID string) context.CancelFunc
+	watched     map[string]*watcher
+}
+
+func New(store Store) *Caches {
+	c := &Caches{
+		store:   store,
+		Books:   NewEntities[model.Book](store, "book"),
+		Authors: NewEntities[model.Author](store, "author"),
+	}
+	c.Books.seen = c.seen
+	c.Authors.seen = c.seen
+	return c
+}
+
+// Purge drops every cached entity.
+func (c *Caches) Purge(ctx context.Context) {
+	if err := c.store.Purge(ctx); err != nil {
+		slog.ErrorContext(ctx, "failed to purge cache", "error", err)
+	}
+}
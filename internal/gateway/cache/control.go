@@ -0,0 +1,135 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/felixge/httpsnoop"
+	"github.com/iho/bookstore/internal/gateway/graph/model"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+type policyKey struct{}
+
+// policy collects the @cacheControl hints of one HTTP request.
+type policy struct {
+	mu      sync.Mutex
+	hints   int
+	maxAge  int
+	private bool
+	hinted  map[string]bool
+	header  string
+}
+
+func (p *policy) hint(alias string, root bool, maxAge int, private bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.hints == 0 || maxAge < p.maxAge {
+		p.maxAge = maxAge
+	}
+	p.hints++
+	p.private = p.private || private
+	if root {
+		p.hinted[alias] = true
+	}
+}
+
+// Middleware sets the Cache-Control header computed by CacheControl for the
+// GraphQL response of a request.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p := &policy{hinted: map[string]bool{}}
+
+		var once sync.Once
+		setHeader := func() {
+			once.Do(func() {
+				p.mu.Lock()
+				defer p.mu.Unlock()
+				if p.header != "" {
+					w.Header().Set("Cache-Control", p.header)
+				}
+			})
+		}
+
+		w = httpsnoop.Wrap(w, httpsnoop.Hooks{
+			WriteHeader: func(next httpsnoop.WriteHeaderFunc) httpsnoop.WriteHeaderFunc {
+				return func(code int) {
+					setHeader()
+					next(code)
+				}
+			},
+			Write: func(next httpsnoop.WriteFunc) httpsnoop.WriteFunc {
+				return func(b []byte) (int, error) {
+					setHeader()
+					return next(b)
+				}
+			},
+		})
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), policyKey{}, p)))
+	})
+}
+
+// Directive implements @cacheControl by recording the hint of every
+// resolved field.
+func Directive(ctx context.Context, obj any, next graphql.Resolver, maxAge int, scope *model.CacheControlScope) (any, error) {
+	if p, ok := ctx.Value(policyKey{}).(*policy); ok {
+		fc := graphql.GetFieldContext(ctx)
+		p.hint(fc.Field.Alias, fc.Object == "Query", maxAge, scope != nil && *scope == model.CacheControlScopePrivate)
+	}
+	return next(ctx)
+}
+
+// CacheControl decides whether a response may be cached. Only queries
+// without errors whose root fields all carry a hint are.
+type CacheControl struct{}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.ResponseInterceptor
+} = CacheControl{}
+
+func (CacheControl) ExtensionName() string {
+	return "CacheControl"
+}
+
+func (CacheControl) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (CacheControl) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	res := next(ctx)
+
+	p, ok := ctx.Value(policyKey{}).(*policy)
+	if !ok || res == nil || len(res.Errors) > 0 || !graphql.HasOperationContext(ctx) {
+		return res
+	}
+	oc := graphql.GetOperationContext(ctx)
+	if oc.Operation == nil || oc.Operation.Operation != ast.Query {
+		return res
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, field := range graphql.CollectFields(oc, oc.Operation.SelectionSet, []string{"Query"}) {
+		if strings.HasPrefix(field.Name, "__") {
+			continue
+		}
+		if !p.hinted[field.Alias] {
+			return res
+		}
+	}
+	if len(p.hinted) == 0 {
+		return res
+	}
+
+	scope := "public"
+	if p.private {
+		scope = "private"
+	}
+	p.header = fmt.Sprintf("%s, max-age=%d", scope, p.maxAge)
+	return res
+}
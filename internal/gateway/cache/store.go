@@ -0,0 +1,136 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/golang-lru/v2/expirable"
+	redis "github.com/redis/go-redis/v9"
+)
+
+// Store keeps encoded entities for a limited time.
+type Store interface {
+	// Get returns the values of keys, nil for misses.
+	Get(ctx context.Context, keys []string) ([][]byte, error)
+	Set(ctx context.Context, key string, value []byte) error
+	Delete(ctx context.Context, keys ...string) error
+	// Purge drops every entry.
+	Purge(ctx context.Context) error
+}
+
+// MemoryStore is an in-process LRU store.
+type MemoryStore struct {
+	lru *expirable.LRU[string, []byte]
+}
+
+func NewMemoryStore(size int, ttl time.Duration) *MemoryStore {
+	return &MemoryStore{lru: expirable.NewLRU[string, []byte](size, nil, ttl)}
+}
+
+func (s *MemoryStore) Get(ctx context.Context, keys []string) ([][]byte, error) {
+	values := make([][]byte, len(keys))
+	for i, key := range keys {
+		values[i], _ = s.lru.Get(key)
+	}
+	return values, nil
+}
+
+func (s *MemoryStore) Set(ctx context.Context, key string, value []byte) error {
+	s.lru.Add(key, value)
+	return nil
+}
+
+func (s *MemoryStore) Delete(ctx context.Context, keys ...string) error {
+	for _, key := range keys {
+		s.lru.Remove(key)
+	}
+	return nil
+}
+
+func (s *MemoryStore) Purge(ctx context.Context) error {
+	s.lru.Purge()
+	return nil
+}
+
+const redisPrefix = "gateway_cache:"
+
+// RedisStore shares the cache between gateway replicas.
+type RedisStore struct {
+	rdb redis.UniversalClient
+	ttl time.Duration
+}
+
+func NewRedisStore(rdb redis.UniversalClient, ttl time.Duration) *RedisStore {
+	return &RedisStore{rdb: rdb, ttl: ttl}
+}
+
+// Get reads the keys in a pipeline rather than with MGET, which a Redis
+// Cluster refuses for keys on different slots.
+func (s *RedisStore) Get(ctx context.Context, keys []string) ([][]byte, error) {
+	cmds, err := s.rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, key := range keys {
+			pipe.Get(ctx, redisPrefix+key)
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, fmt.Errorf("failed to get cache entries: %w", err)
+	}
+
+	values := make([][]byte, len(keys))
+	for i, cmd := range cmds {
+		if value, err := cmd.(*redis.StringCmd).Bytes(); err == nil {
+			values[i] = value
+		}
+	}
+	return values, nil
+}
+
+func (s *RedisStore) Set(ctx context.Context, key string, value []byte) error {
+	if err := s.rdb.Set(ctx, redisPrefix+key, value, s.ttl).Err(); err != nil {
+		return fmt.Errorf("failed to set cache entry: [key=%s] %w", key, err)
+	}
+	return nil
+}
+
+func (s *RedisStore) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	_, err := s.rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, key := range keys {
+			pipe.Del(ctx, redisPrefix+key)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete cache entries: %w", err)
+	}
+	return nil
+}
+
+// Purge scans every master of a Redis Cluster, SCAN only covers the node it
+// is sent to.
+func (s *RedisStore) Purge(ctx context.Context) error {
+	purge := func(ctx context.Context, node redis.UniversalClient) error {
+		iter := node.Scan(ctx, 0, redisPrefix+"*", 100).Iterator()
+		for iter.Next(ctx) {
+			if err := node.Del(ctx, iter.Val()).Err(); err != nil {
+				return fmt.Errorf("failed to purge cache: %w", err)
+			}
+		}
+		if err := iter.Err(); err != nil {
+			return fmt.Errorf("failed to purge cache: %w", err)
+		}
+		return nil
+	}
+
+	if cluster, ok := s.rdb.(*redis.ClusterClient); ok {
+		return cluster.ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+			return purge(ctx, node)
+		})
+	}
+	return purge(ctx, s.rdb)
+}
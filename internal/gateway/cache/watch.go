@@ -0,0 +1,133 @@
+package cache
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/iho/bookstore/internal/tenant"
+	authorsV1 "github.com/iho/bookstore/protos/gen/authors/v1"
+	"github.com/iho/bookstore/protos/gen/authors/v1/authorsv1connect"
+	booksV1 "github.com/iho/bookstore/protos/gen/books/v1"
+	"github.com/iho/bookstore/protos/gen/books/v1/booksv1connect"
+)
+
+const (
+	reconnectDelay = time.Second
+	// maxWatchedTenants bounds the streams the gateway keeps open, two per
+	// tenant.
+	maxWatchedTenants = 256
+)
+
+// watcher holds the streams watching a tenant.
+type watcher struct {
+	stop     context.CancelFunc
+	lastSeen time.Time
+}
+
+// Watch invalidates entities changed through the services directly, not
+// only through gateway mutations, until ctx is done. The services stream the
+// changes of one tenant at a time, so a tenant is watched from its first
+// lookup on. At most maxWatchedTenants are watched at a time, the tenant
+// seen least recently stops being watched to make room for a new one. Events
+// may be missed while a stream is down, so the caches are purged every time
+// it reconnects and when a tenant stops being watched.
+func (c *Caches) Watch(ctx context.Context, books booksv1connect.BooksServiceClient, authors authorsv1connect.AuthorsServiceClient) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.watched = make(map[string]*watcher)
+	c.watchTenant = func(tenantID string) context.CancelFunc {
+		ctx, cancel := context.WithCancel(tenant.WithID(ctx, tenantID))
+		watchTenant(ctx, c, books, authors)
+		return cancel
+	}
+}
+
+// seen starts watching the tenant of ctx if it is not watched yet.
+func (c *Caches) seen(ctx context.Context) {
+	tenantID, ok := tenant.FromContext(ctx)
+	if !ok {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.watchTenant == nil {
+		return
+	}
+	now := time.Now()
+	if w, ok := c.watched[tenantID]; ok {
+		w.lastSeen = now
+		return
+	}
+	if len(c.watched) >= maxWatchedTenants {
+		c.evictWatcher(ctx)
+	}
+	c.watched[tenantID] = &watcher{stop: c.watchTenant(tenantID), lastSeen: now}
+}
+
+// evictWatcher stops watching the tenant seen least recently. Changes to
+// its entities are not noticed anymore, so the caches are purged.
+func (c *Caches) evictWatcher(ctx context.Context) {
+	var oldest string
+	for tenantID, w := range c.watched {
+		if oldest == "" || w.lastSeen.Before(c.watched[oldest].lastSeen) {
+			oldest = tenantID
+		}
+	}
+	c.watched[oldest].stop()
+	delete(c.watched, oldest)
+	c.Purge(ctx)
+}
+
+func watchTenant(ctx context.Context, c *Caches, books booksv1connect.BooksServiceClient, authors authorsv1connect.AuthorsServiceClient) {
+	go watch(ctx, c, "books", func(ctx context.Context, connected func()) error {
+		stream, err := books.WatchBooks(ctx, connect.NewRequest(&booksV1.WatchBooksRequest{}))
+		if err != nil {
+			return err
+		}
+		defer stream.Close()
+		connected()
+		for stream.Receive() {
+			c.Books.Invalidate(ctx, stream.Msg().Book.GetId())
+		}
+		return stream.Err()
+	})
+	go watch(ctx, c, "authors", func(ctx context.Context, connected func()) error {
+		stream, err := authors.WatchAuthors(ctx, connect.NewRequest(&authorsV1.WatchAuthorsRequest{}))
+		if err != nil {
+			return err
+		}
+		defer stream.Close()
+		connected()
+		for stream.Receive() {
+			c.Authors.Invalidate(ctx, stream.Msg().Author.GetId())
+		}
+		return stream.Err()
+	})
+}
+
+func watch(ctx context.Context, c *Caches, name string, consume func(ctx context.Context, connected func()) error) {
+	reconnect := false
+	for {
+		err := consume(ctx, func() {
+			if reconnect {
+				c.Purge(ctx)
+			}
+		})
+		reconnect = true
+		if ctx.Err() != nil {
+			return
+		}
+		tenantID, _ := tenant.FromContext(ctx)
+		slog.WarnContext(ctx, "cache invalidation stream ended", "stream", name, "tenant", tenantID, "error", err)
+
+		select {
+		case <-time.After(reconnectDelay):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
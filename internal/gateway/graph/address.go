@@ -0,0 +1,33 @@
+package graph
+
+import (
+	"github.com/iho/bookstore/internal/gateway/graph/model"
+	ordersV1 "github.com/iho/bookstore/protos/gen/orders/v1"
+)
+
+// addressFromInput converts an optional address, nil is left for the
+// service to default.
+func addressFromInput(in *model.AddressInput) *ordersV1.Address {
+	if in == nil {
+		return nil
+	}
+	a := &ordersV1.Address{
+		Name:    in.Name,
+		Line1:   in.Line1,
+		City:    in.City,
+		Country: in.Country,
+	}
+	if in.Line2 != nil {
+		a.Line2 = *in.Line2
+	}
+	if in.Region != nil {
+		a.Region = *in.Region
+	}
+	if in.PostalCode != nil {
+		a.PostalCode = *in.PostalCode
+	}
+	if in.Phone != nil {
+		a.Phone = *in.Phone
+	}
+	return a
+}
@@ -0,0 +1,28 @@
+package graph
+
+import (
+	"github.com/iho/bookstore/internal/gateway/graph/model"
+	"github.com/iho/bookstore/internal/gateway/loaders"
+	cartV1 "github.com/iho/bookstore/protos/gen/cart/v1"
+)
+
+func cartFromProto(cart *cartV1.Cart) *model.Cart {
+	lines := make([]*model.CartLine, len(cart.Lines))
+	for i, line := range cart.Lines {
+		lines[i] = &model.CartLine{
+			BookID:     line.BookId,
+			Title:      line.Title,
+			Quantity:   int(line.Quantity),
+			UnitPrice:  loaders.MoneyFromProto(line.UnitPrice),
+			TotalPrice: loaders.MoneyFromProto(line.TotalPrice),
+			Available:  line.Available,
+		}
+	}
+
+	return &model.Cart{
+		ID:         cart.Id,
+		Lines:      lines,
+		TotalPrice: loaders.MoneyFromProto(cart.TotalPrice),
+		ExpiresAt:  cart.ExpiresAt,
+	}
+}
@@ -0,0 +1,58 @@
+package graph
+
+import "github.com/iho/bookstore/internal/gateway/graph/model"
+
+// NewComplexity returns complexity functions that weigh list fields by the
+// number of items they return. Lists sized by their input count the
+// requested IDs or their limit; lists without a known size, like
+// Author.books, count as listSize items.
+func NewComplexity(listSize int) ComplexityRoot {
+	var c ComplexityRoot
+
+	list := func(n, childComplexity int) int {
+		return 1 + n*childComplexity
+	}
+
+	c.Query.Books = func(childComplexity int, input *model.BooksQueryInput) int {
+		if input == nil {
+			return list(0, childComplexity)
+		}
+		return list(len(input.IDs), childComplexity)
+	}
+	c.Query.Authors = func(childComplexity int, input *model.AuthorsQueryInput) int {
+		if input == nil {
+			return list(0, childComplexity)
+		}
+		return list(len(input.IDs), childComplexity)
+	}
+	c.Query.Orders = func(childComplexity int, input *model.OrdersQueryInput) int {
+		if input == nil {
+			return list(0, childComplexity)
+		}
+		return list(len(input.IDs), childComplexity)
+	}
+
+	// limit defaults to 10 in the schema, so it is only nil when a client
+	// passes null
+	limited := func(limit *int, childComplexity int) int {
+		if limit == nil {
+			return list(listSize, childComplexity)
+		}
+		return list(*limit, childComplexity)
+	}
+	c.SalesReport.BestSellingBooks = func(childComplexity int, limit *int) int {
+		return limited(limit, childComplexity)
+	}
+	c.SalesReport.BestSellingAuthors = func(childComplexity int, limit *int) int {
+		return limited(limit, childComplexity)
+	}
+
+	c.Author.Books = func(childComplexity int) int {
+		return list(listSize, childComplexity)
+	}
+	c.Order.OrderLines = func(childComplexity int) int {
+		return list(listSize, childComplexity)
+	}
+
+	return c
+}
@@ -0,0 +1,28 @@
+package graph
+
+import (
+	"context"
+	"errors"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/iho/bookstore/internal/clients"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// CodeServiceUnavailable is set on errors of fields whose downstream
+// service is down, together with a "service" extension naming it.
+const CodeServiceUnavailable = "SERVICE_UNAVAILABLE"
+
+// ErrorPresenter marks errors caused by an unavailable downstream service,
+// so that clients can tell partial responses from bad requests.
+func ErrorPresenter(ctx context.Context, err error) *gqlerror.Error {
+	gqlErr := graphql.DefaultErrorPresenter(ctx, err)
+
+	var serviceErr *clients.ServiceError
+	if errors.As(err, &serviceErr) && clients.Unavailable(err) {
+		errcode.Set(gqlErr, CodeServiceUnavailable)
+		gqlErr.Extensions["service"] = serviceErr.Service
+	}
+	return gqlErr
+}
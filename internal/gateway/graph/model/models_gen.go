@@ -2,6 +2,45 @@
 
 package model
 
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+type Address struct {
+	Name       string  `json:"name"`
+	Line1      string  `json:"line1"`
+	Line2      *string `json:"line2,omitempty"`
+	City       string  `json:"city"`
+	Region     *string `json:"region,omitempty"`
+	PostalCode *string `json:"postalCode,omitempty"`
+	Country    string  `json:"country"`
+	Phone      *string `json:"phone,omitempty"`
+}
+
+type AddressInput struct {
+	Name  string  `json:"name"`
+	Line1 string  `json:"line1"`
+	Line2 *string `json:"line2,omitempty"`
+	City  string  `json:"city"`
+	// Subdivision part of an ISO 3166-2 code, e.g. CA for California.
+	Region *string `json:"region,omitempty"`
+	// Required in the countries the store knows the postal code format of.
+	PostalCode *string `json:"postalCode,omitempty"`
+	// ISO 3166-1 alpha-2 code such as US.
+	Country string  `json:"country"`
+	Phone   *string `json:"phone,omitempty"`
+}
+
+type AppliedDiscount struct {
+	PromotionID string `json:"promotionID"`
+	Name        string `json:"name"`
+	// Set when the promotion is a coupon.
+	Code   *string `json:"code,omitempty"`
+	Amount *Money  `json:"amount"`
+}
+
 type Author struct {
 	ID    string  `json:"id"`
 	Name  string  `json:"name"`
@@ -12,6 +51,18 @@ type AuthorQueryInput struct {
 	ID string `json:"id"`
 }
 
+// Revenue is as in BookSales.
+type AuthorSales struct {
+	AuthorID string `json:"authorID"`
+	// Null when the author cannot be loaded, e.g. because it was deleted.
+	Author   *Author `json:"author,omitempty"`
+	Quantity int64   `json:"quantity"`
+	Orders   int64   `json:"orders"`
+	// Number of different books sold.
+	Books   int64  `json:"books"`
+	Revenue *Money `json:"revenue"`
+}
+
 type AuthorsQueryInput struct {
 	IDs []string `json:"IDs"`
 }
@@ -21,30 +72,129 @@ type Book struct {
 	Title         string  `json:"title"`
 	Author        *Author `json:"author"`
 	PublishedDate string  `json:"publishedDate"`
+	// Unit price. Books priced 0 are not for sale.
+	Price *Money `json:"price"`
 }
 
 type BookQueryInput struct {
 	ID string `json:"id"`
 }
 
+// Revenue is the sum of the line totals, after discounts and before taxes.
+type BookSales struct {
+	BookID string `json:"bookID"`
+	// Null when the book cannot be loaded, e.g. because it was deleted.
+	Book     *Book  `json:"book,omitempty"`
+	Quantity int64  `json:"quantity"`
+	Orders   int64  `json:"orders"`
+	Revenue  *Money `json:"revenue"`
+}
+
 type BooksQueryInput struct {
 	IDs []string `json:"IDs"`
 }
 
+// A cart priced at the current book prices. Carts expire when they are left
+// unchanged for a while.
+type Cart struct {
+	ID    string      `json:"id"`
+	Lines []*CartLine `json:"lines"`
+	// Total of the available lines in the currency of the store, before
+	// discounts and taxes.
+	TotalPrice *Money `json:"totalPrice"`
+	ExpiresAt  string `json:"expiresAt"`
+}
+
+// Adds quantity of a book to a cart with addCartItem, or sets the quantity of
+// the book with setCartItemQuantity, where 0 removes it.
+type CartItemInput struct {
+	CartID   string `json:"cartId"`
+	BookID   string `json:"bookID"`
+	Quantity int    `json:"quantity"`
+}
+
+type CartLine struct {
+	BookID   string `json:"bookID"`
+	Title    string `json:"title"`
+	Quantity int    `json:"quantity"`
+	// In the currency the book is priced in.
+	UnitPrice  *Money `json:"unitPrice"`
+	TotalPrice *Money `json:"totalPrice"`
+	// False when the book was deleted or taken off sale. Carts with unavailable
+	// lines cannot be checked out.
+	Available bool `json:"available"`
+}
+
+// A change to the catalog. Exactly one of bookID and authorID is set. book and
+// author are only set for created and updated events.
+type CatalogEvent struct {
+	Type     ChangeType `json:"type"`
+	BookID   *string    `json:"bookID,omitempty"`
+	Book     *Book      `json:"book,omitempty"`
+	AuthorID *string    `json:"authorID,omitempty"`
+	Author   *Author    `json:"author,omitempty"`
+}
+
 type CreateAuthorInput struct {
 	Name string `json:"name"`
+	// Retrying with the same key returns the first result instead of creating
+	// another author. Defaults to the Idempotency-Key header.
+	IdempotencyKey *string `json:"idempotencyKey,omitempty"`
 }
 
 type CreateBookInput struct {
 	Title         string `json:"title"`
 	AuthorID      string `json:"authorId"`
 	PublishedDate string `json:"publishedDate"`
+	// Defaults to 0, not for sale.
+	Price *MoneyInput `json:"price,omitempty"`
+	// Retrying with the same key returns the first result instead of creating
+	// another book. Defaults to the Idempotency-Key header.
+	IdempotencyKey *string `json:"idempotencyKey,omitempty"`
+}
+
+type CreateCartInput struct {
+	// Retrying with the same key returns the first result instead of creating
+	// another cart. Defaults to the Idempotency-Key header.
+	IdempotencyKey *string `json:"idempotencyKey,omitempty"`
 }
 
 type CreateOrderInput struct {
 	OrderLines []*OrderLineInput `json:"orderLines"`
-	TotalPrice int               `json:"totalPrice"`
-	OrderDate  string            `json:"orderDate"`
+	// Ignored, orders are priced from the book prices and promotions.
+	TotalPrice *int `json:"totalPrice,omitempty"`
+	// An RFC 3339 time.
+	OrderDate string `json:"orderDate"`
+	// A coupon to redeem. Orders fail when it does not apply.
+	CouponCode *string `json:"couponCode,omitempty"`
+	// Defaults to the currency of the store.
+	Currency *string `json:"currency,omitempty"`
+	// ISO 3166 code of the country or subdivision taxes are computed for, e.g.
+	// US-CA. Defaults to the region of the shipping address, and to the default
+	// rate without one.
+	TaxRegion *string `json:"taxRegion,omitempty"`
+	// Needed before the order can be shipped.
+	ShippingAddress *AddressInput `json:"shippingAddress,omitempty"`
+	// Defaults to the shipping address.
+	BillingAddress *AddressInput `json:"billingAddress,omitempty"`
+	// Identifies who places the order, opaque to the store.
+	CustomerID *string `json:"customerId,omitempty"`
+	// Retrying with the same key returns the first result instead of creating
+	// another order. Defaults to the Idempotency-Key header.
+	IdempotencyKey *string `json:"idempotencyKey,omitempty"`
+}
+
+type CreateShipmentInput struct {
+	OrderID        string  `json:"orderId"`
+	Carrier        string  `json:"carrier"`
+	TrackingNumber *string `json:"trackingNumber,omitempty"`
+	// Defaults to every book of the order that was not shipped yet.
+	Lines []*ShipmentLineInput `json:"lines,omitempty"`
+	// RFC 3339 time, defaults to now.
+	ShippedAt *string `json:"shippedAt,omitempty"`
+	// Retrying with the same key returns the first result instead of creating
+	// another shipment. Defaults to the Idempotency-Key header.
+	IdempotencyKey *string `json:"idempotencyKey,omitempty"`
 }
 
 type DeleteAuthorInput struct {
@@ -59,6 +209,27 @@ type DeleteOrderInput struct {
 	ID string `json:"id"`
 }
 
+type DeliverShipmentInput struct {
+	OrderID    string `json:"orderId"`
+	ShipmentID string `json:"shipmentId"`
+	// RFC 3339 time, defaults to now.
+	DeliveredAt *string `json:"deliveredAt,omitempty"`
+}
+
+// An amount of a currency.
+type Money struct {
+	// Amount in minor units of the currency, e.g. cents for USD.
+	Amount int64 `json:"amount"`
+	// ISO 4217 currency code such as USD.
+	Currency string `json:"currency"`
+}
+
+type MoneyInput struct {
+	Amount int64 `json:"amount"`
+	// Defaults to the currency of the store.
+	Currency *string `json:"currency,omitempty"`
+}
+
 type Mutation struct {
 }
 
@@ -66,13 +237,35 @@ type Order struct {
 	ID         string       `json:"id"`
 	OrderLines []*OrderLine `json:"orderLines"`
 	Quantity   int          `json:"quantity"`
-	TotalPrice int          `json:"totalPrice"`
-	OrderDate  string       `json:"orderDate"`
+	// Total of the lines before discounts and taxes.
+	SubtotalPrice *Money `json:"subtotalPrice"`
+	DiscountTotal *Money `json:"discountTotal"`
+	TaxTotal      *Money `json:"taxTotal"`
+	// subtotalPrice minus discountTotal plus taxTotal.
+	TotalPrice *Money      `json:"totalPrice"`
+	CouponCode *string     `json:"couponCode,omitempty"`
+	TaxRegion  *string     `json:"taxRegion,omitempty"`
+	OrderDate  string      `json:"orderDate"`
+	Status     OrderStatus `json:"status"`
+	// Calls made to the payment provider, oldest first.
+	Payments        []*PaymentAttempt `json:"payments"`
+	ShippingAddress *Address          `json:"shippingAddress,omitempty"`
+	BillingAddress  *Address          `json:"billingAddress,omitempty"`
+	// Parcels the order was sent in, oldest first.
+	Shipments  []*Shipment `json:"shipments"`
+	CustomerID *string     `json:"customerId,omitempty"`
 }
 
 type OrderLine struct {
 	BookID   string `json:"bookID"`
 	Quantity int    `json:"quantity"`
+	// Book price converted to the currency of the order.
+	UnitPrice *Money `json:"unitPrice"`
+	// Discounts taken off the line, at most one targeted promotion and one
+	// applying to the whole order.
+	Discounts []*AppliedDiscount `json:"discounts"`
+	// unitPrice times quantity minus the discounts, before taxes.
+	TotalPrice *Money `json:"totalPrice"`
 }
 
 type OrderLineInput struct {
@@ -80,17 +273,132 @@ type OrderLineInput struct {
 	Quantity int    `json:"quantity"`
 }
 
+// What an order would cost if it were placed now.
+type OrderPreview struct {
+	OrderLines    []*OrderLine `json:"orderLines"`
+	SubtotalPrice *Money       `json:"subtotalPrice"`
+	DiscountTotal *Money       `json:"discountTotal"`
+	TaxTotal      *Money       `json:"taxTotal"`
+	TotalPrice    *Money       `json:"totalPrice"`
+	CouponCode    *string      `json:"couponCode,omitempty"`
+	TaxRegion     *string      `json:"taxRegion,omitempty"`
+}
+
 type OrderQueryInput struct {
 	ID string `json:"id"`
 }
 
+type OrderStats struct {
+	Orders     int64  `json:"orders"`
+	TotalPrice *Money `json:"totalPrice"`
+	// totalPrice divided by orders.
+	AverageOrderValue *Money `json:"averageOrderValue"`
+	// Number of books in the orders.
+	Quantity             int64             `json:"quantity"`
+	AverageQuantity      float64           `json:"averageQuantity"`
+	QuantityDistribution []*QuantityBucket `json:"quantityDistribution"`
+}
+
 type OrdersQueryInput struct {
 	IDs []string `json:"IDs"`
 }
 
+type PaymentAttempt struct {
+	ID        string           `json:"id"`
+	Operation PaymentOperation `json:"operation"`
+	Status    PaymentStatus    `json:"status"`
+	Amount    *Money           `json:"amount"`
+	// The provider's ID of the payment.
+	Reference *string `json:"reference,omitempty"`
+	// Why the attempt was declined.
+	Reason    *string `json:"reason,omitempty"`
+	CreatedAt string  `json:"createdAt"`
+}
+
+type PreviewOrderInput struct {
+	OrderLines []*OrderLineInput `json:"orderLines"`
+	CouponCode *string           `json:"couponCode,omitempty"`
+	// Defaults to the currency of the store.
+	Currency *string `json:"currency,omitempty"`
+	// ISO 3166 code of the country or subdivision taxes are computed for, e.g.
+	// US-CA. Defaults to the region of the shipping address, and to the default
+	// rate without one.
+	TaxRegion       *string       `json:"taxRegion,omitempty"`
+	ShippingAddress *AddressInput `json:"shippingAddress,omitempty"`
+}
+
+// Counts the orders with minQuantity to maxQuantity books.
+type QuantityBucket struct {
+	MinQuantity int `json:"minQuantity"`
+	// Null for the last bucket, which has no upper bound.
+	MaxQuantity *int  `json:"maxQuantity,omitempty"`
+	Orders      int64 `json:"orders"`
+}
+
+// Root fields are nullable so that a failing downstream service only nulls
+// the fields it serves; the errors say which service was unavailable.
 type Query struct {
 }
 
+type RemoveCartItemInput struct {
+	CartID string `json:"cartId"`
+	BookID string `json:"bookID"`
+}
+
+type Revenue struct {
+	// Periods covering the whole range, periods without orders included.
+	Periods    []*RevenuePeriod `json:"periods"`
+	TotalPrice *Money           `json:"totalPrice"`
+}
+
+type RevenuePeriod struct {
+	// RFC 3339 time the period starts at.
+	Start         string `json:"start"`
+	Orders        int64  `json:"orders"`
+	SubtotalPrice *Money `json:"subtotalPrice"`
+	DiscountTotal *Money `json:"discountTotal"`
+	TaxTotal      *Money `json:"taxTotal"`
+	TotalPrice    *Money `json:"totalPrice"`
+}
+
+// Selects the orders a sales report covers. Reports cover the orders of one
+// currency, amounts are not converted.
+type SalesReportInput struct {
+	// RFC 3339 time, inclusive. Defaults to 30 days before to.
+	From *string `json:"from,omitempty"`
+	// RFC 3339 time, exclusive. Defaults to now.
+	To *string `json:"to,omitempty"`
+	// Defaults to the currency of the store.
+	Currency *string `json:"currency,omitempty"`
+	// Defaults to the orders that were paid and not refunded.
+	Statuses []OrderStatus `json:"statuses,omitempty"`
+}
+
+type Shipment struct {
+	ID             string          `json:"id"`
+	Carrier        string          `json:"carrier"`
+	TrackingNumber *string         `json:"trackingNumber,omitempty"`
+	Lines          []*ShipmentLine `json:"lines"`
+	ShippedAt      string          `json:"shippedAt"`
+	// Unset until the shipment arrived.
+	DeliveredAt *string `json:"deliveredAt,omitempty"`
+}
+
+// A quantity of a book of the order. Order lines with the same book are
+// shipped as one.
+type ShipmentLine struct {
+	BookID   string `json:"bookID"`
+	Quantity int    `json:"quantity"`
+}
+
+type ShipmentLineInput struct {
+	BookID   string `json:"bookID"`
+	Quantity int    `json:"quantity"`
+}
+
+type Subscription struct {
+}
+
 type UpdateAuthorInput struct {
 	ID   string `json:"id"`
 	Name string `json:"name"`
@@ -101,11 +409,339 @@ type UpdateBookInput struct {
 	Title         string `json:"title"`
 	AuthorID      string `json:"authorId"`
 	PublishedDate string `json:"publishedDate"`
+	// Keeps the stored price when omitted.
+	Price *MoneyInput `json:"price,omitempty"`
 }
 
 type UpdateOrderInput struct {
 	ID         string            `json:"id"`
 	OrderLines []*OrderLineInput `json:"orderLines,omitempty"`
-	OrderDate  string            `json:"orderDate"`
-	TotalPrice int               `json:"totalPrice"`
+	// An RFC 3339 time.
+	OrderDate string `json:"orderDate"`
+	// Ignored, orders are priced from the book prices and promotions.
+	TotalPrice *int `json:"totalPrice,omitempty"`
+	// Replaces the shipping address and its tax region when set.
+	ShippingAddress *AddressInput `json:"shippingAddress,omitempty"`
+	// Replaces the billing address when set.
+	BillingAddress *AddressInput `json:"billingAddress,omitempty"`
+	// Replaces the coupon when set, an empty code removes it.
+	CouponCode *string `json:"couponCode,omitempty"`
+}
+
+type CacheControlScope string
+
+const (
+	CacheControlScopePublic  CacheControlScope = "PUBLIC"
+	CacheControlScopePrivate CacheControlScope = "PRIVATE"
+)
+
+var AllCacheControlScope = []CacheControlScope{
+	CacheControlScopePublic,
+	CacheControlScopePrivate,
+}
+
+func (e CacheControlScope) IsValid() bool {
+	switch e {
+	case CacheControlScopePublic, CacheControlScopePrivate:
+		return true
+	}
+	return false
+}
+
+func (e CacheControlScope) String() string {
+	return string(e)
+}
+
+func (e *CacheControlScope) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = CacheControlScope(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid CacheControlScope", str)
+	}
+	return nil
+}
+
+func (e CacheControlScope) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ChangeType string
+
+const (
+	ChangeTypeCreated ChangeType = "CREATED"
+	ChangeTypeUpdated ChangeType = "UPDATED"
+	ChangeTypeDeleted ChangeType = "DELETED"
+)
+
+var AllChangeType = []ChangeType{
+	ChangeTypeCreated,
+	ChangeTypeUpdated,
+	ChangeTypeDeleted,
+}
+
+func (e ChangeType) IsValid() bool {
+	switch e {
+	case ChangeTypeCreated, ChangeTypeUpdated, ChangeTypeDeleted:
+		return true
+	}
+	return false
+}
+
+func (e ChangeType) String() string {
+	return string(e)
+}
+
+func (e *ChangeType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ChangeType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ChangeType", str)
+	}
+	return nil
+}
+
+func (e ChangeType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type OrderStatus string
+
+const (
+	OrderStatusPendingPayment   OrderStatus = "PENDING_PAYMENT"
+	OrderStatusAuthorized       OrderStatus = "AUTHORIZED"
+	OrderStatusPaid             OrderStatus = "PAID"
+	OrderStatusPaymentFailed    OrderStatus = "PAYMENT_FAILED"
+	OrderStatusRefunded         OrderStatus = "REFUNDED"
+	OrderStatusCancelled        OrderStatus = "CANCELLED"
+	OrderStatusPartiallyShipped OrderStatus = "PARTIALLY_SHIPPED"
+	OrderStatusShipped          OrderStatus = "SHIPPED"
+	OrderStatusDelivered        OrderStatus = "DELIVERED"
+)
+
+var AllOrderStatus = []OrderStatus{
+	OrderStatusPendingPayment,
+	OrderStatusAuthorized,
+	OrderStatusPaid,
+	OrderStatusPaymentFailed,
+	OrderStatusRefunded,
+	OrderStatusCancelled,
+	OrderStatusPartiallyShipped,
+	OrderStatusShipped,
+	OrderStatusDelivered,
+}
+
+func (e OrderStatus) IsValid() bool {
+	switch e {
+	case OrderStatusPendingPayment, OrderStatusAuthorized, OrderStatusPaid, OrderStatusPaymentFailed, OrderStatusRefunded, OrderStatusCancelled, OrderStatusPartiallyShipped, OrderStatusShipped, OrderStatusDelivered:
+		return true
+	}
+	return false
+}
+
+func (e OrderStatus) String() string {
+	return string(e)
+}
+
+func (e *OrderStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = OrderStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid OrderStatus", str)
+	}
+	return nil
+}
+
+func (e OrderStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type PaymentOperation string
+
+const (
+	PaymentOperationAuthorize PaymentOperation = "AUTHORIZE"
+	PaymentOperationCapture   PaymentOperation = "CAPTURE"
+	PaymentOperationRefund    PaymentOperation = "REFUND"
+	PaymentOperationVoid      PaymentOperation = "VOID"
+)
+
+var AllPaymentOperation = []PaymentOperation{
+	PaymentOperationAuthorize,
+	PaymentOperationCapture,
+	PaymentOperationRefund,
+	PaymentOperationVoid,
+}
+
+func (e PaymentOperation) IsValid() bool {
+	switch e {
+	case PaymentOperationAuthorize, PaymentOperationCapture, PaymentOperationRefund, PaymentOperationVoid:
+		return true
+	}
+	return false
+}
+
+func (e PaymentOperation) String() string {
+	return string(e)
+}
+
+func (e *PaymentOperation) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = PaymentOperation(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid PaymentOperation", str)
+	}
+	return nil
+}
+
+func (e PaymentOperation) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type PaymentStatus string
+
+const (
+	// The outcome is not known yet. Retrying the operation or the provider's
+	// webhook settles it.
+	PaymentStatusPending   PaymentStatus = "PENDING"
+	PaymentStatusSucceeded PaymentStatus = "SUCCEEDED"
+	PaymentStatusDeclined  PaymentStatus = "DECLINED"
+)
+
+var AllPaymentStatus = []PaymentStatus{
+	PaymentStatusPending,
+	PaymentStatusSucceeded,
+	PaymentStatusDeclined,
+}
+
+func (e PaymentStatus) IsValid() bool {
+	switch e {
+	case PaymentStatusPending, PaymentStatusSucceeded, PaymentStatusDeclined:
+		return true
+	}
+	return false
+}
+
+func (e PaymentStatus) String() string {
+	return string(e)
+}
+
+func (e *PaymentStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = PaymentStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid PaymentStatus", str)
+	}
+	return nil
+}
+
+func (e PaymentStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ReportInterval string
+
+const (
+	ReportIntervalDay ReportInterval = "DAY"
+	// Weeks start on Monday.
+	ReportIntervalWeek  ReportInterval = "WEEK"
+	ReportIntervalMonth ReportInterval = "MONTH"
+)
+
+var AllReportInterval = []ReportInterval{
+	ReportIntervalDay,
+	ReportIntervalWeek,
+	ReportIntervalMonth,
+}
+
+func (e ReportInterval) IsValid() bool {
+	switch e {
+	case ReportIntervalDay, ReportIntervalWeek, ReportIntervalMonth:
+		return true
+	}
+	return false
+}
+
+func (e ReportInterval) String() string {
+	return string(e)
+}
+
+func (e *ReportInterval) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ReportInterval(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ReportInterval", str)
+	}
+	return nil
+}
+
+func (e ReportInterval) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type SalesReportKind string
+
+const (
+	SalesReportKindBestSellingBooks     SalesReportKind = "BEST_SELLING_BOOKS"
+	SalesReportKindBestSellingAuthors   SalesReportKind = "BEST_SELLING_AUTHORS"
+	SalesReportKindRevenue              SalesReportKind = "REVENUE"
+	SalesReportKindQuantityDistribution SalesReportKind = "QUANTITY_DISTRIBUTION"
+)
+
+var AllSalesReportKind = []SalesReportKind{
+	SalesReportKindBestSellingBooks,
+	SalesReportKindBestSellingAuthors,
+	SalesReportKindRevenue,
+	SalesReportKindQuantityDistribution,
+}
+
+func (e SalesReportKind) IsValid() bool {
+	switch e {
+	case SalesReportKindBestSellingBooks, SalesReportKindBestSellingAuthors, SalesReportKindRevenue, SalesReportKindQuantityDistribution:
+		return true
+	}
+	return false
+}
+
+func (e SalesReportKind) String() string {
+	return string(e)
+}
+
+func (e *SalesReportKind) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SalesReportKind(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SalesReportKind", str)
+	}
+	return nil
+}
+
+func (e SalesReportKind) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
 }
@@ -0,0 +1,9 @@
+package model
+
+import ordersV1 "github.com/iho/bookstore/protos/gen/orders/v1"
+
+// SalesReport carries the filter of a salesReport query to the resolvers of
+// its fields, which each call the orders service.
+type SalesReport struct {
+	Filter *ordersV1.SalesFilter
+}
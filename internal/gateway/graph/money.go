@@ -0,0 +1,19 @@
+package graph
+
+import (
+	"github.com/iho/bookstore/internal/gateway/graph/model"
+	moneyV1 "github.com/iho/bookstore/protos/gen/money/v1"
+)
+
+// moneyFromInput converts an optional amount, nil is left for the service
+// to default.
+func moneyFromInput(in *model.MoneyInput) *moneyV1.Money {
+	if in == nil {
+		return nil
+	}
+	m := &moneyV1.Money{Amount: in.Amount}
+	if in.Currency != nil {
+		m.Currency = *in.Currency
+	}
+	return m
+}
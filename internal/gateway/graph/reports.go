@@ -0,0 +1,15 @@
+package graph
+
+import (
+	"github.com/iho/bookstore/internal/gateway/graph/model"
+	ordersV1 "github.com/iho/bookstore/protos/gen/orders/v1"
+)
+
+// reportIntervalToProto converts an optional interval, nil is left for the
+// service to default.
+func reportIntervalToProto(interval *model.ReportInterval) ordersV1.ReportInterval {
+	if interval == nil {
+		return ordersV1.ReportInterval_REPORT_INTERVAL_UNSPECIFIED
+	}
+	return ordersV1.ReportInterval(ordersV1.ReportInterval_value["REPORT_INTERVAL_"+string(*interval)])
+}
@@ -1,26 +1,56 @@
 package graph
 
 import (
+	"context"
 	"net/http"
 
+	"github.com/99designs/gqlgen/graphql"
 	"github.com/iho/bookstore/internal/cfg"
+	"github.com/iho/bookstore/internal/clients"
+	"github.com/iho/bookstore/internal/gateway/cache"
+	"github.com/iho/bookstore/internal/idempotency"
 	"github.com/iho/bookstore/protos/gen/authors/v1/authorsv1connect"
 	"github.com/iho/bookstore/protos/gen/books/v1/booksv1connect"
+	"github.com/iho/bookstore/protos/gen/cart/v1/cartv1connect"
 	"github.com/iho/bookstore/protos/gen/orders/v1/ordersv1connect"
 )
 
 type Resolver struct {
 	cfg              *cfg.Config
+	caches           *cache.Caches
 	booksv1connect   booksv1connect.BooksServiceClient
 	authorsv1connect authorsv1connect.AuthorsServiceClient
 	ordersv1connect  ordersv1connect.OrdersServiceClient
+	cartv1connect    cartv1connect.CartServiceClient
 }
 
-func NewResolver(cfg *cfg.Config) *Resolver {
+// NewResolver returns the root resolver calling services. Mutations
+// invalidate the entities they change in caches, which may be nil.
+func NewResolver(cfg *cfg.Config, services *clients.Services, caches *cache.Caches) *Resolver {
 	return &Resolver{
 		cfg:              cfg,
-		booksv1connect:   booksv1connect.NewBooksServiceClient(http.DefaultClient, cfg.BookServiceUrl),
-		authorsv1connect: authorsv1connect.NewAuthorsServiceClient(http.DefaultClient, cfg.AuthorSericeUrl),
-		ordersv1connect:  ordersv1connect.NewOrdersServiceClient(http.DefaultClient, cfg.OrderServiceUrl),
+		caches:           caches,
+		booksv1connect:   services.Books,
+		authorsv1connect: services.Authors,
+		ordersv1connect:  services.Orders,
+		cartv1connect:    services.Cart,
 	}
 }
+
+func (r *Resolver) invalidateBook(ctx context.Context, id string) {
+	if r.caches != nil {
+		r.caches.Books.Invalidate(ctx, id)
+	}
+}
+
+func (r *Resolver) invalidateAuthor(ctx context.Context, id string) {
+	if r.caches != nil {
+		r.caches.Authors.Invalidate(ctx, id)
+	}
+}
+
+// forwardIdempotencyKey passes the idempotency key of a create mutation on
+// to the service, scoped to the mutation's alias.
+func forwardIdempotencyKey(ctx context.Context, header http.Header, key *string) {
+	idempotency.Forward(ctx, header, key, graphql.GetFieldContext(ctx).Field.Alias)
+}
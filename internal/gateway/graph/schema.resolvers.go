@@ -13,6 +13,7 @@ import (
 	"github.com/iho/bookstore/internal/gateway/loaders"
 	authorsV1 "github.com/iho/bookstore/protos/gen/authors/v1"
 	booksV1 "github.com/iho/bookstore/protos/gen/books/v1"
+	cartV1 "github.com/iho/bookstore/protos/gen/cart/v1"
 	ordersV1 "github.com/iho/bookstore/protos/gen/orders/v1"
 )
 
@@ -22,8 +23,11 @@ func (r *mutationResolver) CreateBook(ctx context.Context, input model.CreateBoo
 		Title:         input.Title,
 		AuthorId:      input.AuthorID,
 		PublishedDate: input.PublishedDate,
+		Price:         moneyFromInput(input.Price),
 	})
 
+	forwardIdempotencyKey(ctx, req.Header(), input.IdempotencyKey)
+
 	res, err := r.booksv1connect.CreateBook(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create book: %w", err)
@@ -46,6 +50,7 @@ func (r *mutationResolver) CreateBook(ctx context.Context, input model.CreateBoo
 			Name: authorRes.Msg.Author.Name,
 		},
 		PublishedDate: res.Msg.Book.PublishedDate,
+		Price:         loaders.MoneyFromProto(res.Msg.Book.Price),
 	}, nil
 }
 
@@ -57,9 +62,11 @@ func (r *mutationResolver) UpdateBook(ctx context.Context, input model.UpdateBoo
 		Title:         input.Title,
 		AuthorId:      input.AuthorID,
 		PublishedDate: input.PublishedDate,
+		Price:         moneyFromInput(input.Price),
 	})
 
 	res, err := r.booksv1connect.UpdateBook(ctx, req)
+	r.invalidateBook(ctx, input.ID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update book: %w", err)
 	}
@@ -81,6 +88,7 @@ func (r *mutationResolver) UpdateBook(ctx context.Context, input model.UpdateBoo
 			Name: authorRes.Msg.Author.Name,
 		},
 		PublishedDate: res.Msg.Book.PublishedDate,
+		Price:         loaders.MoneyFromProto(res.Msg.Book.Price),
 	}, nil
 }
 
@@ -91,6 +99,7 @@ func (r *mutationResolver) DeleteBook(ctx context.Context, input model.DeleteBoo
 	})
 
 	res, err := r.booksv1connect.DeleteBook(ctx, req)
+	r.invalidateBook(ctx, input.ID)
 	if err != nil {
 		return false, fmt.Errorf("failed to delete book: %w", err)
 	}
@@ -104,6 +113,8 @@ func (r *mutationResolver) CreateAuthor(ctx context.Context, input model.CreateA
 		Name: input.Name,
 	})
 
+	forwardIdempotencyKey(ctx, req.Header(), input.IdempotencyKey)
+
 	res, err := r.authorsv1connect.CreateAuthor(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create author: %w", err)
@@ -124,6 +135,7 @@ func (r *mutationResolver) UpdateAuthor(ctx context.Context, input model.UpdateA
 	})
 
 	res, err := r.authorsv1connect.UpdateAuthor(ctx, req)
+	r.invalidateAuthor(ctx, input.ID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update author: %w", err)
 	}
@@ -142,6 +154,7 @@ func (r *mutationResolver) DeleteAuthor(ctx context.Context, input model.DeleteA
 	})
 
 	res, err := r.authorsv1connect.DeleteAuthor(ctx, req)
+	r.invalidateAuthor(ctx, input.ID)
 	if err != nil {
 		return false, fmt.Errorf("failed to delete author: %w", err)
 	}
@@ -161,37 +174,59 @@ func (r *mutationResolver) CreateOrder(ctx context.Context, input model.CreateOr
 	}
 
 	req := connect.NewRequest(&ordersV1.CreateOrderRequest{
-		OrderLines: orderLinesInput,
-		OrderDate:  input.OrderDate,
-		TotalPrice: int32(input.TotalPrice),
+		OrderLines:      orderLinesInput,
+		OrderDate:       input.OrderDate,
+		ShippingAddress: addressFromInput(input.ShippingAddress),
+		BillingAddress:  addressFromInput(input.BillingAddress),
 	})
+	if input.CouponCode != nil {
+		req.Msg.CouponCode = *input.CouponCode
+	}
+	if input.Currency != nil {
+		req.Msg.Currency = *input.Currency
+	}
+	if input.TaxRegion != nil {
+		req.Msg.TaxRegion = *input.TaxRegion
+	}
+	if input.CustomerID != nil {
+		req.Msg.CustomerId = *input.CustomerID
+	}
+
+	forwardIdempotencyKey(ctx, req.Header(), input.IdempotencyKey)
 
 	res, err := r.ordersv1connect.CreateOrder(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create order: %w", err)
 	}
 
-	orderLines := make([]*model.OrderLine, len(res.Msg.Order.OrderLines))
+	return loaders.OrderFromProto(res.Msg.Order), nil
+}
 
-	for i, line := range res.Msg.Order.OrderLines {
-		orderLines[i] = &model.OrderLine{
-			BookID:   line.BookId,
-			Quantity: int(line.Quantity),
+// UpdateOrder is the resolver for the updateOrder field.
+func (r *mutationResolver) UpdateOrder(ctx context.Context, input model.UpdateOrderInput) (*model.Order, error) {
+	orderLinesInput := make([]*ordersV1.OrderLine, len(input.OrderLines))
+	for i, line := range input.OrderLines {
+		orderLinesInput[i] = &ordersV1.OrderLine{
+			BookId:   line.BookID,
+			Quantity: int32(line.Quantity),
 		}
 	}
 
-	return &model.Order{
-		ID:         res.Msg.Order.Id,
-		Quantity:   len(res.Msg.Order.OrderLines),
-		OrderLines: orderLines,
-		TotalPrice: int(res.Msg.Order.TotalPrice),
-		OrderDate:  res.Msg.Order.OrderDate,
-	}, nil
-}
+	req := connect.NewRequest(&ordersV1.UpdateOrderRequest{
+		Id:              input.ID,
+		OrderLines:      orderLinesInput,
+		OrderDate:       input.OrderDate,
+		ShippingAddress: addressFromInput(input.ShippingAddress),
+		BillingAddress:  addressFromInput(input.BillingAddress),
+		CouponCode:      input.CouponCode,
+	})
 
-// UpdateOrder is the resolver for the updateOrder field.
-func (r *mutationResolver) UpdateOrder(ctx context.Context, input model.UpdateOrderInput) (*model.Order, error) {
-	panic(fmt.Errorf("not implemented: UpdateOrder - updateOrder"))
+	res, err := r.ordersv1connect.UpdateOrder(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update order: %w", err)
+	}
+
+	return loaders.OrderFromProto(res.Msg.Order), nil
 }
 
 // DeleteOrder is the resolver for the deleteOrder field.
@@ -208,6 +243,205 @@ func (r *mutationResolver) DeleteOrder(ctx context.Context, input model.DeleteOr
 	return res.Msg.Status, nil
 }
 
+// CreateCart is the resolver for the createCart field.
+func (r *mutationResolver) CreateCart(ctx context.Context, input *model.CreateCartInput) (*model.Cart, error) {
+	req := connect.NewRequest(&cartV1.CreateCartRequest{})
+
+	var key *string
+	if input != nil {
+		key = input.IdempotencyKey
+	}
+	forwardIdempotencyKey(ctx, req.Header(), key)
+
+	res, err := r.cartv1connect.CreateCart(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cart: %w", err)
+	}
+
+	return cartFromProto(res.Msg.Cart), nil
+}
+
+// AddCartItem is the resolver for the addCartItem field.
+func (r *mutationResolver) AddCartItem(ctx context.Context, input model.CartItemInput) (*model.Cart, error) {
+	req := connect.NewRequest(&cartV1.AddItemRequest{
+		CartId:   input.CartID,
+		BookId:   input.BookID,
+		Quantity: int32(input.Quantity),
+	})
+
+	res, err := r.cartv1connect.AddItem(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add cart item: %w", err)
+	}
+
+	return cartFromProto(res.Msg.Cart), nil
+}
+
+// RemoveCartItem is the resolver for the removeCartItem field.
+func (r *mutationResolver) RemoveCartItem(ctx context.Context, input model.RemoveCartItemInput) (*model.Cart, error) {
+	req := connect.NewRequest(&cartV1.RemoveItemRequest{
+		CartId: input.CartID,
+		BookId: input.BookID,
+	})
+
+	res, err := r.cartv1connect.RemoveItem(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove cart item: %w", err)
+	}
+
+	return cartFromProto(res.Msg.Cart), nil
+}
+
+// SetCartItemQuantity is the resolver for the setCartItemQuantity field.
+func (r *mutationResolver) SetCartItemQuantity(ctx context.Context, input model.CartItemInput) (*model.Cart, error) {
+	req := connect.NewRequest(&cartV1.SetItemQuantityRequest{
+		CartId:   input.CartID,
+		BookId:   input.BookID,
+		Quantity: int32(input.Quantity),
+	})
+
+	res, err := r.cartv1connect.SetItemQuantity(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set cart item quantity: %w", err)
+	}
+
+	return cartFromProto(res.Msg.Cart), nil
+}
+
+// Checkout is the resolver for the checkout field.
+func (r *mutationResolver) Checkout(ctx context.Context, cartID string, couponCode *string, currency *string, taxRegion *string, shippingAddress *model.AddressInput, billingAddress *model.AddressInput, customerID *string) (*model.Order, error) {
+	req := connect.NewRequest(&cartV1.CheckoutRequest{
+		CartId:          cartID,
+		ShippingAddress: addressFromInput(shippingAddress),
+		BillingAddress:  addressFromInput(billingAddress),
+	})
+	if couponCode != nil {
+		req.Msg.CouponCode = *couponCode
+	}
+	if currency != nil {
+		req.Msg.Currency = *currency
+	}
+	if taxRegion != nil {
+		req.Msg.TaxRegion = *taxRegion
+	}
+	if customerID != nil {
+		req.Msg.CustomerId = *customerID
+	}
+
+	res, err := r.cartv1connect.Checkout(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check out cart: %w", err)
+	}
+
+	return loaders.OrderFromProto(res.Msg.Order), nil
+}
+
+// AuthorizePayment is the resolver for the authorizePayment field.
+func (r *mutationResolver) AuthorizePayment(ctx context.Context, orderID string) (*model.Order, error) {
+	req := connect.NewRequest(&ordersV1.AuthorizePaymentRequest{
+		OrderId: orderID,
+	})
+
+	res, err := r.ordersv1connect.AuthorizePayment(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to authorize payment: %w", err)
+	}
+
+	return loaders.OrderFromProto(res.Msg.Order), nil
+}
+
+// CapturePayment is the resolver for the capturePayment field.
+func (r *mutationResolver) CapturePayment(ctx context.Context, orderID string) (*model.Order, error) {
+	req := connect.NewRequest(&ordersV1.CapturePaymentRequest{
+		OrderId: orderID,
+	})
+
+	res, err := r.ordersv1connect.CapturePayment(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to capture payment: %w", err)
+	}
+
+	return loaders.OrderFromProto(res.Msg.Order), nil
+}
+
+// RefundPayment is the resolver for the refundPayment field.
+func (r *mutationResolver) RefundPayment(ctx context.Context, orderID string) (*model.Order, error) {
+	req := connect.NewRequest(&ordersV1.RefundPaymentRequest{
+		OrderId: orderID,
+	})
+
+	res, err := r.ordersv1connect.RefundPayment(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to refund payment: %w", err)
+	}
+
+	return loaders.OrderFromProto(res.Msg.Order), nil
+}
+
+// VoidPayment is the resolver for the voidPayment field.
+func (r *mutationResolver) VoidPayment(ctx context.Context, orderID string) (*model.Order, error) {
+	req := connect.NewRequest(&ordersV1.VoidPaymentRequest{
+		OrderId: orderID,
+	})
+
+	res, err := r.ordersv1connect.VoidPayment(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to void payment: %w", err)
+	}
+
+	return loaders.OrderFromProto(res.Msg.Order), nil
+}
+
+// CreateShipment is the resolver for the createShipment field.
+func (r *mutationResolver) CreateShipment(ctx context.Context, input model.CreateShipmentInput) (*model.Order, error) {
+	lines := make([]*ordersV1.ShipmentLine, len(input.Lines))
+	for i, line := range input.Lines {
+		lines[i] = &ordersV1.ShipmentLine{
+			BookId:   line.BookID,
+			Quantity: int32(line.Quantity),
+		}
+	}
+
+	req := connect.NewRequest(&ordersV1.CreateShipmentRequest{
+		OrderId: input.OrderID,
+		Carrier: input.Carrier,
+		Lines:   lines,
+	})
+	if input.TrackingNumber != nil {
+		req.Msg.TrackingNumber = *input.TrackingNumber
+	}
+	if input.ShippedAt != nil {
+		req.Msg.ShippedAt = *input.ShippedAt
+	}
+
+	forwardIdempotencyKey(ctx, req.Header(), input.IdempotencyKey)
+
+	res, err := r.ordersv1connect.CreateShipment(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create shipment: %w", err)
+	}
+
+	return loaders.OrderFromProto(res.Msg.Order), nil
+}
+
+// DeliverShipment is the resolver for the deliverShipment field.
+func (r *mutationResolver) DeliverShipment(ctx context.Context, input model.DeliverShipmentInput) (*model.Order, error) {
+	req := connect.NewRequest(&ordersV1.DeliverShipmentRequest{
+		OrderId:    input.OrderID,
+		ShipmentId: input.ShipmentID,
+	})
+	if input.DeliveredAt != nil {
+		req.Msg.DeliveredAt = *input.DeliveredAt
+	}
+
+	res, err := r.ordersv1connect.DeliverShipment(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to deliver shipment: %w", err)
+	}
+
+	return loaders.OrderFromProto(res.Msg.Order), nil
+}
+
 // Books is the resolver for the books field.
 func (r *queryResolver) Books(ctx context.Context, input *model.BooksQueryInput) ([]*model.Book, error) {
 	loaders := loaders.For(ctx)
@@ -244,11 +478,318 @@ func (r *queryResolver) Order(ctx context.Context, input *model.OrderQueryInput)
 	return loaders.OrderLoader.Load(ctx, input.ID)
 }
 
+// Cart is the resolver for the cart field.
+func (r *queryResolver) Cart(ctx context.Context, id string) (*model.Cart, error) {
+	req := connect.NewRequest(&cartV1.GetCartRequest{
+		Id: id,
+	})
+
+	res, err := r.cartv1connect.GetCart(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cart: %w", err)
+	}
+
+	return cartFromProto(res.Msg.Cart), nil
+}
+
+// PreviewOrder is the resolver for the previewOrder field.
+func (r *queryResolver) PreviewOrder(ctx context.Context, input model.PreviewOrderInput) (*model.OrderPreview, error) {
+	orderLinesInput := make([]*ordersV1.OrderLine, len(input.OrderLines))
+	for i, line := range input.OrderLines {
+		orderLinesInput[i] = &ordersV1.OrderLine{
+			BookId:   line.BookID,
+			Quantity: int32(line.Quantity),
+		}
+	}
+
+	req := connect.NewRequest(&ordersV1.PriceOrderRequest{
+		OrderLines:      orderLinesInput,
+		ShippingAddress: addressFromInput(input.ShippingAddress),
+	})
+	if input.CouponCode != nil {
+		req.Msg.CouponCode = *input.CouponCode
+	}
+	if input.Currency != nil {
+		req.Msg.Currency = *input.Currency
+	}
+	if input.TaxRegion != nil {
+		req.Msg.TaxRegion = *input.TaxRegion
+	}
+
+	res, err := r.ordersv1connect.PriceOrder(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to price order: %w", err)
+	}
+
+	order := loaders.OrderFromProto(res.Msg.Order)
+	return &model.OrderPreview{
+		OrderLines:    order.OrderLines,
+		SubtotalPrice: order.SubtotalPrice,
+		DiscountTotal: order.DiscountTotal,
+		TaxTotal:      order.TaxTotal,
+		TotalPrice:    order.TotalPrice,
+		CouponCode:    order.CouponCode,
+		TaxRegion:     order.TaxRegion,
+	}, nil
+}
+
+// OrderStatusChanged is the resolver for the orderStatusChanged field.
+func (r *subscriptionResolver) OrderStatusChanged(ctx context.Context, id string) (<-chan *model.Order, error) {
+	stream, err := r.ordersv1connect.WatchOrders(ctx, connect.NewRequest(&ordersV1.WatchOrdersRequest{}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch orders: %w", err)
+	}
+
+	ch := make(chan *model.Order, subscriptionBuffer)
+	go func() {
+		defer close(ch)
+		forward(ctx, "orderStatusChanged", stream, ch, func(msg *ordersV1.WatchOrdersResponse) (*model.Order, bool, bool) {
+			if msg.Order.GetId() != id {
+				return nil, false, false
+			}
+			if msg.Type == ordersV1.EventType_EVENT_TYPE_DELETED {
+				return nil, false, true
+			}
+			return loaders.OrderFromProto(msg.Order), true, false
+		})
+	}()
+
+	return ch, nil
+}
+
+// BookAdded is the resolver for the bookAdded field.
+func (r *subscriptionResolver) BookAdded(ctx context.Context, authorID string) (<-chan *model.Book, error) {
+	author, err := r.author(ctx, authorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get author: %w", err)
+	}
+
+	stream, err := r.booksv1connect.WatchBooks(ctx, connect.NewRequest(&booksV1.WatchBooksRequest{}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch books: %w", err)
+	}
+
+	ch := make(chan *model.Book, subscriptionBuffer)
+	go func() {
+		defer close(ch)
+		forward(ctx, "bookAdded", stream, ch, func(msg *booksV1.WatchBooksResponse) (*model.Book, bool, bool) {
+			if msg.Type != booksV1.EventType_EVENT_TYPE_CREATED || msg.Book.GetAuthorId() != authorID {
+				return nil, false, false
+			}
+			return &model.Book{
+				ID:            msg.Book.Id,
+				Title:         msg.Book.Title,
+				Author:        author,
+				PublishedDate: msg.Book.PublishedDate,
+				Price:         loaders.MoneyFromProto(msg.Book.Price),
+			}, true, false
+		})
+	}()
+
+	return ch, nil
+}
+
+// CatalogChanged is the resolver for the catalogChanged field.
+func (r *subscriptionResolver) CatalogChanged(ctx context.Context) (<-chan *model.CatalogEvent, error) {
+	books, err := r.booksv1connect.WatchBooks(ctx, connect.NewRequest(&booksV1.WatchBooksRequest{}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch books: %w", err)
+	}
+
+	authors, err := r.authorsv1connect.WatchAuthors(ctx, connect.NewRequest(&authorsV1.WatchAuthorsRequest{}))
+	if err != nil {
+		books.Close()
+		return nil, fmt.Errorf("failed to watch authors: %w", err)
+	}
+
+	return r.catalogChanged(ctx, books, authors), nil
+}
+
+// SalesReport is the resolver for the salesReport field.
+func (r *queryResolver) SalesReport(ctx context.Context, input *model.SalesReportInput) (*model.SalesReport, error) {
+	filter := &ordersV1.SalesFilter{}
+	if input != nil {
+		if input.From != nil {
+			filter.From = *input.From
+		}
+		if input.To != nil {
+			filter.To = *input.To
+		}
+		if input.Currency != nil {
+			filter.Currency = *input.Currency
+		}
+		for _, s := range input.Statuses {
+			filter.Statuses = append(filter.Statuses, ordersV1.OrderStatus(ordersV1.OrderStatus_value["ORDER_STATUS_"+string(s)]))
+		}
+	}
+
+	return &model.SalesReport{Filter: filter}, nil
+}
+
+// BestSellingBooks is the resolver for the bestSellingBooks field.
+func (r *salesReportResolver) BestSellingBooks(ctx context.Context, obj *model.SalesReport, limit *int) ([]*model.BookSales, error) {
+	req := connect.NewRequest(&ordersV1.ListBestSellingBooksRequest{
+		Filter: obj.Filter,
+	})
+	if limit != nil {
+		req.Msg.Limit = int32(*limit)
+	}
+
+	res, err := r.ordersv1connect.ListBestSellingBooks(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list best selling books: %w", err)
+	}
+
+	books := make([]*model.BookSales, len(res.Msg.Books))
+	for i, b := range res.Msg.Books {
+		books[i] = &model.BookSales{
+			BookID:   b.BookId,
+			Quantity: b.Quantity,
+			Orders:   b.Orders,
+			Revenue:  loaders.MoneyFromProto(b.Revenue),
+		}
+	}
+	return books, nil
+}
+
+// BestSellingAuthors is the resolver for the bestSellingAuthors field.
+func (r *salesReportResolver) BestSellingAuthors(ctx context.Context, obj *model.SalesReport, limit *int) ([]*model.AuthorSales, error) {
+	req := connect.NewRequest(&ordersV1.ListBestSellingAuthorsRequest{
+		Filter: obj.Filter,
+	})
+	if limit != nil {
+		req.Msg.Limit = int32(*limit)
+	}
+
+	res, err := r.ordersv1connect.ListBestSellingAuthors(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list best selling authors: %w", err)
+	}
+
+	authors := make([]*model.AuthorSales, len(res.Msg.Authors))
+	for i, a := range res.Msg.Authors {
+		authors[i] = &model.AuthorSales{
+			AuthorID: a.AuthorId,
+			Quantity: a.Quantity,
+			Orders:   a.Orders,
+			Books:    a.Books,
+			Revenue:  loaders.MoneyFromProto(a.Revenue),
+		}
+	}
+	return authors, nil
+}
+
+// Revenue is the resolver for the revenue field.
+func (r *salesReportResolver) Revenue(ctx context.Context, obj *model.SalesReport, interval *model.ReportInterval) (*model.Revenue, error) {
+	req := connect.NewRequest(&ordersV1.GetRevenueRequest{
+		Filter:   obj.Filter,
+		Interval: reportIntervalToProto(interval),
+	})
+
+	res, err := r.ordersv1connect.GetRevenue(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get revenue: %w", err)
+	}
+
+	periods := make([]*model.RevenuePeriod, len(res.Msg.Periods))
+	for i, p := range res.Msg.Periods {
+		periods[i] = &model.RevenuePeriod{
+			Start:         p.Start,
+			Orders:        p.Orders,
+			SubtotalPrice: loaders.MoneyFromProto(p.SubtotalPrice),
+			DiscountTotal: loaders.MoneyFromProto(p.DiscountTotal),
+			TaxTotal:      loaders.MoneyFromProto(p.TaxTotal),
+			TotalPrice:    loaders.MoneyFromProto(p.TotalPrice),
+		}
+	}
+	return &model.Revenue{
+		Periods:    periods,
+		TotalPrice: loaders.MoneyFromProto(res.Msg.TotalPrice),
+	}, nil
+}
+
+// OrderStats is the resolver for the orderStats field.
+func (r *salesReportResolver) OrderStats(ctx context.Context, obj *model.SalesReport) (*model.OrderStats, error) {
+	req := connect.NewRequest(&ordersV1.GetOrderStatsRequest{
+		Filter: obj.Filter,
+	})
+
+	res, err := r.ordersv1connect.GetOrderStats(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order stats: %w", err)
+	}
+
+	buckets := make([]*model.QuantityBucket, len(res.Msg.QuantityDistribution))
+	for i, b := range res.Msg.QuantityDistribution {
+		buckets[i] = &model.QuantityBucket{
+			MinQuantity: int(b.MinQuantity),
+			Orders:      b.Orders,
+		}
+		if b.MaxQuantity > 0 {
+			upper := int(b.MaxQuantity)
+			buckets[i].MaxQuantity = &upper
+		}
+	}
+	return &model.OrderStats{
+		Orders:               res.Msg.Orders,
+		TotalPrice:           loaders.MoneyFromProto(res.Msg.TotalPrice),
+		AverageOrderValue:    loaders.MoneyFromProto(res.Msg.AverageOrderValue),
+		Quantity:             res.Msg.Quantity,
+		AverageQuantity:      res.Msg.AverageQuantity,
+		QuantityDistribution: buckets,
+	}, nil
+}
+
+// CSV is the resolver for the csv field.
+func (r *salesReportResolver) CSV(ctx context.Context, obj *model.SalesReport, report model.SalesReportKind, limit *int, interval *model.ReportInterval) (string, error) {
+	req := connect.NewRequest(&ordersV1.ExportSalesReportRequest{
+		Filter:   obj.Filter,
+		Report:   ordersV1.SalesReport(ordersV1.SalesReport_value["SALES_REPORT_"+string(report)]),
+		Interval: reportIntervalToProto(interval),
+	})
+	if limit != nil {
+		req.Msg.Limit = int32(*limit)
+	}
+
+	res, err := r.ordersv1connect.ExportSalesReport(ctx, req)
+	if err != nil {
+		return "", fmt.Errorf("failed to export sales report: %w", err)
+	}
+
+	return string(res.Msg.Csv), nil
+}
+
+// Book is the resolver for the book field.
+func (r *bookSalesResolver) Book(ctx context.Context, obj *model.BookSales) (*model.Book, error) {
+	return loaders.GetBook(ctx, obj.BookID)
+}
+
+// Author is the resolver for the author field.
+func (r *authorSalesResolver) Author(ctx context.Context, obj *model.AuthorSales) (*model.Author, error) {
+	return loaders.GetAuthor(ctx, obj.AuthorID)
+}
+
 // Mutation returns MutationResolver implementation.
 func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
 
 // Query returns QueryResolver implementation.
 func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
 
+// SalesReport returns SalesReportResolver implementation.
+func (r *Resolver) SalesReport() SalesReportResolver { return &salesReportResolver{r} }
+
+// BookSales returns BookSalesResolver implementation.
+func (r *Resolver) BookSales() BookSalesResolver { return &bookSalesResolver{r} }
+
+// AuthorSales returns AuthorSalesResolver implementation.
+func (r *Resolver) AuthorSales() AuthorSalesResolver { return &authorSalesResolver{r} }
+
+// Subscription returns SubscriptionResolver implementation.
+func (r *Resolver) Subscription() SubscriptionResolver { return &subscriptionResolver{r} }
+
 type mutationResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
+type salesReportResolver struct{ *Resolver }
+type bookSalesResolver struct{ *Resolver }
+type authorSalesResolver struct{ *Resolver }
+type subscriptionResolver struct{ *Resolver }
@@ -0,0 +1,140 @@
+package graph
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"connectrpc.com/connect"
+	"github.com/iho/bookstore/internal/gateway/graph/model"
+	"github.com/iho/bookstore/internal/gateway/loaders"
+	authorsV1 "github.com/iho/bookstore/protos/gen/authors/v1"
+	booksV1 "github.com/iho/bookstore/protos/gen/books/v1"
+)
+
+// subscriptionBuffer is how many events may queue up for a slow websocket
+// client. Once it is full the gateway stops reading the downstream stream,
+// which pushes back on the service through HTTP/2 flow control.
+const subscriptionBuffer = 16
+
+// watchStream is the client side of a server-streaming watch RPC.
+type watchStream[T any] interface {
+	Receive() bool
+	Msg() *T
+	Err() error
+	Close() error
+}
+
+// forward reads stream until it ends or ctx is done and sends every event
+// convert accepts to ch. convert reports whether to send the event and
+// whether it is the last one.
+func forward[T, E any](ctx context.Context, name string, stream watchStream[T], ch chan<- E, convert func(*T) (event E, send bool, last bool)) {
+	defer stream.Close()
+
+	for stream.Receive() {
+		event, send, last := convert(stream.Msg())
+		if send {
+			select {
+			case ch <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+		if last {
+			return
+		}
+	}
+
+	if err := stream.Err(); err != nil && ctx.Err() == nil {
+		slog.WarnContext(ctx, "subscription ended", "subscription", name, "error", err)
+	}
+}
+
+var changeTypes = map[string]model.ChangeType{
+	"EVENT_TYPE_CREATED": model.ChangeTypeCreated,
+	"EVENT_TYPE_UPDATED": model.ChangeTypeUpdated,
+	"EVENT_TYPE_DELETED": model.ChangeTypeDeleted,
+}
+
+// author fetches a single author for an event. Subscriptions run outside of
+// a request, so they cannot use the request scoped data loaders.
+func (r *Resolver) author(ctx context.Context, id string) (*model.Author, error) {
+	res, err := r.authorsv1connect.GetAuthor(ctx, connect.NewRequest(&authorsV1.GetAuthorRequest{Id: id}))
+	if err != nil {
+		return nil, err
+	}
+	return &model.Author{
+		ID:   res.Msg.Author.Id,
+		Name: res.Msg.Author.Name,
+	}, nil
+}
+
+func (r *Resolver) bookEvent(ctx context.Context, msg *booksV1.WatchBooksResponse) *model.CatalogEvent {
+	event := &model.CatalogEvent{
+		Type:   changeTypes[msg.Type.String()],
+		BookID: &msg.Book.Id,
+	}
+	if msg.Type == booksV1.EventType_EVENT_TYPE_DELETED {
+		return event
+	}
+
+	author, err := r.author(ctx, msg.Book.AuthorId)
+	if err != nil {
+		// keep the event, the author may have been deleted since
+		author = &model.Author{ID: msg.Book.AuthorId}
+	}
+	event.Book = &model.Book{
+		ID:            msg.Book.Id,
+		Title:         msg.Book.Title,
+		Author:        author,
+		PublishedDate: msg.Book.PublishedDate,
+		Price:         loaders.MoneyFromProto(msg.Book.Price),
+	}
+	return event
+}
+
+func authorEvent(msg *authorsV1.WatchAuthorsResponse) *model.CatalogEvent {
+	event := &model.CatalogEvent{
+		Type:     changeTypes[msg.Type.String()],
+		AuthorID: &msg.Author.Id,
+	}
+	if msg.Type != authorsV1.EventType_EVENT_TYPE_DELETED {
+		event.Author = &model.Author{
+			ID:   msg.Author.Id,
+			Name: msg.Author.Name,
+		}
+	}
+	return event
+}
+
+// catalogChanged merges the books and authors watch streams into ch and
+// closes it once both have ended.
+func (r *Resolver) catalogChanged(ctx context.Context, books watchStream[booksV1.WatchBooksResponse], authors watchStream[authorsV1.WatchAuthorsResponse]) <-chan *model.CatalogEvent {
+	ch := make(chan *model.CatalogEvent, subscriptionBuffer)
+	ctx, cancel := context.WithCancel(ctx)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		// if one stream fails the subscription ends rather than silently
+		// dropping half of the events
+		defer cancel()
+		forward(ctx, "catalogChanged", books, ch, func(msg *booksV1.WatchBooksResponse) (*model.CatalogEvent, bool, bool) {
+			return r.bookEvent(ctx, msg), true, false
+		})
+	}()
+	go func() {
+		defer wg.Done()
+		defer cancel()
+		forward(ctx, "catalogChanged", authors, ch, func(msg *authorsV1.WatchAuthorsResponse) (*model.CatalogEvent, bool, bool) {
+			return authorEvent(msg), true, false
+		})
+	}()
+	go func() {
+		wg.Wait()
+		close(ch)
+	}()
+
+	return ch
+}
@@ -0,0 +1,182 @@
+// Package limits holds gqlgen extensions that bound the work a single
+// GraphQL operation can cause.
+package limits
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/iho/bookstore/internal/ratelimit"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// Error codes set in the "code" extension of errors returned to clients.
+const (
+	CodeDepthLimitExceeded = "DEPTH_LIMIT_EXCEEDED"
+	CodeOperationTimeout   = "OPERATION_TIMEOUT"
+	CodeRateLimited        = "RATE_LIMITED"
+)
+
+// DepthLimit rejects operations whose selections nest deeper than Max.
+// Fragments count as if they were inlined. Introspection fields are not
+// counted; the standard introspection query is deeper than any sensible
+// limit.
+type DepthLimit struct {
+	Max int
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationContextMutator
+} = DepthLimit{}
+
+func (d DepthLimit) ExtensionName() string {
+	return "DepthLimit"
+}
+
+func (d DepthLimit) Validate(graphql.ExecutableSchema) error {
+	if d.Max < 1 {
+		return errors.New("depth limit must be at least 1")
+	}
+	return nil
+}
+
+func (d DepthLimit) MutateOperationContext(ctx context.Context, oc *graphql.OperationContext) *gqlerror.Error {
+	if oc.Operation == nil {
+		return nil
+	}
+
+	if depth := selectionDepth(oc.Operation.SelectionSet, d.Max); depth > d.Max {
+		err := gqlerror.Errorf("operation exceeds the depth limit of %d", d.Max)
+		errcode.Set(err, CodeDepthLimitExceeded)
+		return err
+	}
+	return nil
+}
+
+// selectionDepth returns the depth of set, stopping once it exceeds max so
+// that recursive fragments, which validation rejects anyway, terminate.
+func selectionDepth(set ast.SelectionSet, max int) int {
+	if max < 0 {
+		return 0
+	}
+
+	depth := 0
+	for _, selection := range set {
+		var d int
+		switch s := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(s.Name, "__") {
+				continue
+			}
+			if len(s.SelectionSet) == 0 {
+				d = 1
+			} else {
+				d = 1 + selectionDepth(s.SelectionSet, max-1)
+			}
+		case *ast.InlineFragment:
+			d = selectionDepth(s.SelectionSet, max)
+		case *ast.FragmentSpread:
+			if s.Definition != nil {
+				d = selectionDepth(s.Definition.SelectionSet, max)
+			}
+		}
+		if d > depth {
+			depth = d
+		}
+	}
+	return depth
+}
+
+// Timeout bounds the time spent producing the response of a query or
+// mutation. Downstream calls see the deadline through their context.
+// Subscriptions are long-lived and not limited.
+type Timeout struct {
+	Duration time.Duration
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationInterceptor
+} = Timeout{}
+
+func (t Timeout) ExtensionName() string {
+	return "Timeout"
+}
+
+func (t Timeout) Validate(graphql.ExecutableSchema) error {
+	if t.Duration <= 0 {
+		return errors.New("operation timeout must be positive")
+	}
+	return nil
+}
+
+func (t Timeout) InterceptOperation(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	oc := graphql.GetOperationContext(ctx)
+	if oc.Operation != nil && oc.Operation.Operation == ast.Subscription {
+		return next(ctx)
+	}
+
+	handler := next(ctx)
+	return func(ctx context.Context) *graphql.Response {
+		ctx, cancel := context.WithTimeout(ctx, t.Duration)
+		defer cancel()
+
+		res := handler(ctx)
+		if res != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			err := gqlerror.Errorf("operation did not complete within %s", t.Duration)
+			errcode.Set(err, CodeOperationTimeout)
+			res.Errors = append(res.Errors, err)
+		}
+		return res
+	}
+}
+
+// RateLimit applies the limits of Limiter named after GraphQL operations.
+// The default limit per client is enforced by the Limiter's HTTP
+// middleware, which also turns rejections into 429 responses.
+type RateLimit struct {
+	Limiter *ratelimit.Limiter
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationContextMutator
+} = RateLimit{}
+
+func (l RateLimit) ExtensionName() string {
+	return "RateLimit"
+}
+
+func (l RateLimit) Validate(graphql.ExecutableSchema) error {
+	if l.Limiter == nil {
+		return errors.New("rate limit needs a limiter")
+	}
+	return nil
+}
+
+func (l RateLimit) MutateOperationContext(ctx context.Context, oc *graphql.OperationContext) *gqlerror.Error {
+	client, ok := ratelimit.Client(ctx)
+	if !ok || oc.Operation == nil {
+		return nil
+	}
+	name := oc.OperationName
+	if name == "" {
+		name = oc.Operation.Name
+	}
+
+	retryAfter := l.Limiter.AllowNamed(ctx, client, name)
+	if retryAfter == 0 {
+		return nil
+	}
+	ratelimit.Reject(ctx, retryAfter)
+	err := gqlerror.Errorf("operation %s is rate limited", name)
+	errcode.Set(err, CodeRateLimited)
+	err.Extensions["retryAfter"] = retryAfter.Seconds()
+	return err
+}
@@ -2,15 +2,14 @@ package loaders
 
 import (
 	"context"
-	"net/http"
 	"time"
 
 	"connectrpc.com/connect"
-	"github.com/iho/bookstore/internal/cfg"
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/iho/bookstore/internal/clients"
+	"github.com/iho/bookstore/internal/gateway/cache"
 	"github.com/iho/bookstore/internal/gateway/graph/model"
-	"github.com/iho/bookstore/protos/gen/authors/v1/authorsv1connect"
 	"github.com/iho/bookstore/protos/gen/books/v1/booksv1connect"
-	"github.com/iho/bookstore/protos/gen/orders/v1/ordersv1connect"
 	"github.com/vikstrous/dataloadgen"
 
 	booksV1 "github.com/iho/bookstore/protos/gen/books/v1"
@@ -22,14 +21,16 @@ const (
 	loadersKey = ctxKey("dataloaders")
 )
 
-// Middleware injects data loaders into the context
-func Middleware(conn *cfg.Config, next http.Handler) http.Handler {
-	// return a middleware that injects the loader to the request context
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		loader := NewLoaders(conn)
-		r = r.WithContext(context.WithValue(r.Context(), loadersKey, loader))
-		next.ServeHTTP(w, r)
-	})
+// ResponseMiddleware injects fresh data loaders for every GraphQL response.
+// Unlike an HTTP middleware it also covers websocket transports, and every
+// event of a subscription gets its own loaders instead of sharing one cache
+// for the lifetime of the connection. The loaders share the clients in
+// services, and books and authors are additionally cached across requests
+// in caches, which may be nil.
+func ResponseMiddleware(services *clients.Services, caches *cache.Caches) graphql.ResponseMiddleware {
+	return func(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+		return next(context.WithValue(ctx, loadersKey, NewLoaders(services, caches)))
+	}
 }
 
 // For returns the dataloader for a given context
@@ -57,32 +58,21 @@ type Loaders struct {
 }
 
 // NewLoaders instantiates data loaders for the middleware
-func NewLoaders(cfg *cfg.Config) *Loaders {
-	bl := &bookLoader{
-		booksv1connect: booksv1connect.NewBooksServiceClient(
-			http.DefaultClient,
-			cfg.BookServiceUrl,
-		),
-	}
-
-	al := &authorLoader{
-		authorsv1connect: authorsv1connect.NewAuthorsServiceClient(
-			http.DefaultClient,
-			cfg.AuthorSericeUrl,
-		),
-	}
-
-	ol := &orderLoader{
-		ordersv1connect: ordersv1connect.NewOrdersServiceClient(
-			http.DefaultClient,
-			cfg.OrderServiceUrl,
-		),
+func NewLoaders(services *clients.Services, caches *cache.Caches) *Loaders {
+	bl := &bookLoader{booksv1connect: services.Books}
+	al := &authorLoader{authorsv1connect: services.Authors}
+	ol := &orderLoader{ordersv1connect: services.Orders}
+
+	var books *cache.Entities[model.Book]
+	var authors *cache.Entities[model.Author]
+	if caches != nil {
+		books, authors = caches.Books, caches.Authors
 	}
 
 	return &Loaders{
-		BookLoader:    dataloadgen.NewLoader(bl.getBooks, dataloadgen.WithWait(time.Millisecond)),
-		AuthourLoader: dataloadgen.NewLoader(al.getAuthors, dataloadgen.WithWait(time.Millisecond)),
-		OrderLoader:   dataloadgen.NewLoader(ol.getOrders, dataloadgen.WithWait(time.Millisecond)),
+		BookLoader:    dataloadgen.NewLoader(instrument("books", cached(books, bl.getBooks)), dataloadgen.WithWait(time.Millisecond)),
+		AuthourLoader: dataloadgen.NewLoader(instrument("authors", cached(authors, al.getAuthors)), dataloadgen.WithWait(time.Millisecond)),
+		OrderLoader:   dataloadgen.NewLoader(instrument("orders", ol.getOrders), dataloadgen.WithWait(time.Millisecond)),
 	}
 }
 
@@ -107,6 +97,7 @@ func (l *bookLoader) getBooks(ctx context.Context, keys []string) ([]*model.Book
 			ID:            book.Id,
 			Title:         book.Title,
 			PublishedDate: book.PublishedDate,
+			Price:         MoneyFromProto(book.Price),
 		}
 	}
 
@@ -0,0 +1,40 @@
+package loaders
+
+import (
+	"context"
+
+	"github.com/iho/bookstore/internal/gateway/cache"
+)
+
+// cached serves the keys held by entities and only passes the rest on to
+// fetch, caching what it returns. A nil entities disables caching.
+func cached[V any](entities *cache.Entities[V], fetch func(ctx context.Context, keys []string) ([]*V, []error)) func(ctx context.Context, keys []string) ([]*V, []error) {
+	if entities == nil {
+		return fetch
+	}
+	return func(ctx context.Context, keys []string) ([]*V, []error) {
+		values := entities.GetMany(ctx, keys)
+		errs := make([]error, len(keys))
+
+		var missing []string
+		var positions []int
+		for i, value := range values {
+			if value == nil {
+				missing = append(missing, keys[i])
+				positions = append(positions, i)
+			}
+		}
+		if len(missing) == 0 {
+			return values, errs
+		}
+
+		fetched, fetchErrs := fetch(ctx, missing)
+		for j, i := range positions {
+			values[i], errs[i] = fetched[j], fetchErrs[j]
+			if errs[i] == nil && values[i] != nil {
+				entities.Set(ctx, keys[i], values[i])
+			}
+		}
+		return values, errs
+	}
+}
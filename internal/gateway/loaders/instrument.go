@@ -0,0 +1,37 @@
+package loaders
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var batchSize = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "graphql_dataloader_batch_size",
+	Help:    "Number of keys per dataloader batch.",
+	Buckets: []float64{1, 2, 5, 10, 20, 50, 100},
+}, []string{"loader"})
+
+// RegisterMetrics registers the dataloader metrics.
+func RegisterMetrics(reg prometheus.Registerer) {
+	reg.MustRegister(batchSize)
+}
+
+// instrument wraps a batch function in a span, so that the downstream calls
+// of a batch show up under it, and records the batch size.
+func instrument[V any](name string, fetch func(ctx context.Context, keys []string) ([]V, []error)) func(ctx context.Context, keys []string) ([]V, []error) {
+	observer := batchSize.WithLabelValues(name)
+	return func(ctx context.Context, keys []string) ([]V, []error) {
+		observer.Observe(float64(len(keys)))
+
+		ctx, span := otel.Tracer("github.com/iho/bookstore/internal/gateway/loaders").Start(ctx, "dataloader."+name,
+			trace.WithAttributes(attribute.Int("dataloader.batch_size", len(keys))),
+		)
+		defer span.End()
+
+		return fetch(ctx, keys)
+	}
+}
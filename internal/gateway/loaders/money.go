@@ -0,0 +1,14 @@
+package loaders
+
+import (
+	"github.com/iho/bookstore/internal/gateway/graph/model"
+	moneyV1 "github.com/iho/bookstore/protos/gen/money/v1"
+)
+
+// MoneyFromProto converts an amount of money.
+func MoneyFromProto(m *moneyV1.Money) *model.Money {
+	return &model.Money{
+		Amount:   m.GetAmount(),
+		Currency: m.GetCurrency(),
+	}
+}
@@ -2,6 +2,7 @@ package loaders
 
 import (
 	"context"
+	"strings"
 
 	"connectrpc.com/connect"
 	"github.com/iho/bookstore/internal/gateway/graph/model"
@@ -24,22 +25,7 @@ func (l *orderLoader) getOrders(ctx context.Context, keys []string) ([]*model.Or
 			continue
 		}
 
-		orderLines := make([]*model.OrderLine, len(res.Msg.Order.OrderLines))
-
-		for i, line := range res.Msg.Order.OrderLines {
-			orderLines[i] = &model.OrderLine{
-				BookID:   line.BookId,
-				Quantity: int(line.Quantity),
-			}
-		}
-
-		orders[i] = &model.Order{
-			ID:         res.Msg.Order.Id,
-			Quantity:   len(res.Msg.Order.OrderLines),
-			OrderLines: orderLines,
-			TotalPrice: int(res.Msg.Order.TotalPrice),
-			OrderDate:  res.Msg.Order.OrderDate,
-		}
+		orders[i] = OrderFromProto(res.Msg.Order)
 	}
 	return orders, errors
 }
@@ -55,3 +41,109 @@ func GetOrders(ctx context.Context, authorIDs []string) ([]*model.Order, error)
 	loaders := For(ctx)
 	return loaders.OrderLoader.LoadAll(ctx, authorIDs)
 }
+
+// OrderFromProto converts an order returned by the orders service.
+func OrderFromProto(order *ordersV1.Order) *model.Order {
+	payments := make([]*model.PaymentAttempt, len(order.Payments))
+	for i, p := range order.Payments {
+		payments[i] = &model.PaymentAttempt{
+			ID:        p.Id,
+			Operation: model.PaymentOperation(strings.TrimPrefix(p.Operation.String(), "PAYMENT_OPERATION_")),
+			Status:    model.PaymentStatus(strings.TrimPrefix(p.Status.String(), "PAYMENT_STATUS_")),
+			Amount:    MoneyFromProto(p.Amount),
+			Reference: optional(p.Reference),
+			Reason:    optional(p.Reason),
+			CreatedAt: p.CreatedAt,
+		}
+	}
+
+	shipments := make([]*model.Shipment, len(order.Shipments))
+	for i, s := range order.Shipments {
+		shipments[i] = shipmentFromProto(s)
+	}
+
+	return &model.Order{
+		ID:              order.Id,
+		Quantity:        len(order.OrderLines),
+		OrderLines:      OrderLinesFromProto(order.OrderLines),
+		SubtotalPrice:   MoneyFromProto(order.SubtotalPrice),
+		DiscountTotal:   MoneyFromProto(order.DiscountTotal),
+		TaxTotal:        MoneyFromProto(order.TaxTotal),
+		TotalPrice:      MoneyFromProto(order.TotalPrice),
+		CouponCode:      optional(order.CouponCode),
+		TaxRegion:       optional(order.TaxRegion),
+		OrderDate:       order.OrderDate,
+		Status:          model.OrderStatus(strings.TrimPrefix(order.Status.String(), "ORDER_STATUS_")),
+		Payments:        payments,
+		ShippingAddress: AddressFromProto(order.ShippingAddress),
+		BillingAddress:  AddressFromProto(order.BillingAddress),
+		Shipments:       shipments,
+		CustomerID:      optional(order.CustomerId),
+	}
+}
+
+// AddressFromProto converts an optional address.
+func AddressFromProto(a *ordersV1.Address) *model.Address {
+	if a == nil {
+		return nil
+	}
+	return &model.Address{
+		Name:       a.Name,
+		Line1:      a.Line1,
+		Line2:      optional(a.Line2),
+		City:       a.City,
+		Region:     optional(a.Region),
+		PostalCode: optional(a.PostalCode),
+		Country:    a.Country,
+		Phone:      optional(a.Phone),
+	}
+}
+
+func shipmentFromProto(s *ordersV1.Shipment) *model.Shipment {
+	lines := make([]*model.ShipmentLine, len(s.Lines))
+	for i, line := range s.Lines {
+		lines[i] = &model.ShipmentLine{
+			BookID:   line.BookId,
+			Quantity: int(line.Quantity),
+		}
+	}
+	return &model.Shipment{
+		ID:             s.Id,
+		Carrier:        s.Carrier,
+		TrackingNumber: optional(s.TrackingNumber),
+		Lines:          lines,
+		ShippedAt:      s.ShippedAt,
+		DeliveredAt:    optional(s.DeliveredAt),
+	}
+}
+
+// OrderLinesFromProto converts priced order lines.
+func OrderLinesFromProto(lines []*ordersV1.OrderLine) []*model.OrderLine {
+	orderLines := make([]*model.OrderLine, len(lines))
+	for i, line := range lines {
+		discounts := make([]*model.AppliedDiscount, len(line.Discounts))
+		for j, d := range line.Discounts {
+			discounts[j] = &model.AppliedDiscount{
+				PromotionID: d.PromotionId,
+				Name:        d.Name,
+				Code:        optional(d.Code),
+				Amount:      MoneyFromProto(d.Amount),
+			}
+		}
+		orderLines[i] = &model.OrderLine{
+			BookID:     line.BookId,
+			Quantity:   int(line.Quantity),
+			UnitPrice:  MoneyFromProto(line.UnitPrice),
+			Discounts:  discounts,
+			TotalPrice: MoneyFromProto(line.TotalPrice),
+		}
+	}
+	return orderLines
+}
+
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,57 @@
+package persisted
+
+import (
+	"context"
+	"errors"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// CodeOperationNotAllowed is set on errors for operations missing from the
+// manifest.
+const CodeOperationNotAllowed = "OPERATION_NOT_ALLOWED"
+
+// Allowlist only lets operations from the manifest through. Clients either
+// send the hash in the persistedQuery extension, as with APQ, or the full
+// query text, which must hash to a registered operation.
+type Allowlist struct {
+	Manifest *Manifest
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationParameterMutator
+} = Allowlist{}
+
+func (a Allowlist) ExtensionName() string {
+	return "Allowlist"
+}
+
+func (a Allowlist) Validate(graphql.ExecutableSchema) error {
+	if a.Manifest == nil {
+		return errors.New("allowlist needs a manifest")
+	}
+	return nil
+}
+
+func (a Allowlist) MutateOperationParameters(ctx context.Context, params *graphql.RawParams) *gqlerror.Error {
+	hash := ""
+	if ext, ok := params.Extensions["persistedQuery"].(map[string]any); ok {
+		hash, _ = ext["sha256Hash"].(string)
+	}
+	if params.Query != "" {
+		hash = Hash(params.Query)
+	}
+
+	query, ok := a.Manifest.Get(ctx, hash)
+	if hash == "" || !ok {
+		err := gqlerror.Errorf("operation is not in the allowlist")
+		errcode.Set(err, CodeOperationNotAllowed)
+		return err
+	}
+
+	params.Query = query.(string)
+	return nil
+}
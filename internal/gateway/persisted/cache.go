@@ -0,0 +1,69 @@
+package persisted
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	redis "github.com/redis/go-redis/v9"
+)
+
+const (
+	redisKeyPrefix = "apq:"
+	redisTTL       = 24 * time.Hour
+)
+
+// RedisCache is an APQ cache shared by every gateway instance.
+type RedisCache struct {
+	rdb redis.UniversalClient
+}
+
+var _ graphql.Cache = (*RedisCache)(nil)
+
+func NewRedisCache(rdb redis.UniversalClient) *RedisCache {
+	return &RedisCache{rdb: rdb}
+}
+
+func (c *RedisCache) Get(ctx context.Context, hash string) (any, bool) {
+	query, err := c.rdb.Get(ctx, redisKeyPrefix+hash).Result()
+	if err != nil {
+		if err != redis.Nil {
+			slog.WarnContext(ctx, "failed to read persisted query", "hash", hash, "error", err)
+		}
+		return nil, false
+	}
+	return query, true
+}
+
+func (c *RedisCache) Add(ctx context.Context, hash string, query any) {
+	if err := c.rdb.Set(ctx, redisKeyPrefix+hash, query, redisTTL).Err(); err != nil {
+		slog.WarnContext(ctx, "failed to store persisted query", "hash", hash, "error", err)
+	}
+}
+
+// layeredCache serves the manifest first and learns new queries in cache.
+type layeredCache struct {
+	manifest *Manifest
+	cache    graphql.Cache
+}
+
+// NewCache returns an APQ cache that looks queries up in manifest before
+// cache. manifest may be nil.
+func NewCache(manifest *Manifest, cache graphql.Cache) graphql.Cache {
+	if manifest == nil {
+		return cache
+	}
+	return layeredCache{manifest: manifest, cache: cache}
+}
+
+func (c layeredCache) Get(ctx context.Context, hash string) (any, bool) {
+	if query, ok := c.manifest.Get(ctx, hash); ok {
+		return query, true
+	}
+	return c.cache.Get(ctx, hash)
+}
+
+func (c layeredCache) Add(ctx context.Context, hash string, query any) {
+	c.cache.Add(ctx, hash, query)
+}
@@ -0,0 +1,81 @@
+// Package persisted implements persisted queries for the gateway: an
+// operation manifest, caches for Automatic Persisted Queries and a strict
+// allowlist mode.
+package persisted
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync/atomic"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+var ErrHashMismatch = errors.New("persisted: hash does not match query")
+
+// Manifest is the set of pre-registered operations, read from a JSON file
+// mapping the hex encoded SHA-256 of each query to the query. It is safe to
+// Reload while requests are served.
+type Manifest struct {
+	path    string
+	queries atomic.Pointer[map[string]string]
+}
+
+var _ graphql.Cache = (*Manifest)(nil)
+
+// LoadManifest reads the manifest at path.
+func LoadManifest(path string) (*Manifest, error) {
+	m := &Manifest{path: path}
+	if err := m.Reload(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Reload reads the manifest file again. On error the previous operations
+// stay in use.
+func (m *Manifest) Reload() error {
+	data, err := os.ReadFile(m.path)
+	if err != nil {
+		return fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	var queries map[string]string
+	if err := json.Unmarshal(data, &queries); err != nil {
+		return fmt.Errorf("failed to parse manifest: [path=%s] %w", m.path, err)
+	}
+	for hash, query := range queries {
+		if Hash(query) != hash {
+			return fmt.Errorf("%w: [hash=%s]", ErrHashMismatch, hash)
+		}
+	}
+
+	m.queries.Store(&queries)
+	return nil
+}
+
+// Len returns the number of operations in the manifest.
+func (m *Manifest) Len() int {
+	return len(*m.queries.Load())
+}
+
+// Get returns the query registered under hash.
+func (m *Manifest) Get(ctx context.Context, hash string) (any, bool) {
+	query, ok := (*m.queries.Load())[hash]
+	return query, ok
+}
+
+// Add does nothing: the manifest only changes through Reload.
+func (m *Manifest) Add(ctx context.Context, hash string, query any) {}
+
+// Hash returns the hex encoded SHA-256 of query, the key clients use for
+// persisted queries.
+func Hash(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,42 @@
+package idempotency
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/iho/bookstore/internal/ratelimit"
+)
+
+type ctxKey struct{}
+
+type request struct {
+	client string
+	key    string
+}
+
+// Middleware keeps the Idempotency-Key header of a request and its client
+// for Forward.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := request{
+			client: ratelimit.ClientKey(r.Context(), r.RemoteAddr),
+			key:    r.Header.Get(Header),
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), ctxKey{}, req)))
+	})
+}
+
+// Forward sets the idempotency key of a downstream call made for the
+// request in ctx. key, when set, replaces the request's header. The key is
+// scoped to the client, which the service only sees as the caller, and to
+// scope, so that one request can make several idempotent calls.
+func Forward(ctx context.Context, header http.Header, key *string, scope string) {
+	req, _ := ctx.Value(ctxKey{}).(request)
+	if key != nil && *key != "" {
+		req.key = *key
+	}
+	if req.key == "" {
+		return
+	}
+	header.Set(Header, scopeKey(req.client, scope, req.key))
+}
@@ -0,0 +1,162 @@
+// Package idempotency lets clients retry create calls safely. A call with an
+// Idempotency-Key header stores its response under the key, and later calls
+// with the same key get that response instead of creating another entity.
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/iho/bookstore/internal/ratelimit"
+	"github.com/iho/bookstore/internal/redisclient"
+	"github.com/iho/bookstore/internal/tenant"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// Header carries the idempotency key of a call.
+	Header = "Idempotency-Key"
+	// ReplayedHeader is set on responses replayed from the store.
+	ReplayedHeader = "Idempotent-Replayed"
+
+	defaultTTL = 24 * time.Hour
+	// pendingTTL bounds how long a reservation outlives a request that never
+	// completes it, e.g. because the process crashed. It is well above how
+	// long a create call takes.
+	pendingTTL = 2 * time.Minute
+)
+
+var (
+	ErrKeyReused    = errors.New("idempotency: key was used for a different request")
+	ErrInProgress   = errors.New("idempotency: a request with this key is in progress")
+	ErrUnknownStore = errors.New("idempotency: unknown store")
+)
+
+// Record is what a store keeps per key. Response is nil while the first
+// request is still running.
+type Record struct {
+	Fingerprint []byte `json:"fingerprint"`
+	Response    []byte `json:"response,omitempty"`
+}
+
+// Store keeps records for a limited time.
+type Store interface {
+	// Reserve claims key for a request with fingerprint for pendingTTL. If
+	// key was claimed before it returns the existing record and claims
+	// nothing.
+	Reserve(ctx context.Context, key string, fingerprint []byte) (*Record, error)
+	// Complete stores the response of the request that reserved key for the
+	// TTL of the store.
+	Complete(ctx context.Context, key string, record Record) error
+	// Release drops the claim of a failed request, so it can be retried.
+	Release(ctx context.Context, key string) error
+}
+
+// FromEnv returns the store picked by IDEMPOTENCY_STORE: "memory", the
+// default, or "redis" configured by IDEMPOTENCY_REDIS_ADDRS and the other
+// variables of redisclient.OptionsFromEnv. Records are kept for
+// IDEMPOTENCY_TTL, 24h by default.
+func FromEnv() (Store, error) {
+	ttl := defaultTTL
+	if value := os.Getenv("IDEMPOTENCY_TTL"); value != "" {
+		var err error
+		if ttl, err = time.ParseDuration(value); err != nil {
+			return nil, fmt.Errorf("invalid IDEMPOTENCY_TTL: %w", err)
+		}
+	}
+
+	switch kind := os.Getenv("IDEMPOTENCY_STORE"); kind {
+	case "", "memory":
+		return NewMemoryStore(ttl), nil
+	case "redis":
+		rdb, err := redisclient.FromEnv("IDEMPOTENCY_REDIS", "redis:6379")
+		if err != nil {
+			return nil, err
+		}
+		return NewRedisStore(rdb, ttl), nil
+	default:
+		return nil, fmt.Errorf("%w: [store=%s]", ErrUnknownStore, kind)
+	}
+}
+
+// Handle runs fn once per idempotency key of the caller and replays its
+// response for later calls with the same key and request. A different
+// request under a used key fails with CodeAlreadyExists. Calls without a
+// key, or with a nil store, always run fn. Keys are scoped to the caller, its
+// tenant and the procedure.
+func Handle[Req, Res any](ctx context.Context, store Store, req *connect.Request[Req], fn func(context.Context, *connect.Request[Req]) (*connect.Response[Res], error)) (*connect.Response[Res], error) {
+	key := req.Header().Get(Header)
+	if key == "" || store == nil {
+		return fn(ctx, req)
+	}
+
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(any(req.Msg).(proto.Message))
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	sum := sha256.Sum256(data)
+	fingerprint := sum[:]
+	tenantID, _ := tenant.FromContext(ctx)
+	key = scopeKey(ratelimit.ClientKey(ctx, req.Peer().Addr), tenantID+"\x00"+req.Spec().Procedure, key)
+
+	record, err := store.Reserve(ctx, key, fingerprint)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeUnavailable, err)
+	}
+	if record != nil {
+		return replay[Res](record, fingerprint)
+	}
+
+	res, err := fn(ctx, req)
+	// the outcome has to be recorded even if the caller gave up
+	ctx = context.WithoutCancel(ctx)
+	if err != nil {
+		if err := store.Release(ctx, key); err != nil {
+			slog.ErrorContext(ctx, "failed to release idempotency key", "error", err)
+		}
+		return nil, err
+	}
+
+	response, err := proto.Marshal(any(res.Msg).(proto.Message))
+	if err == nil {
+		err = store.Complete(ctx, key, Record{Fingerprint: fingerprint, Response: response})
+	}
+	if err != nil {
+		// the call succeeded, a retry will fail with ErrInProgress until the
+		// reservation expires rather than create a duplicate
+		slog.ErrorContext(ctx, "failed to store idempotent response", "error", err)
+	}
+	return res, nil
+}
+
+func replay[Res any](record *Record, fingerprint []byte) (*connect.Response[Res], error) {
+	if !bytes.Equal(record.Fingerprint, fingerprint) {
+		return nil, connect.NewError(connect.CodeAlreadyExists, ErrKeyReused)
+	}
+	if record.Response == nil {
+		return nil, connect.NewError(connect.CodeAborted, ErrInProgress)
+	}
+
+	msg := new(Res)
+	if err := proto.Unmarshal(record.Response, any(msg).(proto.Message)); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to decode stored response: %w", err))
+	}
+	res := connect.NewResponse(msg)
+	res.Header().Set(ReplayedHeader, "true")
+	return res, nil
+}
+
+// scopeKey makes keys unique per client and scope, so that clients cannot
+// see each other's responses.
+func scopeKey(client, scope, key string) string {
+	sum := sha256.Sum256([]byte(client + "\x00" + scope + "\x00" + key))
+	return hex.EncodeToString(sum[:])
+}
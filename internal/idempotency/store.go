@@ -0,0 +1,128 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	redis "github.com/redis/go-redis/v9"
+)
+
+type memoryRecord struct {
+	Record
+	expires time.Time
+}
+
+// MemoryStore keeps records in process, so retries have to reach the same
+// replica.
+type MemoryStore struct {
+	ttl time.Duration
+
+	mu        sync.Mutex
+	records   map[string]*memoryRecord
+	lastSweep time.Time
+}
+
+func NewMemoryStore(ttl time.Duration) *MemoryStore {
+	return &MemoryStore{ttl: ttl, records: map[string]*memoryRecord{}, lastSweep: time.Now()}
+}
+
+func (s *MemoryStore) Reserve(ctx context.Context, key string, fingerprint []byte) (*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(s.lastSweep) > time.Minute {
+		s.lastSweep = now
+		for k, record := range s.records {
+			if now.After(record.expires) {
+				delete(s.records, k)
+			}
+		}
+	}
+
+	if record, ok := s.records[key]; ok && now.Before(record.expires) {
+		existing := record.Record
+		return &existing, nil
+	}
+	s.records[key] = &memoryRecord{Record: Record{Fingerprint: fingerprint}, expires: now.Add(pendingTTL)}
+	return nil, nil
+}
+
+func (s *MemoryStore) Complete(ctx context.Context, key string, record Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[key] = &memoryRecord{Record: record, expires: time.Now().Add(s.ttl)}
+	return nil
+}
+
+func (s *MemoryStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, key)
+	return nil
+}
+
+const redisPrefix = "idempotency:"
+
+// RedisStore shares records between replicas.
+type RedisStore struct {
+	rdb redis.UniversalClient
+	ttl time.Duration
+}
+
+func NewRedisStore(rdb redis.UniversalClient, ttl time.Duration) *RedisStore {
+	return &RedisStore{rdb: rdb, ttl: ttl}
+}
+
+func (s *RedisStore) Reserve(ctx context.Context, key string, fingerprint []byte) (*Record, error) {
+	pending, err := json.Marshal(Record{Fingerprint: fingerprint})
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		ok, err := s.rdb.SetNX(ctx, redisPrefix+key, pending, pendingTTL).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to reserve idempotency key: %w", err)
+		}
+		if ok {
+			return nil, nil
+		}
+
+		data, err := s.rdb.Get(ctx, redisPrefix+key).Bytes()
+		if errors.Is(err, redis.Nil) {
+			// released or expired in between, try again
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get idempotency record: %w", err)
+		}
+		var record Record
+		if err := json.Unmarshal(data, &record); err != nil {
+			return nil, fmt.Errorf("failed to decode idempotency record: %w", err)
+		}
+		return &record, nil
+	}
+}
+
+func (s *RedisStore) Complete(ctx context.Context, key string, record Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if err := s.rdb.Set(ctx, redisPrefix+key, data, s.ttl).Err(); err != nil {
+		return fmt.Errorf("failed to store idempotency record: %w", err)
+	}
+	return nil
+}
+
+func (s *RedisStore) Release(ctx context.Context, key string) error {
+	if err := s.rdb.Del(ctx, redisPrefix+key).Err(); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// Interceptor propagates request IDs over Connect and writes access logs.
+// Handlers take the request ID from the RequestIDHeader, or make one up,
+// and echo it in the response; clients forward the request ID of their
+// context. Only handlers write access logs. With logBodies, unary requests
+// are also logged at debug level, redacted by redactMessage.
+type Interceptor struct {
+	logger    *slog.Logger
+	accessLog bool
+	logBodies bool
+}
+
+func NewInterceptor(logger *slog.Logger, accessLog, logBodies bool) *Interceptor {
+	return &Interceptor{
+		logger:    logger,
+		accessLog: accessLog,
+		logBodies: logBodies,
+	}
+}
+
+func (i *Interceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if req.Spec().IsClient {
+			forwardRequestID(ctx, req.Header())
+			return next(ctx, req)
+		}
+
+		id := requestIDFrom(req.Header())
+		ctx = WithRequestID(ctx, id)
+
+		start := time.Now()
+		res, err := next(ctx, req)
+		if res != nil {
+			res.Header().Set(RequestIDHeader, id)
+		}
+		setErrorRequestID(err, id)
+
+		if i.accessLog {
+			i.log(ctx, req.Spec(), start, err,
+				slog.String("peer", req.Peer().Addr),
+				slog.String("user_agent", req.Header().Get("User-Agent")),
+			)
+		}
+		if msg, ok := req.Any().(proto.Message); ok && i.logBodies && i.logger.Enabled(ctx, slog.LevelDebug) {
+			i.logger.LogAttrs(ctx, slog.LevelDebug, "rpc request",
+				slog.String("procedure", strings.TrimPrefix(req.Spec().Procedure, "/")),
+				slog.Any("request", redactMessage(msg.ProtoReflect())),
+			)
+		}
+		return res, err
+	}
+}
+
+func (i *Interceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return func(ctx context.Context, spec connect.Spec) connect.StreamingClientConn {
+		conn := next(ctx, spec)
+		forwardRequestID(ctx, conn.RequestHeader())
+		return conn
+	}
+}
+
+func (i *Interceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		id := requestIDFrom(conn.RequestHeader())
+		ctx = WithRequestID(ctx, id)
+		conn.ResponseHeader().Set(RequestIDHeader, id)
+
+		start := time.Now()
+		err := next(ctx, conn)
+		setErrorRequestID(err, id)
+
+		if i.accessLog {
+			i.log(ctx, conn.Spec(), start, err,
+				slog.String("peer", conn.Peer().Addr),
+				slog.String("user_agent", conn.RequestHeader().Get("User-Agent")),
+			)
+		}
+		return err
+	}
+}
+
+func (i *Interceptor) log(ctx context.Context, spec connect.Spec, start time.Time, err error, attrs ...slog.Attr) {
+	code := "ok"
+	level := slog.LevelInfo
+	if err != nil {
+		code = connect.CodeOf(err).String()
+		level = levelFor(connect.CodeOf(err))
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+
+	attrs = append(attrs,
+		slog.String("procedure", strings.TrimPrefix(spec.Procedure, "/")),
+		slog.String("code", code),
+		slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+	)
+	i.logger.LogAttrs(ctx, level, "rpc", attrs...)
+}
+
+// levelFor logs failures caused by the server as errors and the ones caused
+// by the caller as warnings.
+func levelFor(code connect.Code) slog.Level {
+	switch code {
+	case connect.CodeInternal, connect.CodeUnknown, connect.CodeDataLoss, connect.CodeUnavailable, connect.CodeUnimplemented:
+		return slog.LevelError
+	}
+	return slog.LevelWarn
+}
+
+func requestIDFrom(header http.Header) string {
+	if id := header.Get(RequestIDHeader); id != "" {
+		return id
+	}
+	return uuid.NewString()
+}
+
+func forwardRequestID(ctx context.Context, header http.Header) {
+	if id := RequestID(ctx); id != "" {
+		header.Set(RequestIDHeader, id)
+	}
+}
+
+func setErrorRequestID(err error, id string) {
+	var connectErr *connect.Error
+	if errors.As(err, &connectErr) {
+		connectErr.Meta().Set(RequestIDHeader, id)
+	}
+}
+
+// redactMessage returns msg as a JSON-like value for the logs. Which
+// fields are kept depends on their type rather than their name: numbers,
+// bools and enums are kept, strings and bytes, which may hold anything a
+// user typed, are replaced, as are fields marked debug_redact.
+func redactMessage(msg protoreflect.Message) map[string]any {
+	value := make(map[string]any)
+	msg.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if opts, ok := fd.Options().(*descriptorpb.FieldOptions); ok && opts.GetDebugRedact() {
+			value[fd.JSONName()] = redacted
+			return true
+		}
+
+		switch {
+		case fd.IsList():
+			list := v.List()
+			items := make([]any, list.Len())
+			for i := range items {
+				items[i] = redactValue(fd, list.Get(i))
+			}
+			value[fd.JSONName()] = items
+		case fd.IsMap():
+			entries := make(map[string]any)
+			v.Map().Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+				entries[k.String()] = redactValue(fd.MapValue(), v)
+				return true
+			})
+			value[fd.JSONName()] = entries
+		default:
+			value[fd.JSONName()] = redactValue(fd, v)
+		}
+		return true
+	})
+	return value
+}
+
+// redactValue returns a single value of a field of type fd.
+func redactValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) any {
+	switch fd.Kind() {
+	case protoreflect.StringKind, protoreflect.BytesKind:
+		return redacted
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return redactMessage(v.Message())
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return string(ev.Name())
+		}
+		return int32(v.Enum())
+	}
+	return v.Interface()
+}
@@ -0,0 +1,48 @@
+package logging
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/felixge/httpsnoop"
+	"github.com/google/uuid"
+)
+
+// Middleware assigns every HTTP request a request ID, taken from the
+// RequestIDHeader when the client sent one, echoes it in the response and
+// writes an access log line when accessLog is set.
+func Middleware(logger *slog.Logger, accessLog bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(RequestIDHeader)
+			if id == "" {
+				id = uuid.NewString()
+			}
+			w.Header().Set(RequestIDHeader, id)
+			r = r.WithContext(WithRequestID(r.Context(), id))
+
+			if !accessLog {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			start := time.Now()
+			m := httpsnoop.CaptureMetrics(next, w, r)
+
+			level := slog.LevelInfo
+			if m.Code >= http.StatusInternalServerError {
+				level = slog.LevelError
+			}
+			logger.LogAttrs(r.Context(), level, "http",
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.Int("status", m.Code),
+				slog.Int64("bytes", m.Written),
+				slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+				slog.String("remote_addr", r.RemoteAddr),
+				slog.String("user_agent", r.UserAgent()),
+			)
+		})
+	}
+}
@@ -0,0 +1,111 @@
+// Package logging sets up structured JSON logging and carries request IDs
+// between the gateway and the services.
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestIDHeader carries the request ID on HTTP requests and Connect calls.
+const RequestIDHeader = "X-Request-Id"
+
+const redacted = "[REDACTED]"
+
+// sensitiveKeys are attribute names whose values never make it into the
+// logs.
+var sensitiveKeys = map[string]bool{
+	"authorization": true,
+	"cookie":        true,
+	"set-cookie":    true,
+	"password":      true,
+	"secret":        true,
+	"token":         true,
+	"api_key":       true,
+	"apikey":        true,
+	"card_number":   true,
+	"cardnumber":    true,
+	"cvv":           true,
+}
+
+type ctxKey struct{}
+
+// WithRequestID returns a copy of ctx carrying id.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// RequestID returns the request ID carried by ctx, if any.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(ctxKey{}).(string)
+	return id
+}
+
+// New returns a logger writing JSON lines to stdout, tagged with service.
+// Records logged with a context get its request and trace IDs. LOG_LEVEL
+// sets the minimum level: debug, info (the default), warn or error.
+func New(service string) *slog.Logger {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(os.Getenv("LOG_LEVEL"))); err != nil {
+		level = slog.LevelInfo
+	}
+
+	handler := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: redactAttr,
+	})
+	return slog.New(contextHandler{handler}).With("service", service)
+}
+
+// AccessLogEnabled reports whether access logs are on. They are unless
+// ACCESS_LOG is "false".
+func AccessLogEnabled() bool {
+	return os.Getenv("ACCESS_LOG") != "false"
+}
+
+// LogBodiesEnabled reports whether request bodies are logged at debug level.
+// They are when ACCESS_LOG_BODIES is "true".
+func LogBodiesEnabled() bool {
+	return os.Getenv("ACCESS_LOG_BODIES") == "true"
+}
+
+func isSensitive(key string) bool {
+	return sensitiveKeys[strings.ToLower(key)]
+}
+
+func redactAttr(groups []string, a slog.Attr) slog.Attr {
+	if isSensitive(a.Key) {
+		return slog.String(a.Key, redacted)
+	}
+	return a
+}
+
+// contextHandler adds the request and trace IDs of the context to records.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		r.AddAttrs(
+			slog.String("trace_id", span.TraceID().String()),
+			slog.String("span_id", span.SpanID().String()),
+		)
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,117 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Interceptor records RED metrics for Connect calls: totals by procedure
+// and Connect code, and latency histograms. Handlers and clients are
+// recorded separately. Register it on a prometheus.Registerer before use.
+type Interceptor struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+var _ prometheus.Collector = (*Interceptor)(nil)
+
+func NewInterceptor() *Interceptor {
+	return &Interceptor{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "connect_requests_total",
+			Help: "Connect calls by side, procedure and Connect code.",
+		}, []string{"side", "procedure", "code"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "connect_request_duration_seconds",
+			Help:    "Latency of Connect calls by side, procedure and Connect code.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"side", "procedure", "code"}),
+	}
+}
+
+func (i *Interceptor) Describe(ch chan<- *prometheus.Desc) {
+	i.requests.Describe(ch)
+	i.duration.Describe(ch)
+}
+
+func (i *Interceptor) Collect(ch chan<- prometheus.Metric) {
+	i.requests.Collect(ch)
+	i.duration.Collect(ch)
+}
+
+func (i *Interceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		start := time.Now()
+		res, err := next(ctx, req)
+		i.record(ctx, req.Spec(), start, err)
+		return res, err
+	}
+}
+
+func (i *Interceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return func(ctx context.Context, spec connect.Spec) connect.StreamingClientConn {
+		return &recordedClientConn{
+			StreamingClientConn: next(ctx, spec),
+			i:                   i,
+			ctx:                 ctx,
+			start:               time.Now(),
+		}
+	}
+}
+
+func (i *Interceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		start := time.Now()
+		err := next(ctx, conn)
+		i.record(ctx, conn.Spec(), start, err)
+		return err
+	}
+}
+
+func (i *Interceptor) record(ctx context.Context, spec connect.Spec, start time.Time, err error) {
+	side := "server"
+	if spec.IsClient {
+		side = "client"
+	}
+	code := "ok"
+	if err != nil {
+		code = connect.CodeOf(err).String()
+	}
+	procedure := strings.TrimPrefix(spec.Procedure, "/")
+
+	i.requests.WithLabelValues(side, procedure, code).Inc()
+	Observe(ctx, i.duration.WithLabelValues(side, procedure, code), time.Since(start).Seconds())
+}
+
+// recordedClientConn records a streaming call once its response is closed.
+type recordedClientConn struct {
+	connect.StreamingClientConn
+	i     *Interceptor
+	ctx   context.Context
+	start time.Time
+	once  sync.Once
+	err   error
+}
+
+func (c *recordedClientConn) Receive(msg any) error {
+	err := c.StreamingClientConn.Receive(msg)
+	if err != nil && !errors.Is(err, io.EOF) && c.err == nil {
+		c.err = err
+	}
+	return err
+}
+
+func (c *recordedClientConn) CloseResponse() error {
+	err := c.StreamingClientConn.CloseResponse()
+	c.once.Do(func() {
+		c.i.record(c.ctx, c.Spec(), c.start, c.err)
+	})
+	return err
+}
@@ -0,0 +1,112 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// GraphQL is a gqlgen extension recording operation and resolver metrics.
+// Only fields backed by a resolver are recorded. Register it on a
+// prometheus.Registerer before use.
+type GraphQL struct {
+	operations        *prometheus.CounterVec
+	operationDuration *prometheus.HistogramVec
+	resolverDuration  *prometheus.HistogramVec
+	resolverErrors    *prometheus.CounterVec
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.ResponseInterceptor
+	graphql.FieldInterceptor
+	prometheus.Collector
+} = (*GraphQL)(nil)
+
+func NewGraphQL() *GraphQL {
+	return &GraphQL{
+		operations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "graphql_operations_total",
+			Help: "GraphQL responses by operation name, type and status.",
+		}, []string{"operation", "type", "status"}),
+		operationDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "graphql_operation_duration_seconds",
+			Help:    "Time to produce a GraphQL response by operation name and type.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"operation", "type"}),
+		resolverDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "graphql_resolver_duration_seconds",
+			Help:    "Resolver latency by object and field.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"object", "field"}),
+		resolverErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "graphql_resolver_errors_total",
+			Help: "Resolver errors by object and field.",
+		}, []string{"object", "field"}),
+	}
+}
+
+func (m *GraphQL) ExtensionName() string {
+	return "Metrics"
+}
+
+func (m *GraphQL) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (m *GraphQL) Describe(ch chan<- *prometheus.Desc) {
+	m.operations.Describe(ch)
+	m.operationDuration.Describe(ch)
+	m.resolverDuration.Describe(ch)
+	m.resolverErrors.Describe(ch)
+}
+
+func (m *GraphQL) Collect(ch chan<- prometheus.Metric) {
+	m.operations.Collect(ch)
+	m.operationDuration.Collect(ch)
+	m.resolverDuration.Collect(ch)
+	m.resolverErrors.Collect(ch)
+}
+
+func (m *GraphQL) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	start := time.Now()
+	res := next(ctx)
+	if res == nil || !graphql.HasOperationContext(ctx) {
+		return res
+	}
+
+	oc := graphql.GetOperationContext(ctx)
+	name := oc.OperationName
+	if name == "" {
+		name = "anonymous"
+	}
+	opType := "unknown"
+	if oc.Operation != nil {
+		opType = string(oc.Operation.Operation)
+	}
+	status := "ok"
+	if len(res.Errors) > 0 {
+		status = "error"
+	}
+
+	m.operations.WithLabelValues(name, opType, status).Inc()
+	Observe(ctx, m.operationDuration.WithLabelValues(name, opType), time.Since(start).Seconds())
+	return res
+}
+
+func (m *GraphQL) InterceptField(ctx context.Context, next graphql.Resolver) (any, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || !fc.IsResolver {
+		return next(ctx)
+	}
+
+	start := time.Now()
+	res, err := next(ctx)
+	Observe(ctx, m.resolverDuration.WithLabelValues(fc.Object, fc.Field.Name), time.Since(start).Seconds())
+	if err != nil {
+		m.resolverErrors.WithLabelValues(fc.Object, fc.Field.Name).Inc()
+	}
+	return res, err
+}
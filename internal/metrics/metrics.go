@@ -0,0 +1,42 @@
+// Package metrics holds the Prometheus instrumentation shared by the
+// services and the gateway.
+package metrics
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Observe records v on o, attaching the trace ID of ctx as an exemplar when
+// there is one.
+func Observe(ctx context.Context, o prometheus.Observer, v float64) {
+	if eo, ok := o.(prometheus.ExemplarObserver); ok {
+		if labels := exemplar(ctx); labels != nil {
+			eo.ObserveWithExemplar(v, labels)
+			return
+		}
+	}
+	o.Observe(v)
+}
+
+// Add adds v to c, attaching the trace ID of ctx as an exemplar when there
+// is one.
+func Add(ctx context.Context, c prometheus.Counter, v float64) {
+	if ea, ok := c.(prometheus.ExemplarAdder); ok {
+		if labels := exemplar(ctx); labels != nil {
+			ea.AddWithExemplar(v, labels)
+			return
+		}
+	}
+	c.Add(v)
+}
+
+func exemplar(ctx context.Context) prometheus.Labels {
+	span := trace.SpanContextFromContext(ctx)
+	if !span.IsSampled() {
+		return nil
+	}
+	return prometheus.Labels{"trace_id": span.TraceID().String()}
+}
@@ -0,0 +1,11 @@
+package money
+
+import "errors"
+
+var (
+	ErrUnknownCurrency  = errors.New("money: unknown currency")
+	ErrCurrencyMismatch = errors.New("money: currencies do not match")
+	ErrOverflow         = errors.New("money: amount out of bounds")
+	ErrNoRate           = errors.New("money: no exchange rate")
+	ErrInvalidRate      = errors.New("money: invalid exchange rate")
+)
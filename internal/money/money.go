@@ -0,0 +1,138 @@
+// Package money represents prices as amounts of minor units of an ISO 4217
+// currency and converts them between currencies with a rate table loaded
+// from a local file.
+package money
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+
+	v1 "github.com/iho/bookstore/protos/gen/money/v1"
+)
+
+// DefaultCurrency is the currency of the store when CURRENCY is not set.
+const DefaultCurrency = "USD"
+
+// currencies are the ISO 4217 codes accepted, with the number of digits of
+// their minor unit.
+var currencies = map[string]int{
+	"AUD": 2, "BHD": 3, "BRL": 2, "CAD": 2, "CHF": 2, "CNY": 2, "CZK": 2,
+	"DKK": 2, "EUR": 2, "GBP": 2, "HKD": 2, "HUF": 2, "ILS": 2, "INR": 2,
+	"JOD": 3, "JPY": 0, "KRW": 0, "KWD": 3, "MXN": 2, "NOK": 2, "NZD": 2,
+	"PLN": 2, "SEK": 2, "SGD": 2, "TRY": 2, "UAH": 2, "USD": 2, "ZAR": 2,
+}
+
+// Money is an amount of minor units of a currency, e.g. cents for USD.
+type Money struct {
+	Amount   int64  `bson:"amount" json:"amount"`
+	Currency string `bson:"currency" json:"currency"`
+}
+
+func New(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// NormalizeCurrency returns code the way currencies are stored.
+func NormalizeCurrency(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// ParseCurrency normalizes code and checks that it is a known currency.
+func ParseCurrency(code string) (string, error) {
+	code = NormalizeCurrency(code)
+	if _, ok := currencies[code]; !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnknownCurrency, code)
+	}
+	return code, nil
+}
+
+// CurrencyFromEnv returns the currency of the store, CURRENCY or USD.
+func CurrencyFromEnv() (string, error) {
+	code := os.Getenv("CURRENCY")
+	if code == "" {
+		return DefaultCurrency, nil
+	}
+	return ParseCurrency(code)
+}
+
+// Add returns m + o. Both must be in the same currency.
+func (m Money) Add(o Money) (Money, error) {
+	if m.Currency != o.Currency {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, o.Currency)
+	}
+	if o.Amount > 0 && m.Amount > math.MaxInt64-o.Amount || o.Amount < 0 && m.Amount < math.MinInt64-o.Amount {
+		return Money{}, ErrOverflow
+	}
+	return New(m.Amount+o.Amount, m.Currency), nil
+}
+
+// Mul returns m times n.
+func (m Money) Mul(n int64) (Money, error) {
+	product := m.Amount * n
+	if m.Amount != 0 && (product/m.Amount != n || m.Amount == -1 && n == math.MinInt64) {
+		return Money{}, ErrOverflow
+	}
+	return New(product, m.Currency), nil
+}
+
+// Scale returns m times r, rounded half away from zero to a minor unit.
+func (m Money) Scale(r *big.Rat) (Money, error) {
+	amount, err := round(new(big.Rat).Mul(new(big.Rat).SetInt64(m.Amount), r))
+	if err != nil {
+		return Money{}, err
+	}
+	return New(amount, m.Currency), nil
+}
+
+// String formats m in major units, e.g. "12.50 USD".
+func (m Money) String() string {
+	return m.Decimal() + " " + m.Currency
+}
+
+// Decimal formats the amount in major units without the currency, e.g.
+// "12.50".
+func (m Money) Decimal() string {
+	digits := currencies[m.Currency]
+	if digits == 0 {
+		return strconv.FormatInt(m.Amount, 10)
+	}
+	return new(big.Rat).SetFrac(big.NewInt(m.Amount), pow10(digits)).FloatString(digits)
+}
+
+// FromProto converts msg, which is in currency def when it leaves the
+// currency empty. A nil msg is zero.
+func FromProto(msg *v1.Money, def string) (Money, error) {
+	if msg == nil || msg.Currency == "" {
+		return New(msg.GetAmount(), def), nil
+	}
+	currency, err := ParseCurrency(msg.Currency)
+	if err != nil {
+		return Money{}, err
+	}
+	return New(msg.Amount, currency), nil
+}
+
+func (m Money) Proto() *v1.Money {
+	return &v1.Money{Amount: m.Amount, Currency: m.Currency}
+}
+
+// round rounds r half away from zero.
+func round(r *big.Rat) (int64, error) {
+	num, denom := r.Num(), r.Denom()
+	q, rem := new(big.Int).QuoRem(num, denom, new(big.Int))
+	if rem.Sign() != 0 && new(big.Int).Mul(new(big.Int).Abs(rem), big.NewInt(2)).Cmp(denom) >= 0 {
+		q.Add(q, big.NewInt(int64(num.Sign())))
+	}
+	if !q.IsInt64() {
+		return 0, ErrOverflow
+	}
+	return q.Int64(), nil
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
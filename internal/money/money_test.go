@@ -0,0 +1,66 @@
+package money
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestAdd(t *testing.T) {
+	tests := []struct {
+		name string
+		m, o Money
+		want Money
+		err  error
+	}{
+		{"sum", New(150, "USD"), New(250, "USD"), New(400, "USD"), nil},
+		{"negative", New(150, "USD"), New(-250, "USD"), New(-100, "USD"), nil},
+		{"max", New(math.MaxInt64-1, "USD"), New(1, "USD"), New(math.MaxInt64, "USD"), nil},
+		{"min", New(math.MinInt64+1, "USD"), New(-1, "USD"), New(math.MinInt64, "USD"), nil},
+		{"overflow", New(math.MaxInt64, "USD"), New(1, "USD"), Money{}, ErrOverflow},
+		{"underflow", New(math.MinInt64, "USD"), New(-1, "USD"), Money{}, ErrOverflow},
+		{"currency mismatch", New(150, "USD"), New(250, "EUR"), Money{}, ErrCurrencyMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.m.Add(tt.o)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Add() error = %v, want %v", err, tt.err)
+			}
+			if got != tt.want {
+				t.Errorf("Add() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMul(t *testing.T) {
+	tests := []struct {
+		name string
+		m    Money
+		n    int64
+		want Money
+		err  error
+	}{
+		{"product", New(250, "USD"), 3, New(750, "USD"), nil},
+		{"zero amount", New(0, "USD"), math.MaxInt64, New(0, "USD"), nil},
+		{"zero factor", New(math.MaxInt64, "USD"), 0, New(0, "USD"), nil},
+		{"negative", New(-250, "USD"), 3, New(-750, "USD"), nil},
+		{"max", New(math.MaxInt64/2, "USD"), 2, New(math.MaxInt64-1, "USD"), nil},
+		{"overflow", New(math.MaxInt64/2+1, "USD"), 2, Money{}, ErrOverflow},
+		{"negative overflow", New(math.MinInt64/2-1, "USD"), 2, Money{}, ErrOverflow},
+		{"min times minus one", New(math.MinInt64, "USD"), -1, Money{}, ErrOverflow},
+		{"minus one times min", New(-1, "USD"), math.MinInt64, Money{}, ErrOverflow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.m.Mul(tt.n)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Mul() error = %v, want %v", err, tt.err)
+			}
+			if got != tt.want {
+				t.Errorf("Mul() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
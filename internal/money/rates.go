@@ -0,0 +1,85 @@
+package money
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+// Rates converts amounts between currencies at fixed rates. Rates are given
+// against a base currency as the units of a currency one unit of the base
+// buys, e.g. EUR 0.92 for base USD.
+type Rates struct {
+	base  string
+	rates map[string]*big.Rat
+}
+
+// NewRates parses rates, which are decimal strings so that they are exact.
+func NewRates(base string, rates map[string]string) (*Rates, error) {
+	base, err := ParseCurrency(base)
+	if err != nil {
+		return nil, err
+	}
+	r := &Rates{base: base, rates: map[string]*big.Rat{base: big.NewRat(1, 1)}}
+	for code, value := range rates {
+		currency, err := ParseCurrency(code)
+		if err != nil {
+			return nil, err
+		}
+		rate, ok := new(big.Rat).SetString(value)
+		if !ok || rate.Sign() <= 0 {
+			return nil, fmt.Errorf("%w: [currency=%s] %q", ErrInvalidRate, currency, value)
+		}
+		r.rates[currency] = rate
+	}
+	return r, nil
+}
+
+// LoadRates reads a rate table from a JSON file such as
+//
+//	{"base": "USD", "rates": {"EUR": "0.92", "JPY": "151.3"}}
+func LoadRates(path string) (*Rates, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read exchange rates: %w", err)
+	}
+	var file struct {
+		Base  string            `json:"base"`
+		Rates map[string]string `json:"rates"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse exchange rates: [path=%s] %w", path, err)
+	}
+	return NewRates(file.Base, file.Rates)
+}
+
+// RatesFromEnv loads the table in CURRENCY_RATES_FILE. Without one only
+// currency is known, so amounts cannot be converted.
+func RatesFromEnv(currency string) (*Rates, error) {
+	if path := os.Getenv("CURRENCY_RATES_FILE"); path != "" {
+		return LoadRates(path)
+	}
+	return NewRates(currency, nil)
+}
+
+// Convert returns m in currency, rounded half away from zero to a minor
+// unit.
+func (r *Rates) Convert(m Money, currency string) (Money, error) {
+	if m.Currency == currency {
+		return m, nil
+	}
+	from, ok := r.rates[m.Currency]
+	if !ok {
+		return Money{}, fmt.Errorf("%w: [from=%s] [to=%s]", ErrNoRate, m.Currency, currency)
+	}
+	to, ok := r.rates[currency]
+	if !ok {
+		return Money{}, fmt.Errorf("%w: [from=%s] [to=%s]", ErrNoRate, m.Currency, currency)
+	}
+
+	// amount / 10^from digits / from rate * to rate * 10^to digits
+	factor := new(big.Rat).Quo(to, from)
+	factor.Mul(factor, new(big.Rat).SetFrac(pow10(currencies[currency]), pow10(currencies[m.Currency])))
+	return New(m.Amount, currency).Scale(factor)
+}
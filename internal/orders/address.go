@@ -0,0 +1,128 @@
+package orders
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"connectrpc.com/connect"
+	v1 "github.com/iho/bookstore/protos/gen/orders/v1"
+)
+
+// maxAddressField is the longest value an address field may have.
+const maxAddressField = 200
+
+var (
+	countryCode = regexp.MustCompile(`^[A-Z]{2}$`)
+	regionCode  = regexp.MustCompile(`^[A-Z0-9]{1,3}$`)
+	phoneNumber = regexp.MustCompile(`^\+?[0-9][0-9 ()-]{3,19}$`)
+	postalCode  = regexp.MustCompile(`^[A-Z0-9][A-Z0-9 -]{0,10}[A-Z0-9]$`)
+	// postalCodes are the formats of the countries the store ships to most.
+	// These countries need a postal code, the others only get a sanity
+	// check.
+	postalCodes = map[string]*regexp.Regexp{
+		"CA": regexp.MustCompile(`^[A-Z][0-9][A-Z] ?[0-9][A-Z][0-9]$`),
+		"DE": regexp.MustCompile(`^[0-9]{5}$`),
+		"FR": regexp.MustCompile(`^[0-9]{5}$`),
+		"GB": regexp.MustCompile(`^[A-Z]{1,2}[0-9][A-Z0-9]? ?[0-9][A-Z]{2}$`),
+		"US": regexp.MustCompile(`^[0-9]{5}(-[0-9]{4})?$`),
+	}
+)
+
+// newAddress validates the address in field of a request. Codes are upper
+// cased and values trimmed. A missing address is nil.
+func newAddress(field string, msg *v1.Address) (*Address, error) {
+	if msg == nil {
+		return nil, nil
+	}
+
+	a := &Address{
+		Name:       strings.TrimSpace(msg.Name),
+		Line1:      strings.TrimSpace(msg.Line1),
+		Line2:      strings.TrimSpace(msg.Line2),
+		City:       strings.TrimSpace(msg.City),
+		Region:     strings.ToUpper(strings.TrimSpace(msg.Region)),
+		PostalCode: strings.ToUpper(strings.TrimSpace(msg.PostalCode)),
+		Country:    strings.ToUpper(strings.TrimSpace(msg.Country)),
+		Phone:      strings.TrimSpace(msg.Phone),
+	}
+	if err := a.validate(); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("%w: [field=%s] %w", ErrInvalidAddress, field, err))
+	}
+	return a, nil
+}
+
+func (a *Address) validate() error {
+	fields := []struct {
+		name, value string
+		required    bool
+	}{
+		{"name", a.Name, true},
+		{"line1", a.Line1, true},
+		{"line2", a.Line2, false},
+		{"city", a.City, true},
+		{"region", a.Region, false},
+		{"postal_code", a.PostalCode, false},
+		{"country", a.Country, true},
+		{"phone", a.Phone, false},
+	}
+	for _, f := range fields {
+		if f.required && f.value == "" {
+			return fmt.Errorf("%s must be provided", f.name)
+		}
+		if len(f.value) > maxAddressField {
+			return fmt.Errorf("%s must be at most %d bytes", f.name, maxAddressField)
+		}
+	}
+
+	if !countryCode.MatchString(a.Country) {
+		return fmt.Errorf("country must be an ISO 3166-1 alpha-2 code, got %q", a.Country)
+	}
+	if a.Region != "" && !regionCode.MatchString(a.Region) {
+		return fmt.Errorf("region must be an ISO 3166-2 subdivision code, got %q", a.Region)
+	}
+	if a.Phone != "" && !phoneNumber.MatchString(a.Phone) {
+		return fmt.Errorf("invalid phone number %q", a.Phone)
+	}
+
+	format, known := postalCodes[a.Country]
+	switch {
+	case a.PostalCode == "" && known:
+		return fmt.Errorf("postal_code must be provided for %s", a.Country)
+	case a.PostalCode == "":
+	case known && !format.MatchString(a.PostalCode):
+		return fmt.Errorf("invalid postal code %q for %s", a.PostalCode, a.Country)
+	case !postalCode.MatchString(a.PostalCode):
+		return fmt.Errorf("invalid postal code %q", a.PostalCode)
+	}
+	return nil
+}
+
+// taxRegion returns the tax region of the address, e.g. "US-CA", or an
+// empty region for a nil address.
+func (a *Address) taxRegion() string {
+	switch {
+	case a == nil:
+		return ""
+	case a.Region == "":
+		return a.Country
+	default:
+		return a.Country + "-" + a.Region
+	}
+}
+
+func addressToProto(a *Address) *v1.Address {
+	if a == nil {
+		return nil
+	}
+	return &v1.Address{
+		Name:       a.Name,
+		Line1:      a.Line1,
+		Line2:      a.Line2,
+		City:       a.City,
+		Region:     a.Region,
+		PostalCode: a.PostalCode,
+		Country:    a.Country,
+		Phone:      a.Phone,
+	}
+}
@@ -0,0 +1,19 @@
+package orders
+
+import "errors"
+
+var (
+	ErrOrderNotFound     = errors.New("orders: order not found")
+	ErrOrderNotEditable  = errors.New("orders: only orders pending payment can be changed")
+	ErrOrderNotDeletable = errors.New("orders: only orders pending payment or cancelled can be deleted, void or refund them first")
+	ErrUnknownBook       = errors.New("orders: unknown book")
+	ErrNotForSale        = errors.New("orders: book is not for sale")
+	ErrInvalidTransition = errors.New("orders: payment operation not allowed for the order status")
+	ErrPaymentNotFound   = errors.New("orders: payment not found")
+	ErrPaymentPending    = errors.New("orders: payment outcome unknown, retry later")
+	ErrInvalidAddress    = errors.New("orders: invalid address")
+	ErrNoShippingAddress = errors.New("orders: order has no shipping address")
+	ErrNotShippable      = errors.New("orders: only paid orders can be shipped")
+	ErrAlreadyShipped    = errors.New("orders: books were already shipped or are not in the order")
+	ErrShipmentNotFound  = errors.New("orders: shipment not found")
+)
@@ -0,0 +1,182 @@
+package orders
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/iho/bookstore/internal/money"
+	"github.com/iho/bookstore/internal/payments"
+	moneyv1 "github.com/iho/bookstore/protos/gen/money/v1"
+	v1 "github.com/iho/bookstore/protos/gen/orders/v1"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var eventTypes = map[string]v1.EventType{
+	"insert":  v1.EventType_EVENT_TYPE_CREATED,
+	"update":  v1.EventType_EVENT_TYPE_UPDATED,
+	"replace": v1.EventType_EVENT_TYPE_UPDATED,
+	"delete":  v1.EventType_EVENT_TYPE_DELETED,
+}
+
+var orderStatuses = map[Status]v1.OrderStatus{
+	StatusPendingPayment:   v1.OrderStatus_ORDER_STATUS_PENDING_PAYMENT,
+	StatusAuthorized:       v1.OrderStatus_ORDER_STATUS_AUTHORIZED,
+	StatusPaid:             v1.OrderStatus_ORDER_STATUS_PAID,
+	StatusPaymentFailed:    v1.OrderStatus_ORDER_STATUS_PAYMENT_FAILED,
+	StatusRefunded:         v1.OrderStatus_ORDER_STATUS_REFUNDED,
+	StatusCancelled:        v1.OrderStatus_ORDER_STATUS_CANCELLED,
+	StatusPartiallyShipped: v1.OrderStatus_ORDER_STATUS_PARTIALLY_SHIPPED,
+	StatusShipped:          v1.OrderStatus_ORDER_STATUS_SHIPPED,
+	StatusDelivered:        v1.OrderStatus_ORDER_STATUS_DELIVERED,
+}
+
+var paymentOperations = map[payments.Operation]v1.PaymentOperation{
+	payments.OperationAuthorize: v1.PaymentOperation_PAYMENT_OPERATION_AUTHORIZE,
+	payments.OperationCapture:   v1.PaymentOperation_PAYMENT_OPERATION_CAPTURE,
+	payments.OperationRefund:    v1.PaymentOperation_PAYMENT_OPERATION_REFUND,
+	payments.OperationVoid:      v1.PaymentOperation_PAYMENT_OPERATION_VOID,
+}
+
+var paymentStatuses = map[PaymentStatus]v1.PaymentStatus{
+	PaymentPending:   v1.PaymentStatus_PAYMENT_STATUS_PENDING,
+	PaymentSucceeded: v1.PaymentStatus_PAYMENT_STATUS_SUCCEEDED,
+	PaymentDeclined:  v1.PaymentStatus_PAYMENT_STATUS_DECLINED,
+}
+
+type changeEvent struct {
+	OperationType string `bson:"operationType"`
+	FullDocument  *Order `bson:"fullDocument"`
+	DocumentKey   struct {
+		ID primitive.ObjectID `bson:"_id"`
+	} `bson:"documentKey"`
+}
+
+// WatchOrders streams changes from a Mongo change stream. Resume tokens are
+// the base64 encoded change stream resume tokens, so Mongo must run as a
+// replica set.
+func (os *OrdersService) WatchOrders(ctx context.Context, req *connect.Request[v1.WatchOrdersRequest], stream *connect.ServerStream[v1.WatchOrdersResponse]) error {
+	opts := options.ChangeStream().SetFullDocument(options.UpdateLookup)
+	if token := req.Msg.GetResumeToken(); token != "" {
+		raw, err := base64.RawURLEncoding.DecodeString(token)
+		if err != nil {
+			return connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("invalid resume token: %w", err))
+		}
+		opts.SetResumeAfter(bson.Raw(raw))
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{
+			{Key: "operationType", Value: bson.D{{Key: "$in", Value: bson.A{"insert", "update", "replace", "delete"}}}},
+		}}},
+	}
+
+	coll, err := os.orders(ctx)
+	if err != nil {
+		return err
+	}
+	changes, err := coll.Watch(ctx, pipeline, opts)
+	if err != nil {
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	defer changes.Close(context.Background())
+
+	for changes.Next(ctx) {
+		var event changeEvent
+		if err := changes.Decode(&event); err != nil {
+			return connect.NewError(connect.CodeInternal, err)
+		}
+
+		order := &v1.Order{Id: event.DocumentKey.ID.Hex()}
+		if event.FullDocument != nil {
+			// The document is missing when it was deleted before the lookup.
+			order = os.orderToProto(event.FullDocument)
+		}
+
+		err := stream.Send(&v1.WatchOrdersResponse{
+			Type:        eventTypes[event.OperationType],
+			Order:       order,
+			ResumeToken: base64.RawURLEncoding.EncodeToString(changes.ResumeToken()),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if ctx.Err() != nil {
+		return nil
+	}
+	if err := changes.Err(); err != nil {
+		return connect.NewError(connect.CodeInternal, err)
+	}
+	return nil
+}
+
+func (os *OrdersService) orderToProto(order *Order) *v1.Order {
+	orderLines := make([]*v1.OrderLine, 0, len(order.OrderLines))
+	for _, line := range order.OrderLines {
+		discounts := make([]*v1.AppliedDiscount, 0, len(line.Discounts))
+		for _, d := range line.Discounts {
+			discounts = append(discounts, &v1.AppliedDiscount{
+				PromotionId: d.PromotionID,
+				Name:        d.Name,
+				Code:        d.Code,
+				Amount:      os.money(order, d.Amount),
+			})
+		}
+		orderLines = append(orderLines, &v1.OrderLine{
+			BookId:     line.BookId,
+			Quantity:   line.Quantity,
+			UnitPrice:  os.money(order, line.UnitPrice),
+			Discounts:  discounts,
+			TotalPrice: os.money(order, line.TotalPrice),
+			AuthorId:   line.AuthorID,
+		})
+	}
+
+	attempts := make([]*v1.PaymentAttempt, 0, len(order.Payments))
+	for _, p := range order.Payments {
+		attempts = append(attempts, &v1.PaymentAttempt{
+			Id:        p.ID,
+			Operation: paymentOperations[p.Operation],
+			Status:    paymentStatuses[p.Status],
+			Amount:    os.money(order, p.Amount),
+			Reference: p.Reference,
+			Reason:    p.Reason,
+			CreatedAt: p.CreatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+
+	shipments := make([]*v1.Shipment, 0, len(order.Shipments))
+	for _, s := range order.Shipments {
+		shipments = append(shipments, shipmentToProto(s))
+	}
+
+	return &v1.Order{
+		Id:              order.ID.Hex(),
+		OrderLines:      orderLines,
+		OrderDate:       order.OrderDate,
+		Status:          orderStatuses[order.status()],
+		Payments:        attempts,
+		CouponCode:      order.CouponCode,
+		SubtotalPrice:   os.money(order, order.SubtotalPrice),
+		DiscountTotal:   os.money(order, order.DiscountTotal),
+		TaxTotal:        os.money(order, order.TaxTotal),
+		TotalPrice:      os.money(order, order.TotalPrice),
+		TaxRegion:       order.TaxRegion,
+		ShippingAddress: addressToProto(order.ShippingAddress),
+		BillingAddress:  addressToProto(order.BillingAddress),
+		Shipments:       shipments,
+		CustomerId:      order.CustomerID,
+	}
+}
+
+// money returns amount in the currency of order.
+func (os *OrdersService) money(order *Order, amount int64) *moneyv1.Money {
+	return money.New(amount, order.currency(os.currency)).Proto()
+}
@@ -1,10 +1,23 @@
 package orders
 
 import (
+	"cmp"
 	"context"
 	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
 
 	"connectrpc.com/connect"
+	"github.com/iho/bookstore/internal/idempotency"
+	"github.com/iho/bookstore/internal/metrics"
+	"github.com/iho/bookstore/internal/money"
+	"github.com/iho/bookstore/internal/payments"
+	"github.com/iho/bookstore/internal/promotions"
+	"github.com/iho/bookstore/internal/tax"
+	"github.com/iho/bookstore/internal/tenant"
+	eventsv1 "github.com/iho/bookstore/protos/gen/events/v1"
 	v1 "github.com/iho/bookstore/protos/gen/orders/v1"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -15,72 +28,51 @@ import (
 const (
 	bookStoreKey       = "bookstore"
 	orderCollectionKey = "orders"
+	// maxCustomerID is the longest customer ID an order may have.
+	maxCustomerID = 200
 )
 
 type OrdersService struct {
-	client *mongo.Client
+	client      *mongo.Client
+	idempotency idempotency.Store
+	payments    payments.PaymentProvider
+	books       BookStore
+	promotions  *promotions.Store
+	// currency is the currency of the store, orders default to it.
+	currency string
+	rates    *money.Rates
+	taxes    tax.Calculator
+	// indexed holds the names of the orders collections that have their
+	// indexes.
+	indexed sync.Map
 }
 
-func NewOrdersService(client *mongo.Client) *OrdersService {
-	return &OrdersService{client: client}
-}
-
-func (os *OrdersService) ListOrders(ctx context.Context, req *connect.Request[v1.ListOrdersRequest]) (*connect.Response[v1.ListOrdersResponse], error) {
-	var limit int64 = 10
-	var offset int64 = 0
-	if req.Msg.Limit < 1 {
-		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("limit must be greater than 0"))
-	}
-
-	if req.Msg.Offset < 0 {
-		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("offset must be greater than or equal to 0"))
-	}
-	filter := bson.D{}
-	if req.Msg.BookId != "" {
-		filter = bson.D{{"order_lines.book_id", req.Msg.BookId}}
+// NewOrdersService creates the service. Orders are priced with the book
+// prices from books converted with rates, the promotions in promos and the
+// taxes from taxes. idem may be nil, in which case idempotency keys are
+// ignored.
+func NewOrdersService(client *mongo.Client, idem idempotency.Store, provider payments.PaymentProvider, books BookStore, promos *promotions.Store, currency string, rates *money.Rates, taxes tax.Calculator) *OrdersService {
+	return &OrdersService{
+		client:      client,
+		idempotency: idem,
+		payments:    provider,
+		books:       books,
+		promotions:  promos,
+		currency:    currency,
+		rates:       rates,
+		taxes:       taxes,
 	}
+}
 
-	limit = int64(req.Msg.Limit)
-	offset = int64(req.Msg.Offset)
-
-	orders := make([]*v1.Order, 0, req.Msg.Limit)
-
-	cursor, err := os.client.Database(bookStoreKey).Collection(orderCollectionKey).Find(ctx, filter, &options.FindOptions{
-		Limit: &limit,
-		Skip:  &offset,
-	})
+// orders returns the orders collection of the tenant of ctx. Every tenant
+// has a collection of its own, so neither queries nor change streams can
+// reach the orders of another tenant.
+func (os *OrdersService) orders(ctx context.Context) (*mongo.Collection, error) {
+	id, err := tenant.Require(ctx)
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInternal, err)
-	}
-
-	defer cursor.Close(ctx)
-	for cursor.Next(ctx) {
-		order := new(Order)
-		if err := cursor.Decode(order); err != nil {
-			return nil, connect.NewError(connect.CodeInternal, err)
-		}
-
-		orderLines := make([]*v1.OrderLine, 0, len(order.OrderLines))
-		for _, line := range order.OrderLines {
-			orderLines = append(orderLines, &v1.OrderLine{
-				BookId:   line.BookId,
-				Quantity: line.Quantity,
-			})
-		}
-
-		orders = append(orders, &v1.Order{
-			Id:         order.ID.Hex(),
-			OrderLines: orderLines,
-			TotalPrice: order.TotalPrice,
-			OrderDate:  order.OrderDate,
-		})
+		return nil, err
 	}
-
-	return &connect.Response[v1.ListOrdersResponse]{
-		Msg: &v1.ListOrdersResponse{
-			Orders: orders,
-		},
-	}, nil
+	return os.client.Database(bookStoreKey).Collection(orderCollectionKey + "_" + id), nil
 }
 
 func (os *OrdersService) GetOrder(ctx context.Context, req *connect.Request[v1.GetOrderRequest]) (*connect.Response[v1.GetOrderResponse], error) {
@@ -94,86 +86,115 @@ func (os *OrdersService) GetOrder(ctx context.Context, req *connect.Request[v1.G
 		return nil, connect.NewError(connect.CodeInvalidArgument, err)
 	}
 	filter := bson.D{{"_id", id}}
-	if err := os.client.Database(bookStoreKey).Collection(orderCollectionKey).FindOne(ctx, filter).Decode(order); err != nil {
-		return nil, connect.NewError(connect.CodeInternal, err)
+	coll, err := os.orders(ctx)
+	if err != nil {
+		return nil, err
 	}
-
-	orderLines := make([]*v1.OrderLine, 0, len(order.OrderLines))
-	for _, line := range order.OrderLines {
-		orderLines = append(orderLines, &v1.OrderLine{
-			BookId:   line.BookId,
-			Quantity: line.Quantity,
-		})
+	if err := coll.FindOne(ctx, filter).Decode(order); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
 	}
 
 	return &connect.Response[v1.GetOrderResponse]{
 		Msg: &v1.GetOrderResponse{
-			Order: &v1.Order{
-				Id:         order.ID.Hex(),
-				OrderLines: orderLines,
-				TotalPrice: order.TotalPrice,
-				OrderDate:  order.OrderDate,
-			},
+			Order: os.orderToProto(order),
 		},
 	}, nil
 }
 
 func (os *OrdersService) CreateOrder(ctx context.Context, req *connect.Request[v1.CreateOrderRequest]) (*connect.Response[v1.CreateOrderResponse], error) {
-	if req.Msg.TotalPrice < 0 {
-		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("total price must be greater than 0"))
-	}
+	return idempotency.Handle(ctx, os.idempotency, req, os.createOrder)
+}
 
+func (os *OrdersService) createOrder(ctx context.Context, req *connect.Request[v1.CreateOrderRequest]) (*connect.Response[v1.CreateOrderResponse], error) {
 	if req.Msg.OrderDate == "" {
 		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("order date must be provided"))
 	}
+	// reports and lists go by the parsed date
+	if _, err := parseTime("order_date", req.Msg.OrderDate); err != nil {
+		return nil, err
+	}
+
+	orderLines, err := newOrderLines(req.Msg.GetOrderLines())
+	if err != nil {
+		return nil, err
+	}
+	shipping, err := newAddress("shipping_address", req.Msg.ShippingAddress)
+	if err != nil {
+		return nil, err
+	}
+	billing, err := newAddress("billing_address", req.Msg.BillingAddress)
+	if err != nil {
+		return nil, err
+	}
+	customerID := strings.TrimSpace(req.Msg.CustomerId)
+	if len(customerID) > maxCustomerID {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("customer ID must be at most %d bytes", maxCustomerID))
+	}
+	q, err := os.price(ctx, orderLines, terms{
+		couponCode: req.Msg.CouponCode,
+		currency:   req.Msg.Currency,
+		taxRegion:  cmp.Or(req.Msg.TaxRegion, shipping.taxRegion()),
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
 
-	if len(req.Msg.GetOrderLines()) == 0 {
-		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("order must have at least one line"))
+	coll, err := os.orders(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	orderLines := make([]*OrderLine, 0, len(req.Msg.GetOrderLines()))
-	for _, line := range req.Msg.GetOrderLines() {
-		if line.Quantity < 1 {
-			return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("quantity must be greater than 0"))
+	order := NewOrder(orderLines, q.total, req.Msg.GetOrderDate())
+	applyPricing(order, q)
+	order.ShippingAddress = shipping
+	order.BillingAddress = cmp.Or(billing, shipping)
+	order.CustomerID = customerID
+	err = os.inTx(ctx, func(sc mongo.SessionContext) error {
+		if err := os.promotions.Redeem(sc, q.Applied); err != nil {
+			return err
 		}
-
-		if line.BookId == "" {
-			return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("book ID must be provided"))
+		if _, err := coll.InsertOne(sc, order); err != nil {
+			return err
 		}
 
-		orderLines = append(orderLines, NewOrderLine(line.GetBookId(), line.GetQuantity()))
+		return os.addEvent(sc, order.ID.Hex(), &eventsv1.OrderPlaced{
+			OrderId:       order.ID.Hex(),
+			OrderLines:    orderLinesToEvent(order.OrderLines),
+			OrderDate:     order.OrderDate,
+			CouponCode:    order.CouponCode,
+			SubtotalPrice: os.money(order, order.SubtotalPrice),
+			DiscountTotal: os.money(order, order.DiscountTotal),
+			TaxTotal:      os.money(order, order.TaxTotal),
+			TotalPrice:    os.money(order, order.TotalPrice),
+			TaxRegion:     order.TaxRegion,
+		})
+	})
+	if err != nil {
+		return nil, connectError(err)
 	}
 
-	order := NewOrder(orderLines, req.Msg.GetTotalPrice(), req.Msg.GetOrderDate())
-	res, err := os.client.Database(bookStoreKey).Collection(orderCollectionKey).InsertOne(ctx, order)
-	if err != nil {
-		return nil, connect.NewError(connect.CodeInternal, err)
+	ordersCreated.Inc()
+	metrics.Add(ctx, orderRevenue.WithLabelValues(order.Currency), float64(order.TotalPrice))
+	for _, line := range order.OrderLines {
+		orderLineQuantity.Observe(float64(line.Quantity))
 	}
 
 	return &connect.Response[v1.CreateOrderResponse]{
 		Msg: &v1.CreateOrderResponse{
-			Order: &v1.Order{
-				Id:         res.InsertedID.(primitive.ObjectID).Hex(),
-				OrderLines: req.Msg.GetOrderLines(),
-				TotalPrice: req.Msg.GetTotalPrice(),
-				OrderDate:  req.Msg.GetOrderDate(),
-			},
+			Order: os.orderToProto(order),
 		},
 	}, nil
 }
 
 func (os *OrdersService) UpdateOrder(ctx context.Context, req *connect.Request[v1.UpdateOrderRequest]) (*connect.Response[v1.UpdateOrderResponse], error) {
-	if req.Msg.TotalPrice < 0 {
-		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("total price must be greater than 0"))
-	}
-
 	if req.Msg.OrderDate == "" {
 		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("order date must be provided"))
 	}
-
-	if len(req.Msg.GetOrderLines()) == 0 {
-		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("order must have at least one line"))
+	// reports and lists go by the parsed date
+	if _, err := parseTime("order_date", req.Msg.OrderDate); err != nil {
+		return nil, err
 	}
+
 	if req.Msg.Id == "" {
 		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("order ID must be provided"))
 	}
@@ -182,41 +203,122 @@ func (os *OrdersService) UpdateOrder(ctx context.Context, req *connect.Request[v
 		return nil, connect.NewError(connect.CodeInvalidArgument, err)
 	}
 
-	orderLines := make([]*OrderLine, 0, len(req.Msg.GetOrderLines()))
-	for _, line := range req.Msg.GetOrderLines() {
-		if line.Quantity < 1 {
-			return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("quantity must be greater than 0"))
+	orderLines, err := newOrderLines(req.Msg.GetOrderLines())
+	if err != nil {
+		return nil, err
+	}
+	shipping, err := newAddress("shipping_address", req.Msg.ShippingAddress)
+	if err != nil {
+		return nil, err
+	}
+	billing, err := newAddress("billing_address", req.Msg.BillingAddress)
+	if err != nil {
+		return nil, err
+	}
+	coll, err := os.orders(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// lines and prices are fixed once a payment went through, and while an
+	// attempt is pending, as it may go through for the previous total;
+	// orders from before payments have no status
+	filter := bson.D{
+		{Key: "_id", Value: id},
+		{Key: "status", Value: bson.D{{Key: "$in", Value: bson.A{StatusPendingPayment, StatusPaymentFailed, nil}}}},
+		{Key: "payments.status", Value: bson.D{{Key: "$ne", Value: PaymentPending}}},
+	}
+	updated := new(Order)
+	err = os.inTx(ctx, func(sc mongo.SessionContext) error {
+		// the order is priced as the transaction reads it, so that the
+		// promotions it counts against are the ones it is priced with. The
+		// coupon is kept unless the update replaces it, along with the
+		// currency, and the tax region unless the order ships somewhere else
+		// now; the order still counts against the promotions it was
+		// discounted by
+		current, err := findOrder(sc, coll, id)
+		if err != nil {
+			return err
+		}
+		couponCode := current.CouponCode
+		if req.Msg.CouponCode != nil {
+			couponCode = req.Msg.GetCouponCode()
+		}
+		q, err := os.price(sc, orderLines, terms{
+			couponCode: couponCode,
+			currency:   current.currency(os.currency),
+			taxRegion:  cmp.Or(shipping.taxRegion(), current.TaxRegion),
+		}, current.promotionIDs())
+		if err != nil {
+			return err
 		}
 
-		if line.BookId == "" {
-			return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("book ID must be provided"))
+		order := NewOrder(orderLines, q.total, req.Msg.GetOrderDate())
+		applyPricing(order, q)
+		order.ID = primitive.NilObjectID
+		order.OrderedAt = orderedAt(order.OrderDate, id)
+		// payments own the status, an update must not reset it
+		order.Status = ""
+		// addresses that are not set are kept
+		order.ShippingAddress = shipping
+		order.BillingAddress = billing
+		update := bson.M{
+			"$set": order,
+		}
+		if order.CouponCode == "" {
+			update["$unset"] = bson.M{"coupon_code": ""}
+		}
+		err = coll.FindOneAndUpdate(sc, filter, update, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(updated)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			if current.paymentPending() {
+				return connect.NewError(connect.CodeFailedPrecondition, ErrPaymentPending)
+			}
+			return connect.NewError(connect.CodeFailedPrecondition, ErrOrderNotEditable)
+		}
+		if err != nil {
+			return connect.NewError(connect.CodeInternal, err)
 		}
 
-		orderLines = append(orderLines, NewOrderLine(line.GetBookId(), line.GetQuantity()))
-	}
+		// the order counts against the promotions it is discounted by now
+		// instead of the ones it was before
+		before, after := current.promotionIDs(), updated.promotionIDs()
+		var added []*promotions.Promotion
+		for _, p := range q.Applied {
+			if !slices.Contains(before, p.ID) {
+				added = append(added, p)
+			}
+		}
+		var removed []primitive.ObjectID
+		for _, promotionID := range before {
+			if !slices.Contains(after, promotionID) {
+				removed = append(removed, promotionID)
+			}
+		}
+		if err := os.promotions.Redeem(sc, added); err != nil {
+			return err
+		}
+		if err := os.promotions.Release(sc, removed); err != nil {
+			return err
+		}
 
-	order := NewOrder(orderLines, req.Msg.GetTotalPrice(), req.Msg.GetOrderDate())
-	order.ID = primitive.NilObjectID
-	update := bson.M{
-		"$set": order,
-	}
-	res, err := os.client.Database(bookStoreKey).Collection(orderCollectionKey).UpdateByID(ctx, id, update)
+		return os.addEvent(sc, id.Hex(), &eventsv1.OrderUpdated{
+			OrderId:       id.Hex(),
+			OrderLines:    orderLinesToEvent(order.OrderLines),
+			OrderDate:     order.OrderDate,
+			CouponCode:    order.CouponCode,
+			SubtotalPrice: os.money(order, order.SubtotalPrice),
+			DiscountTotal: os.money(order, order.DiscountTotal),
+			TaxTotal:      os.money(order, order.TaxTotal),
+			TotalPrice:    os.money(order, order.TotalPrice),
+			TaxRegion:     order.TaxRegion,
+		})
+	})
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInternal, err)
-	}
-
-	if res.ModifiedCount == 0 {
-		return nil, connect.NewError(connect.CodeNotFound, errors.New("order not found"))
+		return nil, connectError(err)
 	}
 
 	return &connect.Response[v1.UpdateOrderResponse]{
 		Msg: &v1.UpdateOrderResponse{
-			Order: &v1.Order{
-				Id:         id.Hex(),
-				OrderLines: req.Msg.GetOrderLines(),
-				TotalPrice: req.Msg.GetTotalPrice(),
-				OrderDate:  req.Msg.GetOrderDate(),
-			},
+			Order: os.orderToProto(updated),
 		},
 	}, nil
 }
@@ -231,14 +333,56 @@ func (os *OrdersService) DeleteOrder(ctx context.Context, req *connect.Request[v
 	}
 
 	filter := bson.D{{"_id", id}}
-	res, err := os.client.Database(bookStoreKey).Collection(orderCollectionKey).DeleteOne(ctx, filter)
+	coll, err := os.orders(ctx)
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInternal, err)
+		return nil, err
+	}
+	// orders a payment went through for, or may still go through for, are
+	// voided or refunded instead, so that the provider's payments keep an
+	// order; orders from before payments have no status
+	deletable := append(filter,
+		bson.E{Key: "status", Value: bson.D{{Key: "$in", Value: bson.A{StatusPendingPayment, StatusPaymentFailed, StatusCancelled, nil}}}},
+		bson.E{Key: "payments.status", Value: bson.D{{Key: "$ne", Value: PaymentPending}}},
+	)
+	var deleted bool
+	err = os.inTx(ctx, func(sc mongo.SessionContext) error {
+		order := new(Order)
+		err := coll.FindOneAndDelete(sc, deletable).Decode(order)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			current, err := findOrder(sc, coll, id)
+			if connect.CodeOf(err) == connect.CodeNotFound {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if current.paymentPending() {
+				return connect.NewError(connect.CodeFailedPrecondition, ErrPaymentPending)
+			}
+			return connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("%w: [status=%s]", ErrOrderNotDeletable, current.status()))
+		}
+		if err != nil {
+			return err
+		}
+		deleted = true
+
+		// cancelled and refunded orders gave their promotions back already
+		if !order.status().releasesPromotions() {
+			if err := os.promotions.Release(sc, order.promotionIDs()); err != nil {
+				return err
+			}
+		}
+		return os.addEvent(sc, id.Hex(), &eventsv1.OrderDeleted{
+			OrderId: id.Hex(),
+		})
+	})
+	if err != nil {
+		return nil, connectError(err)
 	}
 
 	return &connect.Response[v1.DeleteOrderResponse]{
 		Msg: &v1.DeleteOrderResponse{
-			Status: res.DeletedCount > 0,
+			Status: deleted,
 		},
 	}, nil
 }
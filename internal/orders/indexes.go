@@ -0,0 +1,115 @@
+package orders
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// dateOrdersBatch is how many orders dateOrders updates at once.
+const dateOrdersBatch = 500
+
+// orderIndexes serve the filters and sorts of ListOrders. Every sort ends
+// with _id so that pages have a stable order.
+var orderIndexes = []mongo.IndexModel{
+	{Keys: bson.D{{Key: "ordered_at", Value: -1}, {Key: "_id", Value: -1}}},
+	{Keys: bson.D{{Key: "currency", Value: 1}, {Key: "total_price", Value: -1}, {Key: "_id", Value: -1}}},
+	{Keys: bson.D{{Key: "status", Value: 1}, {Key: "ordered_at", Value: -1}, {Key: "_id", Value: -1}}},
+	{Keys: bson.D{{Key: "customer_id", Value: 1}, {Key: "ordered_at", Value: -1}, {Key: "_id", Value: -1}}},
+	{Keys: bson.D{{Key: "order_lines.book_id", Value: 1}, {Key: "ordered_at", Value: -1}, {Key: "_id", Value: -1}}},
+}
+
+// EnsureIndexes indexes the orders collection of every tenant, dating the
+// orders stored before ordered_at existed. It is meant to run at startup,
+// the collections of new tenants are indexed when their orders are first
+// listed.
+func (os *OrdersService) EnsureIndexes(ctx context.Context) error {
+	db := os.client.Database(bookStoreKey)
+	names, err := db.ListCollectionNames(ctx, bson.D{
+		{Key: "name", Value: bson.D{{Key: "$regex", Value: "^" + orderCollectionKey + "_"}}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list orders collections: %w", err)
+	}
+	for _, name := range names {
+		if err := ensureIndexes(ctx, db.Collection(name)); err != nil {
+			return err
+		}
+		os.indexed.Store(name, struct{}{})
+	}
+	return nil
+}
+
+// indexedOrders returns the orders collection of the tenant of ctx, making
+// sure it has its indexes. Indexes cannot be created in transactions, so
+// ctx must not be a session context.
+func (os *OrdersService) indexedOrders(ctx context.Context) (*mongo.Collection, error) {
+	coll, err := os.orders(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := os.indexed.Load(coll.Name()); ok {
+		return coll, nil
+	}
+
+	if err := ensureIndexes(ctx, coll); err != nil {
+		return nil, err
+	}
+	os.indexed.Store(coll.Name(), struct{}{})
+	return coll, nil
+}
+
+// ensureIndexes dates the orders of coll without ordered_at and creates
+// orderIndexes.
+func ensureIndexes(ctx context.Context, coll *mongo.Collection) error {
+	if err := dateOrders(ctx, coll); err != nil {
+		return fmt.Errorf("failed to date orders: [collection=%s] %w", coll.Name(), err)
+	}
+
+	if _, err := coll.Indexes().CreateMany(ctx, orderIndexes); err != nil {
+		return fmt.Errorf("failed to create order indexes: [collection=%s] %w", coll.Name(), err)
+	}
+	return nil
+}
+
+// dateOrders sets ordered_at on the orders of coll stored before it existed,
+// with orderedAt like new orders get it.
+func dateOrders(ctx context.Context, coll *mongo.Collection) error {
+	undated := bson.D{{Key: "ordered_at", Value: bson.D{{Key: "$exists", Value: false}}}}
+	cursor, err := coll.Find(ctx, undated, options.Find().SetProjection(bson.D{{Key: "order_date", Value: 1}}))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	var updates []mongo.WriteModel
+	flush := func() error {
+		if len(updates) == 0 {
+			return nil
+		}
+		_, err := coll.BulkWrite(ctx, updates, options.BulkWrite().SetOrdered(false))
+		updates = updates[:0]
+		return err
+	}
+	for cursor.Next(ctx) {
+		var order Order
+		if err := cursor.Decode(&order); err != nil {
+			return err
+		}
+		updates = append(updates, mongo.NewUpdateOneModel().
+			SetFilter(append(bson.D{{Key: "_id", Value: order.ID}}, undated...)).
+			SetUpdate(bson.D{{Key: "$set", Value: bson.D{{Key: "ordered_at", Value: orderedAt(order.OrderDate, order.ID)}}}}))
+		if len(updates) == dateOrdersBatch {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+	return flush()
+}
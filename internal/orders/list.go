@@ -0,0 +1,287 @@
+package orders
+
+import (
+	"cmp"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/iho/bookstore/internal/money"
+	moneyv1 "github.com/iho/bookstore/protos/gen/money/v1"
+	v1 "github.com/iho/bookstore/protos/gen/orders/v1"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	defaultListLimit = 10
+	maxListLimit     = 100
+	// maxListBookIDs keeps the $in of the book filter small.
+	maxListBookIDs = 100
+)
+
+// orderSort is the field orders are sorted by, ties are sorted by _id in
+// the same direction.
+type orderSort struct {
+	field string
+	order int
+}
+
+var orderSorts = map[v1.OrderSort]orderSort{
+	v1.OrderSort_ORDER_SORT_DATE_ASC:   {"ordered_at", 1},
+	v1.OrderSort_ORDER_SORT_DATE_DESC:  {"ordered_at", -1},
+	v1.OrderSort_ORDER_SORT_TOTAL_ASC:  {"total_price", 1},
+	v1.OrderSort_ORDER_SORT_TOTAL_DESC: {"total_price", -1},
+}
+
+// pageToken is the order a page starts after, in the sort of the request,
+// or before when Backward.
+type pageToken struct {
+	Sort      v1.OrderSort       `json:"s"`
+	ID        primitive.ObjectID `json:"i"`
+	OrderedAt time.Time          `json:"d"`
+	Total     int64              `json:"t"`
+	Backward  bool               `json:"b,omitempty"`
+}
+
+func newPageToken(sort v1.OrderSort, order *Order, backward bool) string {
+	b, err := json.Marshal(&pageToken{
+		Sort:      sort,
+		ID:        order.ID,
+		OrderedAt: order.OrderedAt,
+		Total:     order.TotalPrice,
+		Backward:  backward,
+	})
+	if err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func parsePageToken(s string, sort v1.OrderSort) (*pageToken, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("invalid page token"))
+	}
+	t := new(pageToken)
+	if err := json.Unmarshal(b, t); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("invalid page token"))
+	}
+	if t.Sort != sort {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("page token is for another sort"))
+	}
+	return t, nil
+}
+
+// after matches the orders after t when sorted by field in order.
+func (t *pageToken) after(field string, order int) bson.E {
+	op := "$gt"
+	if order < 0 {
+		op = "$lt"
+	}
+	var value any = t.Total
+	if field == "ordered_at" {
+		value = t.OrderedAt
+	}
+	return bson.E{Key: "$or", Value: bson.A{
+		bson.D{{Key: field, Value: bson.D{{Key: op, Value: value}}}},
+		bson.D{{Key: field, Value: value}, {Key: "_id", Value: bson.D{{Key: op, Value: t.ID}}}},
+	}}
+}
+
+func (os *OrdersService) ListOrders(ctx context.Context, req *connect.Request[v1.ListOrdersRequest]) (*connect.Response[v1.ListOrdersResponse], error) {
+	limit := int64(cmp.Or(req.Msg.Limit, defaultListLimit))
+	if limit < 1 || limit > maxListLimit {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("limit must be between 1 and %d", maxListLimit))
+	}
+	if req.Msg.Offset < 0 {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("offset must be greater than or equal to 0"))
+	}
+	offset := int64(req.Msg.Offset)
+
+	sortBy := cmp.Or(req.Msg.Sort, v1.OrderSort_ORDER_SORT_DATE_DESC)
+	by, ok := orderSorts[sortBy]
+	if !ok {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("unknown sort %s", req.Msg.Sort))
+	}
+	var token *pageToken
+	if req.Msg.PageToken != "" {
+		if offset > 0 {
+			return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("offset cannot be combined with page_token"))
+		}
+		var err error
+		if token, err = parsePageToken(req.Msg.PageToken, sortBy); err != nil {
+			return nil, err
+		}
+	}
+	filter, err := os.listFilter(req.Msg)
+	if err != nil {
+		return nil, err
+	}
+
+	coll, err := os.indexedOrders(ctx)
+	if err != nil {
+		return nil, err
+	}
+	total, err := coll.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to count orders: %w", err))
+	}
+
+	// previous pages are read backwards from the first order of the page
+	// after them
+	order := by.order
+	page := filter
+	if token != nil {
+		if token.Backward {
+			order = -order
+		}
+		page = append(slices.Clip(filter), token.after(by.field, order))
+	}
+	cursor, err := coll.Find(ctx, page, options.Find().
+		SetSort(bson.D{{Key: by.field, Value: order}, {Key: "_id", Value: order}}).
+		SetSkip(offset).
+		SetLimit(limit+1))
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	var found []*Order
+	if err := cursor.All(ctx, &found); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	more := int64(len(found)) > limit
+	if more {
+		found = found[:limit]
+	}
+	hasNext, hasPrevious := more, token != nil || offset > 0
+	if token != nil && token.Backward {
+		slices.Reverse(found)
+		hasNext, hasPrevious = true, more
+	}
+
+	orders := make([]*v1.Order, 0, len(found))
+	for _, o := range found {
+		orders = append(orders, os.orderToProto(o))
+	}
+	msg := &v1.ListOrdersResponse{
+		Orders:     orders,
+		TotalCount: total,
+	}
+	if len(found) > 0 {
+		if hasNext {
+			msg.NextPageToken = newPageToken(sortBy, found[len(found)-1], false)
+		}
+		if hasPrevious {
+			msg.PreviousPageToken = newPageToken(sortBy, found[0], true)
+		}
+	}
+
+	return &connect.Response[v1.ListOrdersResponse]{
+		Msg: msg,
+	}, nil
+}
+
+// listFilter returns the filter matching the orders selected by msg.
+func (os *OrdersService) listFilter(msg *v1.ListOrdersRequest) (bson.D, error) {
+	filter := bson.D{}
+
+	bookIDs := slices.Clone(msg.BookIds)
+	if msg.BookId != "" {
+		bookIDs = append(bookIDs, msg.BookId)
+	}
+	slices.Sort(bookIDs)
+	bookIDs = slices.Compact(bookIDs)
+	switch {
+	case len(bookIDs) > maxListBookIDs:
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("at most %d book IDs may be provided", maxListBookIDs))
+	case slices.Contains(bookIDs, ""):
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("book IDs must not be empty"))
+	case len(bookIDs) > 0:
+		filter = append(filter, bson.E{Key: "order_lines.book_id", Value: bson.D{{Key: "$in", Value: bookIDs}}})
+	}
+
+	dated := bson.D{}
+	var from, to time.Time
+	var err error
+	if msg.From != "" {
+		if from, err = parseTime("from", msg.From); err != nil {
+			return nil, err
+		}
+		dated = append(dated, bson.E{Key: "$gte", Value: from})
+	}
+	if msg.To != "" {
+		if to, err = parseTime("to", msg.To); err != nil {
+			return nil, err
+		}
+		dated = append(dated, bson.E{Key: "$lt", Value: to})
+	}
+	if msg.From != "" && msg.To != "" && !from.Before(to) {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("from must be before to"))
+	}
+	if len(dated) > 0 {
+		filter = append(filter, bson.E{Key: "ordered_at", Value: dated})
+	}
+
+	var currency string
+	if msg.Currency != "" {
+		if currency, err = money.ParseCurrency(msg.Currency); err != nil {
+			return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("invalid currency: %w", err))
+		}
+	}
+	totals := bson.D{}
+	for _, bound := range []struct {
+		field, op string
+		msg       *moneyv1.Money
+	}{
+		{"min_total", "$gte", msg.MinTotal},
+		{"max_total", "$lte", msg.MaxTotal},
+	} {
+		if bound.msg == nil {
+			continue
+		}
+		total, err := money.FromProto(bound.msg, cmp.Or(currency, os.currency))
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("invalid %s: %w", bound.field, err))
+		}
+		if currency != "" && currency != total.Currency {
+			return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("min_total, max_total and currency must have the same currency"))
+		}
+		currency = total.Currency
+		totals = append(totals, bson.E{Key: bound.op, Value: total.Amount})
+	}
+	if msg.MinTotal != nil && msg.MaxTotal != nil && msg.MinTotal.Amount > msg.MaxTotal.Amount {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("min_total must not be greater than max_total"))
+	}
+	if len(totals) > 0 {
+		filter = append(filter, bson.E{Key: "total_price", Value: totals})
+	}
+	// totals in different currencies do not compare, so orders are only
+	// sorted by total within one
+	if currency == "" && orderSorts[msg.Sort].field == "total_price" {
+		currency = os.currency
+	}
+	if currency != "" {
+		filter = append(filter, bson.E{Key: "currency", Value: matchCurrency(currency, os.currency)})
+	}
+
+	if len(msg.Statuses) > 0 {
+		statuses, err := statusesFromProto(msg.Statuses)
+		if err != nil {
+			return nil, err
+		}
+		filter = append(filter, bson.E{Key: "status", Value: matchStatuses(statuses)})
+	}
+
+	if customerID := strings.TrimSpace(msg.CustomerId); customerID != "" {
+		filter = append(filter, bson.E{Key: "customer_id", Value: customerID})
+	}
+	return filter, nil
+}
@@ -0,0 +1,36 @@
+package orders
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	ordersCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "bookstore_orders_created_total",
+		Help: "Orders created.",
+	})
+	orderRevenue = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "bookstore_order_revenue_total",
+		Help: "Sum of the total price of created orders by currency, in minor units.",
+	}, []string{"currency"})
+	orderLineQuantity = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "bookstore_order_line_quantity",
+		Help:    "Quantity per order line of created orders.",
+		Buckets: []float64{1, 2, 3, 5, 10, 20, 50},
+	})
+	paymentAttempts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "bookstore_payment_attempts_total",
+		Help: "Calls to the payment provider by operation and outcome.",
+	}, []string{"operation", "status"})
+	shipmentsCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "bookstore_shipments_created_total",
+		Help: "Shipments created.",
+	})
+	shipmentsDelivered = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "bookstore_shipments_delivered_total",
+		Help: "Shipments delivered.",
+	})
+)
+
+// RegisterMetrics registers the business metrics of the orders service.
+func RegisterMetrics(reg prometheus.Registerer) {
+	reg.MustRegister(ordersCreated, orderRevenue, orderLineQuantity, paymentAttempts, shipmentsCreated, shipmentsDelivered)
+}
@@ -1,26 +1,253 @@
 package orders
 
-import "go.mongodb.org/mongo-driver/bson/primitive"
+import (
+	"slices"
+	"time"
 
+	"github.com/iho/bookstore/internal/payments"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Status is where an order is in its payment and fulfillment. Orders stored
+// before payments existed have none and count as pending payment.
+type Status string
+
+const (
+	StatusPendingPayment Status = "pending_payment"
+	StatusAuthorized     Status = "authorized"
+	StatusPaid           Status = "paid"
+	StatusPaymentFailed  Status = "payment_failed"
+	StatusRefunded       Status = "refunded"
+	StatusCancelled      Status = "cancelled"
+	// Paid orders are partially shipped until every book was shipped.
+	StatusPartiallyShipped Status = "partially_shipped"
+	StatusShipped          Status = "shipped"
+	StatusDelivered        Status = "delivered"
+)
+
+// releasesPromotions reports whether orders with the status gave their
+// promotions back, which they do once cancelled or refunded.
+func (s Status) releasesPromotions() bool {
+	return s == StatusCancelled || s == StatusRefunded
+}
+
+// PaymentStatus is the outcome of a payment attempt.
+type PaymentStatus string
+
+const (
+	PaymentPending   PaymentStatus = "pending"
+	PaymentSucceeded PaymentStatus = "succeeded"
+	PaymentDeclined  PaymentStatus = "declined"
+)
+
+// Order prices are in minor units of Currency. Orders stored before
+// currencies existed have none and are in the currency of the store.
 type Order struct {
-	ID         primitive.ObjectID `bson:"_id,omitempty"`
-	OrderLines []*OrderLine       `bson:"order_lines,omitempty"`
-	TotalPrice int32              `bson:"total_price,omitempty"`
-	OrderDate  string             `bson:"order_date,omitempty"`
+	ID            primitive.ObjectID `bson:"_id,omitempty"`
+	OrderLines    []*OrderLine       `bson:"order_lines,omitempty"`
+	TotalPrice    int64              `bson:"total_price"`
+	OrderDate     string             `bson:"order_date,omitempty"`
+	Status        Status             `bson:"status,omitempty"`
+	Payments      []*Payment         `bson:"payments,omitempty"`
+	SubtotalPrice int64              `bson:"subtotal_price"`
+	DiscountTotal int64              `bson:"discount_total"`
+	CouponCode    string             `bson:"coupon_code,omitempty"`
+	TaxTotal      int64              `bson:"tax_total"`
+	Currency      string             `bson:"currency,omitempty"`
+	TaxRegion     string             `bson:"tax_region,omitempty"`
+	// Orders may be placed without addresses, they need a shipping address
+	// to be shipped.
+	ShippingAddress *Address    `bson:"shipping_address,omitempty"`
+	BillingAddress  *Address    `bson:"billing_address,omitempty"`
+	Shipments       []*Shipment `bson:"shipments,omitempty"`
+	// CustomerID is set when the order is created and kept by updates.
+	CustomerID string `bson:"customer_id,omitempty"`
+	// OrderedAt is OrderDate parsed, orders are listed and reported by it.
+	OrderedAt time.Time `bson:"ordered_at"`
+}
+
+type Address struct {
+	Name       string `bson:"name"`
+	Line1      string `bson:"line1"`
+	Line2      string `bson:"line2,omitempty"`
+	City       string `bson:"city"`
+	Region     string `bson:"region,omitempty"`
+	PostalCode string `bson:"postal_code,omitempty"`
+	Country    string `bson:"country"`
+	Phone      string `bson:"phone,omitempty"`
+}
+
+// Shipment is a parcel with some of the books of an order. Carriers are
+// free-form, the service does not talk to them.
+type Shipment struct {
+	ID             string          `bson:"id"`
+	Carrier        string          `bson:"carrier"`
+	TrackingNumber string          `bson:"tracking_number,omitempty"`
+	Lines          []*ShipmentLine `bson:"lines"`
+	ShippedAt      time.Time       `bson:"shipped_at"`
+	DeliveredAt    *time.Time      `bson:"delivered_at,omitempty"`
+}
+
+type ShipmentLine struct {
+	BookID   string `bson:"book_id"`
+	Quantity int32  `bson:"quantity"`
+}
+
+// Payment is a call made to the payment provider. Its ID is the idempotency
+// key of the call.
+type Payment struct {
+	ID        string             `bson:"id"`
+	Operation payments.Operation `bson:"operation"`
+	Status    PaymentStatus      `bson:"status"`
+	Amount    int64              `bson:"amount"`
+	Reference string             `bson:"reference,omitempty"`
+	Reason    string             `bson:"reason,omitempty"`
+	CreatedAt time.Time          `bson:"created_at"`
+}
+
+// currency returns the currency of the order, def for orders that predate
+// currencies.
+func (o *Order) currency(def string) string {
+	if o.Currency == "" {
+		return def
+	}
+	return o.Currency
+}
+
+func (o *Order) status() Status {
+	if o.Status == "" {
+		return StatusPendingPayment
+	}
+	return o.Status
+}
+
+// payment returns the attempt with the given ID.
+func (o *Order) payment(id string) *Payment {
+	for _, p := range o.Payments {
+		if p.ID == id {
+			return p
+		}
+	}
+	return nil
+}
+
+// shipment returns the shipment with the given ID.
+func (o *Order) shipment(id string) *Shipment {
+	for _, s := range o.Shipments {
+		if s.ID == id {
+			return s
+		}
+	}
+	return nil
+}
+
+// unshipped returns the quantity of every book of the order that was not
+// shipped yet. Books that were shipped entirely are left out.
+func (o *Order) unshipped() map[string]int32 {
+	quantities := make(map[string]int32)
+	for _, line := range o.OrderLines {
+		quantities[line.BookId] += line.Quantity
+	}
+	for _, s := range o.Shipments {
+		for _, line := range s.Lines {
+			quantities[line.BookID] -= line.Quantity
+		}
+	}
+	for bookID, quantity := range quantities {
+		if quantity <= 0 {
+			delete(quantities, bookID)
+		}
+	}
+	return quantities
+}
+
+// fulfillmentStatus returns the status the shipments of a paid order put it
+// in.
+func (o *Order) fulfillmentStatus() Status {
+	if len(o.unshipped()) > 0 {
+		return StatusPartiallyShipped
+	}
+	for _, s := range o.Shipments {
+		if s.DeliveredAt == nil {
+			return StatusShipped
+		}
+	}
+	return StatusDelivered
+}
+
+// lastPayment returns the latest attempt of operation op with status s.
+func (o *Order) lastPayment(op payments.Operation, s PaymentStatus) *Payment {
+	for i := len(o.Payments) - 1; i >= 0; i-- {
+		if p := o.Payments[i]; p.Operation == op && p.Status == s {
+			return p
+		}
+	}
+	return nil
+}
+
+// paymentPending reports whether an attempt of any operation is pending.
+func (o *Order) paymentPending() bool {
+	for _, p := range o.Payments {
+		if p.Status == PaymentPending {
+			return true
+		}
+	}
+	return false
+}
+
+// promotionIDs returns the promotions the order counts against, the ones
+// that gave one of its lines a discount.
+func (o *Order) promotionIDs() []primitive.ObjectID {
+	var ids []primitive.ObjectID
+	for _, line := range o.OrderLines {
+		for _, d := range line.Discounts {
+			id, err := primitive.ObjectIDFromHex(d.PromotionID)
+			if err == nil && !slices.Contains(ids, id) {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
 }
 
 type OrderLine struct {
-	BookId   string `bson:"book_id,omitempty"`
-	Quantity int32  `bson:"quantity,omitempty"`
+	BookId     string      `bson:"book_id,omitempty"`
+	AuthorID   string      `bson:"author_id,omitempty"`
+	Quantity   int32       `bson:"quantity,omitempty"`
+	UnitPrice  int64       `bson:"unit_price"`
+	Discounts  []*Discount `bson:"discounts,omitempty"`
+	TotalPrice int64       `bson:"total_price"`
+}
+
+// Discount is the part of a promotion that went to an order line.
+type Discount struct {
+	PromotionID string `bson:"promotion_id"`
+	Name        string `bson:"name"`
+	Code        string `bson:"code,omitempty"`
+	Amount      int64  `bson:"amount"`
 }
 
-func NewOrder(orderLines []*OrderLine, totalPrice int32, orderDate string) *Order {
+func NewOrder(orderLines []*OrderLine, totalPrice int64, orderDate string) *Order {
+	id := primitive.NewObjectID()
 	return &Order{
-		ID:         primitive.NewObjectID(),
+		ID:         id,
 		OrderLines: orderLines,
 		TotalPrice: totalPrice,
 		OrderDate:  orderDate,
+		OrderedAt:  orderedAt(orderDate, id),
+		Status:     StatusPendingPayment,
+	}
+}
+
+// orderedAt parses an order date, an RFC 3339 time or a date. Dates that
+// parse as neither are the time the order with the given ID was created.
+func orderedAt(orderDate string, id primitive.ObjectID) time.Time {
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if t, err := time.Parse(layout, orderDate); err == nil {
+			return t.UTC()
+		}
 	}
+	return id.Timestamp().UTC()
 }
 
 func NewOrderLine(bookId string, quantity int32) *OrderLine {
@@ -0,0 +1,127 @@
+package orders
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/iho/bookstore/internal/events"
+	eventsv1 "github.com/iho/bookstore/protos/gen/events/v1"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"google.golang.org/protobuf/proto"
+)
+
+const outboxCollectionKey = "outbox"
+
+type outboxMessage struct {
+	ID          string     `bson:"_id"`
+	Topic       string     `bson:"topic"`
+	Payload     []byte     `bson:"payload"`
+	CreatedAt   time.Time  `bson:"created_at"`
+	PublishedAt *time.Time `bson:"published_at,omitempty"`
+}
+
+// inTx runs fn in a transaction, so that changes and the events written to
+// the outbox are committed together. fn may be retried on transient errors.
+func (os *OrdersService) inTx(ctx context.Context, fn func(sc mongo.SessionContext) error) error {
+	session, err := os.client.StartSession()
+	if err != nil {
+		return fmt.Errorf("failed to start session: %w", err)
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sc)
+	})
+	return err
+}
+
+// addEvent writes a domain event about order id to the outbox. ctx must be
+// the session context of the transaction making the change.
+func (os *OrdersService) addEvent(ctx context.Context, id string, payload proto.Message) error {
+	msg, err := events.NewMessage(ctx, events.TopicOrders, id, payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = os.client.Database(bookStoreKey).Collection(outboxCollectionKey).InsertOne(ctx, &outboxMessage{
+		ID:        msg.ID,
+		Topic:     msg.Topic,
+		Payload:   msg.Payload,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write outbox: %w", err)
+	}
+	return nil
+}
+
+func orderLinesToEvent(lines []*OrderLine) []*eventsv1.OrderLine {
+	eventLines := make([]*eventsv1.OrderLine, 0, len(lines))
+	for _, line := range lines {
+		eventLines = append(eventLines, &eventsv1.OrderLine{
+			BookId:   line.BookId,
+			Quantity: line.Quantity,
+		})
+	}
+	return eventLines
+}
+
+// OutboxStore reads the outbox collection for events.Relay.
+type OutboxStore struct {
+	client *mongo.Client
+}
+
+func NewOutboxStore(client *mongo.Client) *OutboxStore {
+	return &OutboxStore{client: client}
+}
+
+func (s *OutboxStore) collection() *mongo.Collection {
+	return s.client.Database(bookStoreKey).Collection(outboxCollectionKey)
+}
+
+func (s *OutboxStore) Fetch(ctx context.Context, limit int) ([]events.OutboxEntry, error) {
+	filter := bson.D{{Key: "published_at", Value: bson.D{{Key: "$exists", Value: false}}}}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := s.collection().Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list outbox: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var entries []events.OutboxEntry
+	for cursor.Next(ctx) {
+		msg := new(outboxMessage)
+		if err := cursor.Decode(msg); err != nil {
+			return nil, fmt.Errorf("failed to decode outbox message: %w", err)
+		}
+		entries = append(entries, events.OutboxEntry{
+			Key: msg.ID,
+			Message: events.Message{
+				ID:      msg.ID,
+				Topic:   msg.Topic,
+				Payload: msg.Payload,
+			},
+		})
+	}
+	return entries, cursor.Err()
+}
+
+func (s *OutboxStore) MarkPublished(ctx context.Context, entries []events.OutboxEntry) error {
+	ids := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		ids = append(ids, entry.Key)
+	}
+
+	filter := bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: ids}}}}
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: "published_at", Value: time.Now()}}}}
+	if _, err := s.collection().UpdateMany(ctx, filter, update); err != nil {
+		return fmt.Errorf("failed to mark outbox published: %w", err)
+	}
+	return nil
+}
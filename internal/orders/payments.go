@@ -0,0 +1,309 @@
+package orders
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/iho/bookstore/internal/money"
+	"github.com/iho/bookstore/internal/payments"
+	"github.com/iho/bookstore/internal/tenant"
+	v1 "github.com/iho/bookstore/protos/gen/orders/v1"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// transition says which statuses an operation applies to and where it
+// takes the order. Declined attempts leave the status as it was when
+// declined is empty.
+type transition struct {
+	from      []Status
+	succeeded Status
+	declined  Status
+}
+
+var transitions = map[payments.Operation]transition{
+	payments.OperationAuthorize: {
+		from:      []Status{StatusPendingPayment, StatusPaymentFailed},
+		succeeded: StatusAuthorized,
+		declined:  StatusPaymentFailed,
+	},
+	payments.OperationCapture: {
+		from:      []Status{StatusAuthorized},
+		succeeded: StatusPaid,
+		declined:  StatusAuthorized,
+	},
+	payments.OperationVoid: {
+		from:      []Status{StatusAuthorized},
+		succeeded: StatusCancelled,
+		declined:  StatusAuthorized,
+	},
+	payments.OperationRefund: {
+		from:      []Status{StatusPaid, StatusPartiallyShipped, StatusShipped, StatusDelivered},
+		succeeded: StatusRefunded,
+	},
+}
+
+func (os *OrdersService) AuthorizePayment(ctx context.Context, req *connect.Request[v1.AuthorizePaymentRequest]) (*connect.Response[v1.AuthorizePaymentResponse], error) {
+	order, err := os.pay(ctx, req.Msg.OrderId, payments.OperationAuthorize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &connect.Response[v1.AuthorizePaymentResponse]{
+		Msg: &v1.AuthorizePaymentResponse{
+			Order: os.orderToProto(order),
+		},
+	}, nil
+}
+
+func (os *OrdersService) CapturePayment(ctx context.Context, req *connect.Request[v1.CapturePaymentRequest]) (*connect.Response[v1.CapturePaymentResponse], error) {
+	order, err := os.pay(ctx, req.Msg.OrderId, payments.OperationCapture)
+	if err != nil {
+		return nil, err
+	}
+
+	return &connect.Response[v1.CapturePaymentResponse]{
+		Msg: &v1.CapturePaymentResponse{
+			Order: os.orderToProto(order),
+		},
+	}, nil
+}
+
+func (os *OrdersService) RefundPayment(ctx context.Context, req *connect.Request[v1.RefundPaymentRequest]) (*connect.Response[v1.RefundPaymentResponse], error) {
+	order, err := os.pay(ctx, req.Msg.OrderId, payments.OperationRefund)
+	if err != nil {
+		return nil, err
+	}
+
+	return &connect.Response[v1.RefundPaymentResponse]{
+		Msg: &v1.RefundPaymentResponse{
+			Order: os.orderToProto(order),
+		},
+	}, nil
+}
+
+func (os *OrdersService) VoidPayment(ctx context.Context, req *connect.Request[v1.VoidPaymentRequest]) (*connect.Response[v1.VoidPaymentResponse], error) {
+	order, err := os.pay(ctx, req.Msg.OrderId, payments.OperationVoid)
+	if err != nil {
+		return nil, err
+	}
+
+	return &connect.Response[v1.VoidPaymentResponse]{
+		Msg: &v1.VoidPaymentResponse{
+			Order: os.orderToProto(order),
+		},
+	}, nil
+}
+
+// pay runs operation op for an order. The attempt is recorded as pending
+// before the provider is called, so that an attempt whose outcome got lost
+// is retried with the same idempotency key instead of paying twice.
+func (os *OrdersService) pay(ctx context.Context, orderID string, op payments.Operation) (*Order, error) {
+	if orderID == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("order ID must be provided"))
+	}
+	id, err := primitive.ObjectIDFromHex(orderID)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	coll, err := os.orders(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var attempt *Payment
+	var reference, currency string
+	err = os.inTx(ctx, func(sc mongo.SessionContext) error {
+		order, err := findOrder(sc, coll, id)
+		if err != nil {
+			return err
+		}
+		if !slices.Contains(transitions[op].from, order.status()) {
+			return connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("%w: [operation=%s] [status=%s]", ErrInvalidTransition, op, order.status()))
+		}
+		reference = paymentReference(order, op)
+		currency = order.currency(os.currency)
+
+		if attempt = order.lastPayment(op, PaymentPending); attempt != nil {
+			return nil
+		}
+		attempt = &Payment{
+			ID:        primitive.NewObjectID().Hex(),
+			Operation: op,
+			Status:    PaymentPending,
+			Amount:    order.TotalPrice,
+			CreatedAt: time.Now().UTC(),
+		}
+		_, err = coll.UpdateByID(sc, id, bson.D{{Key: "$push", Value: bson.D{{Key: "payments", Value: attempt}}}})
+		return err
+	})
+	if err != nil {
+		return nil, connectError(err)
+	}
+
+	res, err := payments.Call(ctx, os.payments, op, payments.Request{
+		IdempotencyKey: attempt.ID,
+		Reference:      reference,
+		Amount:         money.New(attempt.Amount, currency),
+		Metadata: map[string]string{
+			"tenant_id": tenantID,
+			"order_id":  orderID,
+		},
+	})
+	switch {
+	case errors.Is(err, payments.ErrDeclined):
+		return os.settlePayment(ctx, coll, id, attempt.ID, PaymentDeclined, "", err.Error())
+	case err != nil:
+		paymentAttempts.WithLabelValues(string(op), string(PaymentPending)).Inc()
+		return nil, connect.NewError(connect.CodeUnavailable, fmt.Errorf("%w: %w", ErrPaymentPending, err))
+	}
+	return os.settlePayment(ctx, coll, id, attempt.ID, PaymentSucceeded, res.Reference, "")
+}
+
+// paymentReference returns the provider's reference of the payment op acts
+// on: the authorization for captures and voids, the capture for refunds.
+func paymentReference(order *Order, op payments.Operation) string {
+	var source *Payment
+	switch op {
+	case payments.OperationCapture, payments.OperationVoid:
+		source = order.lastPayment(payments.OperationAuthorize, PaymentSucceeded)
+	case payments.OperationRefund:
+		source = order.lastPayment(payments.OperationCapture, PaymentSucceeded)
+	}
+	if source == nil {
+		return ""
+	}
+	return source.Reference
+}
+
+// settlePayment records the outcome of a pending attempt and moves the order
+// on if the attempt still applies to its status. Attempts that are already
+// settled are left alone, so the provider's answer and its webhook may
+// arrive in any order.
+func (os *OrdersService) settlePayment(ctx context.Context, coll *mongo.Collection, id primitive.ObjectID, paymentID string, status PaymentStatus, reference, reason string) (*Order, error) {
+	var order *Order
+	var settled bool
+	err := os.inTx(ctx, func(sc mongo.SessionContext) error {
+		var err error
+		settled = false
+		if order, err = findOrder(sc, coll, id); err != nil {
+			return err
+		}
+		attempt := order.payment(paymentID)
+		if attempt == nil {
+			return connect.NewError(connect.CodeNotFound, fmt.Errorf("%w: [id=%s]", ErrPaymentNotFound, paymentID))
+		}
+		if attempt.Status != PaymentPending {
+			return nil
+		}
+
+		attempt.Status = status
+		attempt.Reference = reference
+		attempt.Reason = reason
+		previous := order.status()
+		if t := transitions[attempt.Operation]; slices.Contains(t.from, previous) {
+			order.Status = t.succeeded
+			if status == PaymentDeclined {
+				order.Status = cmp.Or(t.declined, previous)
+			}
+		}
+
+		update := bson.D{{Key: "$set", Value: bson.D{
+			{Key: "status", Value: order.Status},
+			{Key: "payments", Value: order.Payments},
+		}}}
+		if _, err := coll.UpdateByID(sc, id, update); err != nil {
+			return err
+		}
+		if order.Status != previous && order.Status.releasesPromotions() {
+			if err := os.promotions.Release(sc, order.promotionIDs()); err != nil {
+				return err
+			}
+		}
+		settled = true
+		return os.addStatusChanged(sc, id, previous, order.Status)
+	})
+	if err != nil {
+		return nil, connectError(err)
+	}
+
+	if settled {
+		op := order.payment(paymentID).Operation
+		paymentAttempts.WithLabelValues(string(op), string(status)).Inc()
+	}
+	return order, nil
+}
+
+// HandlePaymentEvent settles the attempt a provider webhook reports on.
+// Events that cannot be matched to an attempt are logged and dropped, the
+// provider would only deliver them again.
+func (os *OrdersService) HandlePaymentEvent(ctx context.Context, event payments.Event) error {
+	log := slog.With("event_id", event.ID, "payment_id", event.IdempotencyKey)
+
+	var status PaymentStatus
+	switch event.Status {
+	case payments.StatusSucceeded:
+		status = PaymentSucceeded
+	case payments.StatusDeclined:
+		status = PaymentDeclined
+	default:
+		log.WarnContext(ctx, "dropped payment event with unknown status", "status", event.Status)
+		return nil
+	}
+
+	tenantID := event.Metadata["tenant_id"]
+	if err := tenant.Validate(tenantID); err != nil {
+		log.WarnContext(ctx, "dropped payment event without tenant", "error", err)
+		return nil
+	}
+	id, err := primitive.ObjectIDFromHex(event.Metadata["order_id"])
+	if err != nil {
+		log.WarnContext(ctx, "dropped payment event without order", "error", err)
+		return nil
+	}
+
+	ctx = tenant.WithID(ctx, tenantID)
+	coll, err := os.orders(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = os.settlePayment(ctx, coll, id, event.IdempotencyKey, status, event.Reference, event.Reason)
+	if connect.CodeOf(err) == connect.CodeNotFound {
+		log.WarnContext(ctx, "dropped payment event of unknown payment", "error", err)
+		return nil
+	}
+	return err
+}
+
+func findOrder(ctx context.Context, coll *mongo.Collection, id primitive.ObjectID) (*Order, error) {
+	order := new(Order)
+	err := coll.FindOne(ctx, bson.D{{Key: "_id", Value: id}}).Decode(order)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("%w: [id=%s]", ErrOrderNotFound, id.Hex()))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find order: %w", err)
+	}
+	return order, nil
+}
+
+// connectError keeps the code of Connect errors returned from transactions
+// and reports everything else as internal.
+func connectError(err error) error {
+	var connectErr *connect.Error
+	if errors.As(err, &connectErr) {
+		return connectErr
+	}
+	return connect.NewError(connect.CodeInternal, err)
+}
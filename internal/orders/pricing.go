@@ -0,0 +1,227 @@
+package orders
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/iho/bookstore/internal/money"
+	"github.com/iho/bookstore/internal/promotions"
+	"github.com/iho/bookstore/internal/tax"
+	booksV1 "github.com/iho/bookstore/protos/gen/books/v1"
+	v1 "github.com/iho/bookstore/protos/gen/orders/v1"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// BookStore is the part of the books API pricing needs. The Connect client
+// satisfies it.
+type BookStore interface {
+	GetBook(context.Context, *connect.Request[booksV1.GetBookRequest]) (*connect.Response[booksV1.GetBookResponse], error)
+}
+
+// terms are what an order is priced with besides its lines.
+type terms struct {
+	couponCode string
+	// currency defaults to the currency of the store.
+	currency  string
+	taxRegion string
+}
+
+// quote is an order priced with terms.
+type quote struct {
+	*promotions.Pricing
+	terms
+	tax   int64
+	total int64
+}
+
+func (os *OrdersService) PriceOrder(ctx context.Context, req *connect.Request[v1.PriceOrderRequest]) (*connect.Response[v1.PriceOrderResponse], error) {
+	orderLines, err := newOrderLines(req.Msg.GetOrderLines())
+	if err != nil {
+		return nil, err
+	}
+	shipping, err := newAddress("shipping_address", req.Msg.ShippingAddress)
+	if err != nil {
+		return nil, err
+	}
+	q, err := os.price(ctx, orderLines, terms{
+		couponCode: req.Msg.CouponCode,
+		currency:   req.Msg.Currency,
+		taxRegion:  cmp.Or(req.Msg.TaxRegion, shipping.taxRegion()),
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	order := &Order{OrderLines: orderLines, ShippingAddress: shipping}
+	applyPricing(order, q)
+	msg := os.orderToProto(order)
+	msg.Id = ""
+	msg.Status = v1.OrderStatus_ORDER_STATUS_UNSPECIFIED
+
+	return &connect.Response[v1.PriceOrderResponse]{
+		Msg: &v1.PriceOrderResponse{
+			Order: msg,
+		},
+	}, nil
+}
+
+// newOrderLines validates the lines of a request.
+func newOrderLines(lines []*v1.OrderLine) ([]*OrderLine, error) {
+	if len(lines) == 0 {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("order must have at least one line"))
+	}
+
+	orderLines := make([]*OrderLine, 0, len(lines))
+	for _, line := range lines {
+		if line.Quantity < 1 {
+			return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("quantity must be greater than 0"))
+		}
+
+		if line.BookId == "" {
+			return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("book ID must be provided"))
+		}
+
+		orderLines = append(orderLines, NewOrderLine(line.GetBookId(), line.GetQuantity()))
+	}
+	return orderLines, nil
+}
+
+// price prices lines at the current book prices, converted to the currency
+// of the order, with the promotions of the tenant and the taxes of the
+// region. redeemed are the promotions the order already counts against the
+// usage limits of, as it does once it was created. It keeps them while it is
+// repriced, even once they used up their limit or ended.
+func (os *OrdersService) price(ctx context.Context, lines []*OrderLine, t terms, redeemed []primitive.ObjectID) (*quote, error) {
+	t.couponCode = promotions.NormalizeCode(t.couponCode)
+	t.taxRegion = tax.NormalizeRegion(t.taxRegion)
+	if t.currency == "" {
+		t.currency = os.currency
+	}
+	currency, err := money.ParseCurrency(t.currency)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	t.currency = currency
+
+	books := make(map[string]*booksV1.Book)
+	toPrice := make([]promotions.Line, 0, len(lines))
+	for _, line := range lines {
+		book, ok := books[line.BookId]
+		if !ok {
+			res, err := os.books.GetBook(ctx, connect.NewRequest(&booksV1.GetBookRequest{Id: line.BookId}))
+			if connect.CodeOf(err) == connect.CodeNotFound {
+				return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("%w: [book_id=%s]", ErrUnknownBook, line.BookId))
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to get book: [id=%s] %w", line.BookId, err)
+			}
+			book = res.Msg.Book
+			books[line.BookId] = book
+		}
+		price, err := money.FromProto(book.Price, os.currency)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read book price: [id=%s] %w", line.BookId, err)
+		}
+		if price.Amount <= 0 {
+			return nil, connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("%w: [book_id=%s]", ErrNotForSale, line.BookId))
+		}
+		if price, err = os.convert(price, currency); err != nil {
+			return nil, err
+		}
+
+		toPrice = append(toPrice, promotions.Line{
+			BookID:    line.BookId,
+			AuthorID:  book.AuthorId,
+			Quantity:  line.Quantity,
+			UnitPrice: price.Amount,
+		})
+	}
+
+	candidates, err := os.promotions.Candidates(ctx, t.couponCode)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range candidates {
+		if slices.Contains(redeemed, p.ID) {
+			p.StartsAt, p.EndsAt = nil, nil
+			if p.Redemptions > 0 {
+				p.Redemptions--
+			}
+		}
+		if p.Kind == promotions.KindFixedAmount {
+			amount, err := os.convert(money.New(p.Amount, cmp.Or(p.Currency, os.currency)), currency)
+			if err != nil {
+				return nil, err
+			}
+			p.Amount = amount.Amount
+		}
+	}
+	pricing, err := promotions.Apply(candidates, toPrice, t.couponCode, time.Now())
+	switch {
+	case errors.Is(err, promotions.ErrTotalOutOfBounds):
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	case err != nil:
+		return nil, connect.NewError(connect.CodeFailedPrecondition, err)
+	}
+
+	taxed := money.New(pricing.Total, currency)
+	owed, err := os.taxes.Tax(ctx, t.taxRegion, taxed)
+	switch {
+	case errors.Is(err, tax.ErrUnknownRegion):
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	case errors.Is(err, money.ErrOverflow):
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	case err != nil:
+		return nil, fmt.Errorf("failed to compute tax: %w", err)
+	}
+	total, err := taxed.Add(owed)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	return &quote{Pricing: pricing, terms: t, tax: owed.Amount, total: total.Amount}, nil
+}
+
+// convert converts m to currency with the rates of the service.
+func (os *OrdersService) convert(m money.Money, currency string) (money.Money, error) {
+	converted, err := os.rates.Convert(m, currency)
+	switch {
+	case errors.Is(err, money.ErrNoRate):
+		return money.Money{}, connect.NewError(connect.CodeFailedPrecondition, err)
+	case err != nil:
+		return money.Money{}, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	return converted, nil
+}
+
+// applyPricing sets the prices, discounts and terms of order, whose lines
+// are the ones that were priced.
+func applyPricing(order *Order, q *quote) {
+	for i, line := range q.Lines {
+		orderLine := order.OrderLines[i]
+		orderLine.AuthorID = line.AuthorID
+		orderLine.UnitPrice = line.UnitPrice
+		orderLine.TotalPrice = line.Total
+		orderLine.Discounts = nil
+		for _, d := range line.Discounts {
+			orderLine.Discounts = append(orderLine.Discounts, &Discount{
+				PromotionID: d.Promotion.ID.Hex(),
+				Name:        d.Promotion.Name,
+				Code:        d.Promotion.Code,
+				Amount:      d.Amount,
+			})
+		}
+	}
+	order.SubtotalPrice = q.Subtotal
+	order.DiscountTotal = q.Discount
+	order.TaxTotal = q.tax
+	order.TotalPrice = q.total
+	order.CouponCode = q.couponCode
+	order.Currency = q.currency
+	order.TaxRegion = q.taxRegion
+}
@@ -0,0 +1,586 @@
+package orders
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/iho/bookstore/internal/money"
+	moneyv1 "github.com/iho/bookstore/protos/gen/money/v1"
+	v1 "github.com/iho/bookstore/protos/gen/orders/v1"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	// defaultReportRange is how far reports go back without a from time.
+	defaultReportRange = 30 * 24 * time.Hour
+	defaultReportLimit = 10
+	maxReportLimit     = 100
+	// maxReportPeriods keeps a long range from being split into days.
+	maxReportPeriods = 1000
+)
+
+// soldStatuses are the statuses of orders that were paid and not refunded.
+var soldStatuses = []Status{StatusPaid, StatusPartiallyShipped, StatusShipped, StatusDelivered}
+
+// quantityBuckets are the lower bounds of the buckets of the quantity
+// distribution. The last bucket has no upper bound.
+var quantityBuckets = []int32{1, 2, 3, 4, 5, 6, 11, 21, 51}
+
+// salesFilter is a validated v1.SalesFilter.
+type salesFilter struct {
+	from, to time.Time
+	currency string
+	statuses []Status
+}
+
+func (os *OrdersService) newSalesFilter(msg *v1.SalesFilter) (*salesFilter, error) {
+	to, err := parseTime("to", msg.GetTo())
+	if err != nil {
+		return nil, err
+	}
+	from := to.Add(-defaultReportRange)
+	if msg.GetFrom() != "" {
+		if from, err = parseTime("from", msg.GetFrom()); err != nil {
+			return nil, err
+		}
+	}
+	if !from.Before(to) {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("from must be before to"))
+	}
+	currency, err := money.ParseCurrency(cmp.Or(msg.GetCurrency(), os.currency))
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	f := &salesFilter{from: from, to: to, currency: currency, statuses: soldStatuses}
+	if len(msg.GetStatuses()) > 0 {
+		if f.statuses, err = statusesFromProto(msg.GetStatuses()); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// statusFromProto returns the status of s.
+func statusFromProto(s v1.OrderStatus) (Status, bool) {
+	for status, msg := range orderStatuses {
+		if msg == s {
+			return status, true
+		}
+	}
+	return "", false
+}
+
+// statusesFromProto validates the statuses of a request.
+func statusesFromProto(msgs []v1.OrderStatus) ([]Status, error) {
+	statuses := make([]Status, 0, len(msgs))
+	for _, s := range msgs {
+		status, ok := statusFromProto(s)
+		if !ok {
+			return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("unknown order status %s", s))
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// matchStatuses matches the orders in any of statuses.
+func matchStatuses(statuses []Status) bson.D {
+	values := bson.A{}
+	for _, s := range statuses {
+		values = append(values, s)
+		// orders from before payments have no status
+		if s == StatusPendingPayment {
+			values = append(values, nil)
+		}
+	}
+	return bson.D{{Key: "$in", Value: values}}
+}
+
+// matchCurrency matches the orders in currency.
+func matchCurrency(currency, storeCurrency string) bson.D {
+	values := bson.A{currency}
+	// orders from before currencies are in the currency of the store
+	if currency == storeCurrency {
+		values = append(values, nil)
+	}
+	return bson.D{{Key: "$in", Value: values}}
+}
+
+// stages returns the stages selecting the orders of f. The collection must
+// come from indexedOrders, which dates the orders stored before ordered_at
+// existed.
+func (f *salesFilter) stages(storeCurrency string) mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{
+			{Key: "status", Value: matchStatuses(f.statuses)},
+			{Key: "currency", Value: matchCurrency(f.currency, storeCurrency)},
+			{Key: "ordered_at", Value: bson.D{{Key: "$gte", Value: f.from}, {Key: "$lt", Value: f.to}}},
+		}}},
+	}
+}
+
+// aggregate runs pipeline over the orders of the tenant of ctx.
+func aggregate[T any](ctx context.Context, coll *mongo.Collection, pipeline mongo.Pipeline) ([]T, error) {
+	cursor, err := coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to aggregate orders: %w", err))
+	}
+	var results []T
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to read aggregation: %w", err))
+	}
+	return results, nil
+}
+
+func reportLimit(limit int32) (int64, error) {
+	switch {
+	case limit == 0:
+		return defaultReportLimit, nil
+	case limit < 0 || limit > maxReportLimit:
+		return 0, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("limit must be between 1 and %d", maxReportLimit))
+	}
+	return int64(limit), nil
+}
+
+func (os *OrdersService) ListBestSellingBooks(ctx context.Context, req *connect.Request[v1.ListBestSellingBooksRequest]) (*connect.Response[v1.ListBestSellingBooksResponse], error) {
+	f, err := os.newSalesFilter(req.Msg.Filter)
+	if err != nil {
+		return nil, err
+	}
+	books, err := os.bestSellingBooks(ctx, f, req.Msg.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &connect.Response[v1.ListBestSellingBooksResponse]{
+		Msg: &v1.ListBestSellingBooksResponse{
+			Books: books,
+		},
+	}, nil
+}
+
+func (os *OrdersService) bestSellingBooks(ctx context.Context, f *salesFilter, limit int32) ([]*v1.BookSales, error) {
+	n, err := reportLimit(limit)
+	if err != nil {
+		return nil, err
+	}
+	coll, err := os.indexedOrders(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pipeline := append(f.stages(os.currency),
+		bson.D{{Key: "$unwind", Value: "$order_lines"}},
+		bson.D{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$order_lines.book_id"},
+			{Key: "quantity", Value: bson.D{{Key: "$sum", Value: "$order_lines.quantity"}}},
+			{Key: "revenue", Value: bson.D{{Key: "$sum", Value: "$order_lines.total_price"}}},
+			{Key: "orders", Value: bson.D{{Key: "$addToSet", Value: "$_id"}}},
+		}}},
+		bson.D{{Key: "$set", Value: bson.D{{Key: "orders", Value: bson.D{{Key: "$size", Value: "$orders"}}}}}},
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "quantity", Value: -1}, {Key: "revenue", Value: -1}, {Key: "_id", Value: 1}}}},
+		bson.D{{Key: "$limit", Value: n}},
+	)
+	rows, err := aggregate[struct {
+		BookID   string `bson:"_id"`
+		Quantity int64  `bson:"quantity"`
+		Orders   int64  `bson:"orders"`
+		Revenue  int64  `bson:"revenue"`
+	}](ctx, coll, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	books := make([]*v1.BookSales, 0, len(rows))
+	for _, row := range rows {
+		books = append(books, &v1.BookSales{
+			BookId:   row.BookID,
+			Quantity: row.Quantity,
+			Orders:   row.Orders,
+			Revenue:  money.New(row.Revenue, f.currency).Proto(),
+		})
+	}
+	return books, nil
+}
+
+func (os *OrdersService) ListBestSellingAuthors(ctx context.Context, req *connect.Request[v1.ListBestSellingAuthorsRequest]) (*connect.Response[v1.ListBestSellingAuthorsResponse], error) {
+	f, err := os.newSalesFilter(req.Msg.Filter)
+	if err != nil {
+		return nil, err
+	}
+	authors, err := os.bestSellingAuthors(ctx, f, req.Msg.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &connect.Response[v1.ListBestSellingAuthorsResponse]{
+		Msg: &v1.ListBestSellingAuthorsResponse{
+			Authors: authors,
+		},
+	}, nil
+}
+
+// bestSellingAuthors leaves out the lines of orders placed before authors
+// were recorded on them.
+func (os *OrdersService) bestSellingAuthors(ctx context.Context, f *salesFilter, limit int32) ([]*v1.AuthorSales, error) {
+	n, err := reportLimit(limit)
+	if err != nil {
+		return nil, err
+	}
+	coll, err := os.indexedOrders(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pipeline := append(f.stages(os.currency),
+		bson.D{{Key: "$unwind", Value: "$order_lines"}},
+		bson.D{{Key: "$match", Value: bson.D{{Key: "order_lines.author_id", Value: bson.D{{Key: "$nin", Value: bson.A{nil, ""}}}}}}},
+		bson.D{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$order_lines.author_id"},
+			{Key: "quantity", Value: bson.D{{Key: "$sum", Value: "$order_lines.quantity"}}},
+			{Key: "revenue", Value: bson.D{{Key: "$sum", Value: "$order_lines.total_price"}}},
+			{Key: "orders", Value: bson.D{{Key: "$addToSet", Value: "$_id"}}},
+			{Key: "books", Value: bson.D{{Key: "$addToSet", Value: "$order_lines.book_id"}}},
+		}}},
+		bson.D{{Key: "$set", Value: bson.D{
+			{Key: "orders", Value: bson.D{{Key: "$size", Value: "$orders"}}},
+			{Key: "books", Value: bson.D{{Key: "$size", Value: "$books"}}},
+		}}},
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "quantity", Value: -1}, {Key: "revenue", Value: -1}, {Key: "_id", Value: 1}}}},
+		bson.D{{Key: "$limit", Value: n}},
+	)
+	rows, err := aggregate[struct {
+		AuthorID string `bson:"_id"`
+		Quantity int64  `bson:"quantity"`
+		Orders   int64  `bson:"orders"`
+		Revenue  int64  `bson:"revenue"`
+		Books    int64  `bson:"books"`
+	}](ctx, coll, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	authors := make([]*v1.AuthorSales, 0, len(rows))
+	for _, row := range rows {
+		authors = append(authors, &v1.AuthorSales{
+			AuthorId: row.AuthorID,
+			Quantity: row.Quantity,
+			Orders:   row.Orders,
+			Revenue:  money.New(row.Revenue, f.currency).Proto(),
+			Books:    row.Books,
+		})
+	}
+	return authors, nil
+}
+
+func (os *OrdersService) GetRevenue(ctx context.Context, req *connect.Request[v1.GetRevenueRequest]) (*connect.Response[v1.GetRevenueResponse], error) {
+	f, err := os.newSalesFilter(req.Msg.Filter)
+	if err != nil {
+		return nil, err
+	}
+	periods, err := os.revenue(ctx, f, req.Msg.Interval)
+	if err != nil {
+		return nil, err
+	}
+
+	var total int64
+	for _, p := range periods {
+		total += p.TotalPrice.Amount
+	}
+	return &connect.Response[v1.GetRevenueResponse]{
+		Msg: &v1.GetRevenueResponse{
+			Periods:    periods,
+			TotalPrice: money.New(total, f.currency).Proto(),
+		},
+	}, nil
+}
+
+// periodUnits are the $dateTrunc units of the intervals.
+var periodUnits = map[v1.ReportInterval]string{
+	v1.ReportInterval_REPORT_INTERVAL_DAY:   "day",
+	v1.ReportInterval_REPORT_INTERVAL_WEEK:  "week",
+	v1.ReportInterval_REPORT_INTERVAL_MONTH: "month",
+}
+
+// periodStart truncates t like $dateTrunc does in UTC.
+func periodStart(t time.Time, interval v1.ReportInterval) time.Time {
+	y, m, d := t.UTC().Date()
+	switch interval {
+	case v1.ReportInterval_REPORT_INTERVAL_WEEK:
+		day := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case v1.ReportInterval_REPORT_INTERVAL_MONTH:
+		return time.Date(y, m, 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+}
+
+func nextPeriod(start time.Time, interval v1.ReportInterval) time.Time {
+	switch interval {
+	case v1.ReportInterval_REPORT_INTERVAL_WEEK:
+		return start.AddDate(0, 0, 7)
+	case v1.ReportInterval_REPORT_INTERVAL_MONTH:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// revenue returns the periods of interval the range of f falls in, the
+// first and last period may start before and end after it.
+func (os *OrdersService) revenue(ctx context.Context, f *salesFilter, interval v1.ReportInterval) ([]*v1.RevenuePeriod, error) {
+	if interval == v1.ReportInterval_REPORT_INTERVAL_UNSPECIFIED {
+		interval = v1.ReportInterval_REPORT_INTERVAL_DAY
+	}
+	unit, ok := periodUnits[interval]
+	if !ok {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("unknown interval %s", interval))
+	}
+	var starts []time.Time
+	for start := periodStart(f.from, interval); start.Before(f.to); start = nextPeriod(start, interval) {
+		if len(starts) == maxReportPeriods {
+			return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("range has more than %d periods, use a longer interval", maxReportPeriods))
+		}
+		starts = append(starts, start)
+	}
+	coll, err := os.indexedOrders(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	trunc := bson.D{
+		{Key: "date", Value: "$ordered_at"},
+		{Key: "unit", Value: unit},
+	}
+	if interval == v1.ReportInterval_REPORT_INTERVAL_WEEK {
+		trunc = append(trunc, bson.E{Key: "startOfWeek", Value: "monday"})
+	}
+	pipeline := append(f.stages(os.currency),
+		bson.D{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: bson.D{{Key: "$dateTrunc", Value: trunc}}},
+			{Key: "orders", Value: bson.D{{Key: "$sum", Value: 1}}},
+			{Key: "subtotal_price", Value: bson.D{{Key: "$sum", Value: "$subtotal_price"}}},
+			{Key: "discount_total", Value: bson.D{{Key: "$sum", Value: "$discount_total"}}},
+			{Key: "tax_total", Value: bson.D{{Key: "$sum", Value: "$tax_total"}}},
+			{Key: "total_price", Value: bson.D{{Key: "$sum", Value: "$total_price"}}},
+		}}},
+	)
+	type period struct {
+		Start         time.Time `bson:"_id"`
+		Orders        int64     `bson:"orders"`
+		SubtotalPrice int64     `bson:"subtotal_price"`
+		DiscountTotal int64     `bson:"discount_total"`
+		TaxTotal      int64     `bson:"tax_total"`
+		TotalPrice    int64     `bson:"total_price"`
+	}
+	rows, err := aggregate[period](ctx, coll, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	byStart := make(map[time.Time]period, len(rows))
+	for _, row := range rows {
+		byStart[row.Start.UTC()] = row
+	}
+
+	periods := make([]*v1.RevenuePeriod, 0, len(starts))
+	for _, start := range starts {
+		row := byStart[start]
+		periods = append(periods, &v1.RevenuePeriod{
+			Start:         start.Format(time.RFC3339),
+			Orders:        row.Orders,
+			SubtotalPrice: money.New(row.SubtotalPrice, f.currency).Proto(),
+			DiscountTotal: money.New(row.DiscountTotal, f.currency).Proto(),
+			TaxTotal:      money.New(row.TaxTotal, f.currency).Proto(),
+			TotalPrice:    money.New(row.TotalPrice, f.currency).Proto(),
+		})
+	}
+	return periods, nil
+}
+
+func (os *OrdersService) GetOrderStats(ctx context.Context, req *connect.Request[v1.GetOrderStatsRequest]) (*connect.Response[v1.GetOrderStatsResponse], error) {
+	f, err := os.newSalesFilter(req.Msg.Filter)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := os.orderStats(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+
+	return &connect.Response[v1.GetOrderStatsResponse]{
+		Msg: stats,
+	}, nil
+}
+
+func (os *OrdersService) orderStats(ctx context.Context, f *salesFilter) (*v1.GetOrderStatsResponse, error) {
+	coll, err := os.indexedOrders(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	boundaries := bson.A{}
+	for _, b := range quantityBuckets {
+		boundaries = append(boundaries, b)
+	}
+	last := quantityBuckets[len(quantityBuckets)-1]
+	pipeline := append(f.stages(os.currency),
+		bson.D{{Key: "$set", Value: bson.D{{Key: "quantity", Value: bson.D{{Key: "$sum", Value: "$order_lines.quantity"}}}}}},
+		bson.D{{Key: "$facet", Value: bson.D{
+			{Key: "summary", Value: bson.A{
+				bson.D{{Key: "$group", Value: bson.D{
+					{Key: "_id", Value: nil},
+					{Key: "orders", Value: bson.D{{Key: "$sum", Value: 1}}},
+					{Key: "total_price", Value: bson.D{{Key: "$sum", Value: "$total_price"}}},
+					{Key: "quantity", Value: bson.D{{Key: "$sum", Value: "$quantity"}}},
+				}}},
+			}},
+			{Key: "distribution", Value: bson.A{
+				bson.D{{Key: "$bucket", Value: bson.D{
+					{Key: "groupBy", Value: "$quantity"},
+					{Key: "boundaries", Value: boundaries},
+					// orders with more books than the last boundary
+					{Key: "default", Value: last},
+					{Key: "output", Value: bson.D{{Key: "orders", Value: bson.D{{Key: "$sum", Value: 1}}}}},
+				}}},
+			}},
+		}}},
+	)
+	results, err := aggregate[struct {
+		Summary []struct {
+			Orders     int64 `bson:"orders"`
+			TotalPrice int64 `bson:"total_price"`
+			Quantity   int64 `bson:"quantity"`
+		} `bson:"summary"`
+		Distribution []struct {
+			Min    int32 `bson:"_id"`
+			Orders int64 `bson:"orders"`
+		} `bson:"distribution"`
+	}](ctx, coll, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &v1.GetOrderStatsResponse{
+		TotalPrice:        money.New(0, f.currency).Proto(),
+		AverageOrderValue: money.New(0, f.currency).Proto(),
+	}
+	counts := make(map[int32]int64)
+	if len(results) > 0 {
+		if summary := results[0].Summary; len(summary) > 0 && summary[0].Orders > 0 {
+			s := summary[0]
+			total := money.New(s.TotalPrice, f.currency)
+			average, err := total.Scale(big.NewRat(1, s.Orders))
+			if err != nil {
+				return nil, connect.NewError(connect.CodeInternal, err)
+			}
+			stats.Orders = s.Orders
+			stats.TotalPrice = total.Proto()
+			stats.AverageOrderValue = average.Proto()
+			stats.Quantity = s.Quantity
+			stats.AverageQuantity = float64(s.Quantity) / float64(s.Orders)
+		}
+		for _, bucket := range results[0].Distribution {
+			counts[bucket.Min] = bucket.Orders
+		}
+	}
+	for i, lower := range quantityBuckets {
+		var upper int32
+		if i+1 < len(quantityBuckets) {
+			upper = quantityBuckets[i+1] - 1
+		}
+		stats.QuantityDistribution = append(stats.QuantityDistribution, &v1.QuantityBucket{
+			MinQuantity: lower,
+			MaxQuantity: upper,
+			Orders:      counts[lower],
+		})
+	}
+	return stats, nil
+}
+
+// ExportSalesReport renders the same rows as the report RPCs.
+func (os *OrdersService) ExportSalesReport(ctx context.Context, req *connect.Request[v1.ExportSalesReportRequest]) (*connect.Response[v1.ExportSalesReportResponse], error) {
+	f, err := os.newSalesFilter(req.Msg.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var records [][]string
+	switch req.Msg.Report {
+	case v1.SalesReport_SALES_REPORT_BEST_SELLING_BOOKS:
+		books, err := os.bestSellingBooks(ctx, f, req.Msg.Limit)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, []string{"book_id", "quantity", "orders", "revenue", "currency"})
+		for _, b := range books {
+			records = append(records, []string{b.BookId, itoa(b.Quantity), itoa(b.Orders), decimal(b.Revenue), f.currency})
+		}
+	case v1.SalesReport_SALES_REPORT_BEST_SELLING_AUTHORS:
+		authors, err := os.bestSellingAuthors(ctx, f, req.Msg.Limit)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, []string{"author_id", "quantity", "orders", "books", "revenue", "currency"})
+		for _, a := range authors {
+			records = append(records, []string{a.AuthorId, itoa(a.Quantity), itoa(a.Orders), itoa(a.Books), decimal(a.Revenue), f.currency})
+		}
+	case v1.SalesReport_SALES_REPORT_REVENUE:
+		periods, err := os.revenue(ctx, f, req.Msg.Interval)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, []string{"period_start", "orders", "subtotal_price", "discount_total", "tax_total", "total_price", "currency"})
+		for _, p := range periods {
+			records = append(records, []string{p.Start, itoa(p.Orders), decimal(p.SubtotalPrice), decimal(p.DiscountTotal), decimal(p.TaxTotal), decimal(p.TotalPrice), f.currency})
+		}
+	case v1.SalesReport_SALES_REPORT_QUANTITY_DISTRIBUTION:
+		stats, err := os.orderStats(ctx, f)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, []string{"min_quantity", "max_quantity", "orders"})
+		for _, b := range stats.QuantityDistribution {
+			upper := ""
+			if b.MaxQuantity > 0 {
+				upper = itoa(int64(b.MaxQuantity))
+			}
+			records = append(records, []string{itoa(int64(b.MinQuantity)), upper, itoa(b.Orders)})
+		}
+	default:
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("report must be provided"))
+	}
+
+	var buf bytes.Buffer
+	if err := csv.NewWriter(&buf).WriteAll(records); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to write csv: %w", err))
+	}
+	name := strings.ToLower(strings.TrimPrefix(req.Msg.Report.String(), "SALES_REPORT_"))
+
+	return &connect.Response[v1.ExportSalesReportResponse]{
+		Msg: &v1.ExportSalesReportResponse{
+			Filename: fmt.Sprintf("%s_%s_%s.csv", name, f.from.Format(time.DateOnly), f.to.Format(time.DateOnly)),
+			Csv:      buf.Bytes(),
+		},
+	}, nil
+}
+
+func itoa(n int64) string {
+	return strconv.FormatInt(n, 10)
+}
+
+// decimal formats m in major units without its currency.
+func decimal(m *moneyv1.Money) string {
+	return money.New(m.GetAmount(), m.GetCurrency()).Decimal()
+}
@@ -0,0 +1,302 @@
+package orders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/iho/bookstore/internal/idempotency"
+	"github.com/iho/bookstore/internal/payments"
+	eventsv1 "github.com/iho/bookstore/protos/gen/events/v1"
+	v1 "github.com/iho/bookstore/protos/gen/orders/v1"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// shippable are the statuses shipments can be created in.
+var shippable = []Status{StatusPaid, StatusPartiallyShipped}
+
+// CreateShipment creates at most one shipment per Idempotency-Key header.
+func (os *OrdersService) CreateShipment(ctx context.Context, req *connect.Request[v1.CreateShipmentRequest]) (*connect.Response[v1.CreateShipmentResponse], error) {
+	return idempotency.Handle(ctx, os.idempotency, req, os.createShipment)
+}
+
+func (os *OrdersService) createShipment(ctx context.Context, req *connect.Request[v1.CreateShipmentRequest]) (*connect.Response[v1.CreateShipmentResponse], error) {
+	if req.Msg.OrderId == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("order ID must be provided"))
+	}
+	id, err := primitive.ObjectIDFromHex(req.Msg.OrderId)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	carrier := strings.TrimSpace(req.Msg.Carrier)
+	if carrier == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("carrier must be provided"))
+	}
+	shippedAt, err := parseTime("shipped_at", req.Msg.ShippedAt)
+	if err != nil {
+		return nil, err
+	}
+	lines, err := newShipmentLines(req.Msg.GetLines())
+	if err != nil {
+		return nil, err
+	}
+	coll, err := os.orders(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var order *Order
+	var shipment *Shipment
+	err = os.inTx(ctx, func(sc mongo.SessionContext) error {
+		var err error
+		if order, err = findOrder(sc, coll, id); err != nil {
+			return err
+		}
+		previous := order.status()
+		if !slices.Contains(shippable, previous) {
+			return connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("%w: [status=%s]", ErrNotShippable, previous))
+		}
+		if order.ShippingAddress == nil {
+			return connect.NewError(connect.CodeFailedPrecondition, ErrNoShippingAddress)
+		}
+		// the order may be refunded any moment
+		if order.lastPayment(payments.OperationRefund, PaymentPending) != nil {
+			return connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("%w: [operation=%s]", ErrPaymentPending, payments.OperationRefund))
+		}
+
+		shipped, err := order.toShip(lines)
+		if err != nil {
+			return err
+		}
+		shipment = &Shipment{
+			ID:             primitive.NewObjectID().Hex(),
+			Carrier:        carrier,
+			TrackingNumber: strings.TrimSpace(req.Msg.TrackingNumber),
+			Lines:          shipped,
+			ShippedAt:      shippedAt,
+		}
+		order.Shipments = append(order.Shipments, shipment)
+		order.Status = order.fulfillmentStatus()
+
+		update := bson.D{
+			{Key: "$set", Value: bson.D{{Key: "status", Value: order.Status}}},
+			{Key: "$push", Value: bson.D{{Key: "shipments", Value: shipment}}},
+		}
+		if _, err := coll.UpdateByID(sc, id, update); err != nil {
+			return err
+		}
+
+		eventLines := make([]*eventsv1.ShipmentLine, 0, len(shipment.Lines))
+		for _, line := range shipment.Lines {
+			eventLines = append(eventLines, &eventsv1.ShipmentLine{
+				BookId:   line.BookID,
+				Quantity: line.Quantity,
+			})
+		}
+		err = os.addEvent(sc, id.Hex(), &eventsv1.ShipmentCreated{
+			OrderId:        id.Hex(),
+			ShipmentId:     shipment.ID,
+			Carrier:        shipment.Carrier,
+			TrackingNumber: shipment.TrackingNumber,
+			Lines:          eventLines,
+			ShippedAt:      timestamppb.New(shipment.ShippedAt),
+		})
+		if err != nil {
+			return err
+		}
+		return os.addStatusChanged(sc, id, previous, order.Status)
+	})
+	if err != nil {
+		return nil, connectError(err)
+	}
+
+	shipmentsCreated.Inc()
+	return &connect.Response[v1.CreateShipmentResponse]{
+		Msg: &v1.CreateShipmentResponse{
+			Order:    os.orderToProto(order),
+			Shipment: shipmentToProto(shipment),
+		},
+	}, nil
+}
+
+func (os *OrdersService) DeliverShipment(ctx context.Context, req *connect.Request[v1.DeliverShipmentRequest]) (*connect.Response[v1.DeliverShipmentResponse], error) {
+	if req.Msg.OrderId == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("order ID must be provided"))
+	}
+	id, err := primitive.ObjectIDFromHex(req.Msg.OrderId)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	if req.Msg.ShipmentId == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("shipment ID must be provided"))
+	}
+	deliveredAt, err := parseTime("delivered_at", req.Msg.DeliveredAt)
+	if err != nil {
+		return nil, err
+	}
+	coll, err := os.orders(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var order *Order
+	var delivered bool
+	err = os.inTx(ctx, func(sc mongo.SessionContext) error {
+		var err error
+		delivered = false
+		if order, err = findOrder(sc, coll, id); err != nil {
+			return err
+		}
+		shipment := order.shipment(req.Msg.ShipmentId)
+		if shipment == nil {
+			return connect.NewError(connect.CodeNotFound, fmt.Errorf("%w: [id=%s]", ErrShipmentNotFound, req.Msg.ShipmentId))
+		}
+		if shipment.DeliveredAt != nil {
+			return nil
+		}
+		if deliveredAt.Before(shipment.ShippedAt) {
+			return connect.NewError(connect.CodeInvalidArgument, errors.New("delivered_at must not be before shipped_at"))
+		}
+
+		shipment.DeliveredAt = &deliveredAt
+		previous := order.status()
+		order.Status = order.fulfillmentStatus()
+
+		update := bson.D{{Key: "$set", Value: bson.D{
+			{Key: "status", Value: order.Status},
+			{Key: "shipments", Value: order.Shipments},
+		}}}
+		if _, err := coll.UpdateByID(sc, id, update); err != nil {
+			return err
+		}
+		delivered = true
+
+		err = os.addEvent(sc, id.Hex(), &eventsv1.ShipmentDelivered{
+			OrderId:     id.Hex(),
+			ShipmentId:  shipment.ID,
+			DeliveredAt: timestamppb.New(deliveredAt),
+		})
+		if err != nil {
+			return err
+		}
+		return os.addStatusChanged(sc, id, previous, order.Status)
+	})
+	if err != nil {
+		return nil, connectError(err)
+	}
+
+	if delivered {
+		shipmentsDelivered.Inc()
+	}
+	return &connect.Response[v1.DeliverShipmentResponse]{
+		Msg: &v1.DeliverShipmentResponse{
+			Order: os.orderToProto(order),
+		},
+	}, nil
+}
+
+// addStatusChanged writes an OrderStatusChanged event to the outbox unless
+// the status stayed the same.
+func (os *OrdersService) addStatusChanged(ctx context.Context, id primitive.ObjectID, previous, status Status) error {
+	if previous == status {
+		return nil
+	}
+	return os.addEvent(ctx, id.Hex(), &eventsv1.OrderStatusChanged{
+		OrderId:        id.Hex(),
+		PreviousStatus: string(previous),
+		Status:         string(status),
+	})
+}
+
+// newShipmentLines validates the lines of a request, merging the lines of
+// the same book.
+func newShipmentLines(lines []*v1.ShipmentLine) ([]*ShipmentLine, error) {
+	shipmentLines := make([]*ShipmentLine, 0, len(lines))
+	byBook := make(map[string]*ShipmentLine, len(lines))
+	for _, line := range lines {
+		if line.Quantity < 1 {
+			return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("quantity must be greater than 0"))
+		}
+		if line.BookId == "" {
+			return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("book ID must be provided"))
+		}
+
+		if merged, ok := byBook[line.BookId]; ok {
+			if merged.Quantity > math.MaxInt32-line.Quantity {
+				return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("quantity is too large"))
+			}
+			merged.Quantity += line.Quantity
+			continue
+		}
+		shipmentLine := &ShipmentLine{BookID: line.BookId, Quantity: line.Quantity}
+		byBook[line.BookId] = shipmentLine
+		shipmentLines = append(shipmentLines, shipmentLine)
+	}
+	return shipmentLines, nil
+}
+
+// toShip checks that lines are left to ship in the order. No lines ship
+// everything that is left, in the order of the order lines.
+func (o *Order) toShip(lines []*ShipmentLine) ([]*ShipmentLine, error) {
+	unshipped := o.unshipped()
+	if len(lines) > 0 {
+		for _, line := range lines {
+			if line.Quantity > unshipped[line.BookID] {
+				return nil, connect.NewError(connect.CodeFailedPrecondition, fmt.Errorf("%w: [book_id=%s] [unshipped=%d]", ErrAlreadyShipped, line.BookID, unshipped[line.BookID]))
+			}
+		}
+		return lines, nil
+	}
+
+	rest := make([]*ShipmentLine, 0, len(unshipped))
+	for _, line := range o.OrderLines {
+		if quantity, ok := unshipped[line.BookId]; ok {
+			rest = append(rest, &ShipmentLine{BookID: line.BookId, Quantity: quantity})
+			delete(unshipped, line.BookId)
+		}
+	}
+	return rest, nil
+}
+
+// parseTime parses the RFC 3339 time in field of a request, an empty value
+// is now.
+func parseTime(field, value string) (time.Time, error) {
+	if value == "" {
+		return time.Now().UTC(), nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("invalid %s: %w", field, err))
+	}
+	return t.UTC(), nil
+}
+
+func shipmentToProto(s *Shipment) *v1.Shipment {
+	lines := make([]*v1.ShipmentLine, 0, len(s.Lines))
+	for _, line := range s.Lines {
+		lines = append(lines, &v1.ShipmentLine{
+			BookId:   line.BookID,
+			Quantity: line.Quantity,
+		})
+	}
+
+	msg := &v1.Shipment{
+		Id:             s.ID,
+		Carrier:        s.Carrier,
+		TrackingNumber: s.TrackingNumber,
+		Lines:          lines,
+		ShippedAt:      s.ShippedAt.UTC().Format(time.RFC3339),
+	}
+	if s.DeliveredAt != nil {
+		msg.DeliveredAt = s.DeliveredAt.UTC().Format(time.RFC3339)
+	}
+	return msg
+}
@@ -0,0 +1,171 @@
+package payments
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// Outcome is what the fake provider does with new calls.
+type Outcome string
+
+const (
+	// OutcomeSucceed accepts every call.
+	OutcomeSucceed Outcome = "succeed"
+	// OutcomeDecline declines every call.
+	OutcomeDecline Outcome = "decline"
+	// OutcomeTimeout accepts every call but fails it with ErrTimeout, as if
+	// the answer was lost. Retries with the same key and webhooks report
+	// the success.
+	OutcomeTimeout Outcome = "timeout"
+)
+
+// Fake is an in-memory provider for development. It is deterministic:
+// references derive from idempotency keys and a key always gets the result
+// of its first call.
+type Fake struct {
+	outcome  Outcome
+	webhooks *WebhookClient
+
+	mu      sync.Mutex
+	results map[string]fakeResult
+}
+
+type fakeResult struct {
+	reference string
+	reason    string
+}
+
+// NewFake creates a fake provider. Every new call is reported to webhooks
+// unless it is nil.
+func NewFake(outcome Outcome, webhooks *WebhookClient) (*Fake, error) {
+	switch outcome {
+	case OutcomeSucceed, OutcomeDecline, OutcomeTimeout:
+	default:
+		return nil, fmt.Errorf("payments: unknown fake outcome %q", outcome)
+	}
+	return &Fake{
+		outcome:  outcome,
+		webhooks: webhooks,
+		results:  make(map[string]fakeResult),
+	}, nil
+}
+
+// NewFakeFromEnv creates a fake provider with the outcome in
+// PAYMENTS_FAKE_OUTCOME, "succeed" by default. Webhooks are sent to
+// PAYMENTS_FAKE_WEBHOOK_URL, if set, signed with PAYMENTS_WEBHOOK_SECRET.
+func NewFakeFromEnv() (*Fake, error) {
+	outcome := Outcome(os.Getenv("PAYMENTS_FAKE_OUTCOME"))
+	if outcome == "" {
+		outcome = OutcomeSucceed
+	}
+
+	var webhooks *WebhookClient
+	if url := os.Getenv("PAYMENTS_FAKE_WEBHOOK_URL"); url != "" {
+		secret := os.Getenv("PAYMENTS_WEBHOOK_SECRET")
+		if secret == "" {
+			return nil, fmt.Errorf("PAYMENTS_FAKE_WEBHOOK_URL needs PAYMENTS_WEBHOOK_SECRET")
+		}
+		webhooks = NewWebhookClient(url, []byte(secret))
+	}
+	return NewFake(outcome, webhooks)
+}
+
+func (f *Fake) Authorize(ctx context.Context, req Request) (*Result, error) {
+	return f.call(ctx, OperationAuthorize, req)
+}
+
+func (f *Fake) Capture(ctx context.Context, req Request) (*Result, error) {
+	return f.call(ctx, OperationCapture, req)
+}
+
+func (f *Fake) Refund(ctx context.Context, req Request) (*Result, error) {
+	return f.call(ctx, OperationRefund, req)
+}
+
+func (f *Fake) Void(ctx context.Context, req Request) (*Result, error) {
+	return f.call(ctx, OperationVoid, req)
+}
+
+func (f *Fake) call(ctx context.Context, op Operation, req Request) (*Result, error) {
+	if req.IdempotencyKey == "" {
+		return nil, fmt.Errorf("payments: missing idempotency key")
+	}
+
+	f.mu.Lock()
+	res, replayed := f.results[req.IdempotencyKey]
+	if !replayed {
+		res = f.decide(op, req)
+		f.results[req.IdempotencyKey] = res
+	}
+	f.mu.Unlock()
+
+	if !replayed {
+		f.notify(ctx, op, req, res)
+		if f.outcome == OutcomeTimeout {
+			return nil, fmt.Errorf("%w: [operation=%s]", ErrTimeout, op)
+		}
+	}
+	if res.reason != "" {
+		return nil, fmt.Errorf("%w: %s", ErrDeclined, res.reason)
+	}
+	return &Result{Reference: res.reference}, nil
+}
+
+func (f *Fake) decide(op Operation, req Request) fakeResult {
+	switch {
+	case f.outcome == OutcomeDecline:
+		return fakeResult{reason: "declined by the fake provider"}
+	case op == OperationAuthorize && req.Amount.Amount <= 0:
+		return fakeResult{reason: "amount must be greater than 0"}
+	case op != OperationAuthorize && req.Reference == "":
+		return fakeResult{reason: "missing reference"}
+	}
+
+	sum := sha256.Sum256([]byte(req.IdempotencyKey))
+	return fakeResult{reference: "fake_" + string(op) + "_" + hex.EncodeToString(sum[:8])}
+}
+
+// notify reports a new call by webhook in the background, retrying a few
+// times like a real provider would.
+func (f *Fake) notify(ctx context.Context, op Operation, req Request, res fakeResult) {
+	if f.webhooks == nil {
+		return
+	}
+
+	event := Event{
+		ID:             "evt_" + req.IdempotencyKey,
+		Operation:      op,
+		Status:         StatusSucceeded,
+		IdempotencyKey: req.IdempotencyKey,
+		Reference:      res.reference,
+		Reason:         res.reason,
+		Amount:         req.Amount,
+		Metadata:       req.Metadata,
+	}
+	if res.reason != "" {
+		event.Status = StatusDeclined
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		backoff := time.Second
+		for attempt := 1; ; attempt++ {
+			err := f.webhooks.Send(ctx, event)
+			if err == nil {
+				return
+			}
+			if attempt == 5 {
+				slog.ErrorContext(ctx, "failed to deliver payment webhook", "event_id", event.ID, "error", err)
+				return
+			}
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+	}()
+}
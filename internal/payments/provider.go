@@ -0,0 +1,87 @@
+// Package payments talks to payment providers. Orders authorize the total
+// price of an order, capture it, and refund or void it later. Providers
+// report outcomes they could not return in time through signed webhooks.
+package payments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/iho/bookstore/internal/money"
+)
+
+var (
+	ErrDeclined         = errors.New("payments: payment declined")
+	ErrTimeout          = errors.New("payments: provider timed out")
+	ErrUnknownProvider  = errors.New("payments: unknown provider")
+	ErrInvalidSignature = errors.New("payments: invalid webhook signature")
+)
+
+// Operation is a call made to a provider.
+type Operation string
+
+const (
+	OperationAuthorize Operation = "authorize"
+	OperationCapture   Operation = "capture"
+	OperationRefund    Operation = "refund"
+	OperationVoid      Operation = "void"
+)
+
+// Request is the input of every provider call.
+type Request struct {
+	// IdempotencyKey identifies the call. Providers return the result of
+	// the first call for repeated keys, so a call whose outcome is unknown
+	// is retried with the same key.
+	IdempotencyKey string
+	// Reference is the provider's ID of the payment to act on. It is empty
+	// for authorizations.
+	Reference string
+	Amount    money.Money
+	// Metadata is passed back in webhooks about the call.
+	Metadata map[string]string
+}
+
+// Result is the outcome of a successful call.
+type Result struct {
+	// Reference is the provider's ID of the payment, used by later calls.
+	Reference string
+}
+
+// PaymentProvider moves money. Calls fail with ErrDeclined when the provider
+// refused them. Any other error, ErrTimeout included, leaves the outcome
+// unknown until a retry with the same key or a webhook settles it.
+type PaymentProvider interface {
+	Authorize(ctx context.Context, req Request) (*Result, error)
+	Capture(ctx context.Context, req Request) (*Result, error)
+	Refund(ctx context.Context, req Request) (*Result, error)
+	Void(ctx context.Context, req Request) (*Result, error)
+}
+
+// Call runs operation op on p.
+func Call(ctx context.Context, p PaymentProvider, op Operation, req Request) (*Result, error) {
+	switch op {
+	case OperationAuthorize:
+		return p.Authorize(ctx, req)
+	case OperationCapture:
+		return p.Capture(ctx, req)
+	case OperationRefund:
+		return p.Refund(ctx, req)
+	case OperationVoid:
+		return p.Void(ctx, req)
+	default:
+		return nil, fmt.Errorf("payments: unknown operation %q", op)
+	}
+}
+
+// FromEnv returns the provider picked by PAYMENTS_PROVIDER. "fake", the
+// default, is the only one so far; see NewFakeFromEnv.
+func FromEnv() (PaymentProvider, error) {
+	switch kind := os.Getenv("PAYMENTS_PROVIDER"); kind {
+	case "", "fake":
+		return NewFakeFromEnv()
+	default:
+		return nil, fmt.Errorf("%w: [provider=%s]", ErrUnknownProvider, kind)
+	}
+}
@@ -0,0 +1,175 @@
+package payments
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iho/bookstore/internal/money"
+)
+
+const (
+	// SignatureHeader carries "t=<unix time>,v1=<hex HMAC-SHA256>" of a
+	// webhook, the HMAC being over "<unix time>.<body>".
+	SignatureHeader = "Payment-Signature"
+	// SignatureTolerance is how old a webhook may be before it is treated
+	// as a replay.
+	SignatureTolerance = 5 * time.Minute
+
+	maxWebhookSize = 64 << 10
+)
+
+// Status is the outcome of a call reported by a webhook.
+type Status string
+
+const (
+	StatusSucceeded Status = "succeeded"
+	StatusDeclined  Status = "declined"
+)
+
+// Event is the body of a webhook. Providers may deliver an event several
+// times, handlers must be idempotent.
+type Event struct {
+	ID             string            `json:"id"`
+	Operation      Operation         `json:"operation"`
+	Status         Status            `json:"status"`
+	IdempotencyKey string            `json:"idempotency_key"`
+	Reference      string            `json:"reference,omitempty"`
+	Reason         string            `json:"reason,omitempty"`
+	Amount         money.Money       `json:"amount"`
+	Metadata       map[string]string `json:"metadata,omitempty"`
+}
+
+// Sign returns the signature header value of body sent at t.
+func Sign(secret []byte, t time.Time, body []byte) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac(secret, ts, body))
+}
+
+// Verify checks the signature header value of body.
+func Verify(secret []byte, header string, body []byte, now time.Time) error {
+	var ts string
+	var sigs [][]byte
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			ts = value
+		case "v1":
+			if sig, err := hex.DecodeString(value); err == nil {
+				sigs = append(sigs, sig)
+			}
+		}
+	}
+
+	sent, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: missing timestamp", ErrInvalidSignature)
+	}
+	if age := now.Sub(time.Unix(sent, 0)); age > SignatureTolerance || age < -SignatureTolerance {
+		return fmt.Errorf("%w: timestamp out of tolerance", ErrInvalidSignature)
+	}
+
+	expected := mac(secret, ts, body)
+	for _, sig := range sigs {
+		if hmac.Equal(sig, expected) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+func mac(secret []byte, ts string, body []byte) []byte {
+	h := hmac.New(sha256.New, secret)
+	h.Write([]byte(ts))
+	h.Write([]byte("."))
+	h.Write(body)
+	return h.Sum(nil)
+}
+
+// NewWebhookHandler serves webhooks signed with secret and passes their
+// events to handle. Unsigned or stale requests get 401, failures of handle
+// 500 so that the provider delivers the event again.
+func NewWebhookHandler(secret []byte, handle func(context.Context, Event) error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookSize))
+		if err != nil {
+			http.Error(w, "failed to read body", http.StatusRequestEntityTooLarge)
+			return
+		}
+		if err := Verify(secret, r.Header.Get(SignatureHeader), body, time.Now()); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		var event Event
+		if err := json.Unmarshal(body, &event); err != nil {
+			http.Error(w, "invalid event", http.StatusBadRequest)
+			return
+		}
+		if err := handle(r.Context(), event); err != nil {
+			slog.ErrorContext(r.Context(), "failed to handle payment webhook", "event_id", event.ID, "error", err)
+			http.Error(w, "failed to handle event", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// WebhookClient delivers signed webhooks, as a provider would.
+type WebhookClient struct {
+	url    string
+	secret []byte
+	client *http.Client
+}
+
+func NewWebhookClient(url string, secret []byte) *WebhookClient {
+	return &WebhookClient{
+		url:    url,
+		secret: secret,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (c *WebhookClient) Send(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, Sign(c.secret, time.Now(), body))
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer res.Body.Close()
+	io.Copy(io.Discard, res.Body)
+
+	if res.StatusCode/100 != 2 {
+		return fmt.Errorf("failed to send webhook: %w", errors.New(res.Status))
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package payments
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVerify(t *testing.T) {
+	secret := []byte("secret")
+	body := []byte(`{"id":"evt_1"}`)
+	now := time.Unix(1700000000, 0)
+	ts := strconv.FormatInt(now.Unix(), 10)
+	v1 := strings.TrimPrefix(Sign(secret, now, body), "t="+ts+",")
+
+	tests := []struct {
+		name   string
+		header string
+		err    error
+	}{
+		{"valid", Sign(secret, now, body), nil},
+		{"within tolerance", Sign(secret, now.Add(-SignatureTolerance), body), nil},
+		{"one of several signatures", "t=" + ts + ",v1=00ff," + v1, nil},
+		{"bad signature", Sign([]byte("other"), now, body), ErrInvalidSignature},
+		{"signature not hex", "t=" + ts + ",v1=zz", ErrInvalidSignature},
+		{"stale timestamp", Sign(secret, now.Add(-SignatureTolerance-time.Second), body), ErrInvalidSignature},
+		{"future timestamp", Sign(secret, now.Add(SignatureTolerance+time.Second), body), ErrInvalidSignature},
+		{"missing v1", "t=" + ts, ErrInvalidSignature},
+		{"missing timestamp", v1, ErrInvalidSignature},
+		{"empty", "", ErrInvalidSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(secret, tt.header, body, now)
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Verify() error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package promotions
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+)
+
+// Line is an order line to price. Prices are in minor units of the
+// currency of the order.
+type Line struct {
+	BookID    string
+	AuthorID  string
+	Quantity  int32
+	UnitPrice int64
+}
+
+// Discount is the part of a promotion that went to a line.
+type Discount struct {
+	Promotion *Promotion
+	Amount    int64
+}
+
+// PricedLine is a line with its discounts. Total is Subtotal minus the
+// discounts.
+type PricedLine struct {
+	Line
+	Subtotal  int64
+	Discounts []Discount
+	Total     int64
+}
+
+// Pricing is the outcome of Apply.
+type Pricing struct {
+	Lines    []PricedLine
+	Subtotal int64
+	Discount int64
+	Total    int64
+	// Applied are the promotions that gave a discount, each once.
+	Applied []*Promotion
+}
+
+// Apply prices lines with the promotions usable at now. Promotions with a
+// code only take part when code names them. Every line gets the best
+// targeted discount, then the best untargeted one is taken off the rest of
+// the order and spread over the lines in proportion to what they cost.
+//
+// Fixed amounts of promotions must be in the currency of the lines.
+//
+// A code that names no usable promotion fails with ErrUnknownCoupon, one
+// that ends up giving no discount with ErrCouponNotApplicable.
+func Apply(promotions []*Promotion, lines []Line, code string, now time.Time) (*Pricing, error) {
+	code = NormalizeCode(code)
+
+	var candidates []*Promotion
+	var coupon *Promotion
+	for _, p := range promotions {
+		if !p.usable(now) {
+			continue
+		}
+		switch p.Code {
+		case "":
+			candidates = append(candidates, p)
+		case code:
+			coupon = p
+			candidates = append(candidates, p)
+		}
+	}
+	if code != "" && coupon == nil {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownCoupon, code)
+	}
+
+	priced := make([]PricedLine, len(lines))
+	remaining := make([]int64, len(lines))
+	var subtotal, rest int64
+	for i, line := range lines {
+		if line.UnitPrice > math.MaxInt64/int64(max(line.Quantity, 1)) {
+			return nil, ErrTotalOutOfBounds
+		}
+		lineSubtotal := line.UnitPrice * int64(line.Quantity)
+		priced[i] = PricedLine{Line: line, Subtotal: lineSubtotal}
+
+		var best *Promotion
+		var bestAmount int64
+		for _, p := range candidates {
+			if !p.targeted() || !p.matches(line) {
+				continue
+			}
+			if amount := lineDiscount(p, line, lineSubtotal); amount > bestAmount {
+				best, bestAmount = p, amount
+			}
+		}
+		if best != nil {
+			priced[i].Discounts = append(priced[i].Discounts, Discount{Promotion: best, Amount: bestAmount})
+		}
+
+		if subtotal > math.MaxInt64-lineSubtotal {
+			return nil, ErrTotalOutOfBounds
+		}
+		remaining[i] = lineSubtotal - bestAmount
+		subtotal += lineSubtotal
+		rest += remaining[i]
+	}
+
+	var best *Promotion
+	var bestAmount int64
+	for _, p := range candidates {
+		if p.targeted() {
+			continue
+		}
+		if amount := orderDiscount(p, rest); amount > bestAmount {
+			best, bestAmount = p, amount
+		}
+	}
+	if best != nil {
+		for i, amount := range spread(bestAmount, remaining) {
+			if amount > 0 {
+				priced[i].Discounts = append(priced[i].Discounts, Discount{Promotion: best, Amount: amount})
+			}
+		}
+	}
+
+	pricing := &Pricing{Lines: priced, Subtotal: subtotal}
+	seen := make(map[*Promotion]bool)
+	for i := range priced {
+		line := &priced[i]
+		line.Total = line.Subtotal
+		for _, d := range line.Discounts {
+			line.Total -= d.Amount
+			pricing.Discount += d.Amount
+			if !seen[d.Promotion] {
+				seen[d.Promotion] = true
+				pricing.Applied = append(pricing.Applied, d.Promotion)
+			}
+		}
+		pricing.Total += line.Total
+	}
+	if coupon != nil && !seen[coupon] {
+		return nil, fmt.Errorf("%w: %q", ErrCouponNotApplicable, code)
+	}
+	return pricing, nil
+}
+
+func lineDiscount(p *Promotion, line Line, subtotal int64) int64 {
+	var amount int64
+	switch p.Kind {
+	case KindPercentage:
+		amount = percentOf(subtotal, p.Percent)
+	case KindFixedAmount:
+		if p.Amount > subtotal/int64(line.Quantity) {
+			return subtotal
+		}
+		amount = p.Amount * int64(line.Quantity)
+	case KindBuyXGetY:
+		free := int64(line.Quantity) / int64(p.BuyQuantity+p.GetQuantity) * int64(p.GetQuantity)
+		amount = free * line.UnitPrice
+	}
+	return min(amount, subtotal)
+}
+
+func orderDiscount(p *Promotion, total int64) int64 {
+	var amount int64
+	switch p.Kind {
+	case KindPercentage:
+		amount = percentOf(total, p.Percent)
+	case KindFixedAmount:
+		amount = p.Amount
+	}
+	return min(amount, total)
+}
+
+// percentOf returns percent percent of amount, rounded down, without
+// overflowing.
+func percentOf(amount int64, percent int32) int64 {
+	return amount/100*int64(percent) + amount%100*int64(percent)/100
+}
+
+// spread splits amount over weights in proportion to them. Rounding leftovers
+// go to the first lines that still have room, so no share exceeds its
+// weight as long as amount does not exceed their sum.
+func spread(amount int64, weights []int64) []int64 {
+	shares := make([]int64, len(weights))
+	var total, given int64
+	for _, w := range weights {
+		total += w
+	}
+	if total == 0 {
+		return shares
+	}
+	for i, w := range weights {
+		// amount * w may not fit in an int64, the share does
+		share := new(big.Int).Mul(big.NewInt(amount), big.NewInt(w))
+		shares[i] = share.Quo(share, big.NewInt(total)).Int64()
+		given += shares[i]
+	}
+	for i := range shares {
+		if given == amount {
+			break
+		}
+		if room := weights[i] - shares[i]; room > 0 {
+			add := min(room, amount-given)
+			shares[i] += add
+			given += add
+		}
+	}
+	return shares
+}
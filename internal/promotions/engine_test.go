@@ -0,0 +1,227 @@
+package promotions
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestApply(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	before, after := now.Add(-time.Hour), now.Add(time.Hour)
+
+	tenPercentOffBook := &Promotion{Name: "10% off", Kind: KindPercentage, Percent: 10, BookIDs: []string{"b1"}}
+	fixedOffAuthor := &Promotion{Name: "1.50 off", Kind: KindFixedAmount, Amount: 150, AuthorIDs: []string{"a1"}}
+	buyTwoGetOne := &Promotion{Name: "3 for 2", Kind: KindBuyXGetY, BuyQuantity: 2, GetQuantity: 1}
+	fixedOffOrder := &Promotion{Name: "1.00 off", Kind: KindFixedAmount, Amount: 100}
+	twentyPercentOffOrder := &Promotion{Name: "20% off", Kind: KindPercentage, Percent: 20}
+	coupon := &Promotion{Name: "Summer", Kind: KindPercentage, Percent: 15, Code: "SUMMER"}
+	expiredCoupon := &Promotion{Name: "Spring", Kind: KindPercentage, Percent: 15, Code: "SPRING", EndsAt: &before}
+	futureCoupon := &Promotion{Name: "Autumn", Kind: KindPercentage, Percent: 15, Code: "AUTUMN", StartsAt: &after}
+	usedUpCoupon := &Promotion{Name: "Once", Kind: KindPercentage, Percent: 15, Code: "ONCE", MaxRedemptions: 1, Redemptions: 1}
+	otherBookCoupon := &Promotion{Name: "Other book", Kind: KindPercentage, Percent: 15, Code: "OTHER", BookIDs: []string{"b9"}}
+	expiredAutomatic := &Promotion{Name: "Ended", Kind: KindPercentage, Percent: 50, EndsAt: &before}
+
+	tests := []struct {
+		name       string
+		promotions []*Promotion
+		lines      []Line
+		code       string
+		// discounts are the discount amounts of every line
+		discounts [][]int64
+		total     int64
+		applied   []*Promotion
+		err       error
+	}{
+		{
+			name:       "no promotions",
+			promotions: nil,
+			lines:      []Line{{BookID: "b1", AuthorID: "a1", Quantity: 2, UnitPrice: 1000}},
+			discounts:  [][]int64{nil},
+			total:      2000,
+		},
+		{
+			name:       "best targeted discount",
+			promotions: []*Promotion{tenPercentOffBook, fixedOffAuthor},
+			lines: []Line{
+				{BookID: "b1", AuthorID: "a1", Quantity: 2, UnitPrice: 1000},
+				{BookID: "b2", AuthorID: "a2", Quantity: 1, UnitPrice: 1000},
+			},
+			discounts: [][]int64{{300}, nil},
+			total:     2700,
+			applied:   []*Promotion{fixedOffAuthor},
+		},
+		{
+			name:       "best targeted discount per line",
+			promotions: []*Promotion{tenPercentOffBook, buyTwoGetOne},
+			lines: []Line{
+				{BookID: "b1", AuthorID: "a1", Quantity: 1, UnitPrice: 1000},
+				{BookID: "b2", AuthorID: "a2", Quantity: 3, UnitPrice: 500},
+			},
+			discounts: [][]int64{{100}, {500}},
+			total:     1900,
+			applied:   []*Promotion{tenPercentOffBook, buyTwoGetOne},
+		},
+		{
+			name:       "untargeted discount spread with rounding",
+			promotions: []*Promotion{fixedOffOrder},
+			lines: []Line{
+				{BookID: "b1", Quantity: 1, UnitPrice: 300},
+				{BookID: "b2", Quantity: 1, UnitPrice: 300},
+				{BookID: "b3", Quantity: 1, UnitPrice: 300},
+			},
+			discounts: [][]int64{{34}, {33}, {33}},
+			total:     800,
+			applied:   []*Promotion{fixedOffOrder},
+		},
+		{
+			name:       "best untargeted discount taken off the rest",
+			promotions: []*Promotion{tenPercentOffBook, fixedOffOrder, twentyPercentOffOrder},
+			lines: []Line{
+				{BookID: "b1", Quantity: 1, UnitPrice: 1000},
+				{BookID: "b2", Quantity: 1, UnitPrice: 600},
+			},
+			discounts: [][]int64{{100, 180}, {120}},
+			total:     1200,
+			applied:   []*Promotion{tenPercentOffBook, twentyPercentOffOrder},
+		},
+		{
+			name:       "untargeted discount capped at the order",
+			promotions: []*Promotion{fixedOffOrder},
+			lines:      []Line{{BookID: "b1", Quantity: 1, UnitPrice: 60}},
+			discounts:  [][]int64{{60}},
+			total:      0,
+			applied:    []*Promotion{fixedOffOrder},
+		},
+		{
+			name:       "coupon",
+			promotions: []*Promotion{coupon},
+			lines:      []Line{{BookID: "b1", Quantity: 1, UnitPrice: 1000}},
+			code:       " summer ",
+			discounts:  [][]int64{{150}},
+			total:      850,
+			applied:    []*Promotion{coupon},
+		},
+		{
+			name:       "coupon without code",
+			promotions: []*Promotion{coupon},
+			lines:      []Line{{BookID: "b1", Quantity: 1, UnitPrice: 1000}},
+			discounts:  [][]int64{nil},
+			total:      1000,
+		},
+		{
+			name:       "expired automatic promotion",
+			promotions: []*Promotion{expiredAutomatic},
+			lines:      []Line{{BookID: "b1", Quantity: 1, UnitPrice: 1000}},
+			discounts:  [][]int64{nil},
+			total:      1000,
+		},
+		{
+			name:       "expired coupon",
+			promotions: []*Promotion{expiredCoupon},
+			lines:      []Line{{BookID: "b1", Quantity: 1, UnitPrice: 1000}},
+			code:       "SPRING",
+			err:        ErrUnknownCoupon,
+		},
+		{
+			name:       "coupon not started",
+			promotions: []*Promotion{futureCoupon},
+			lines:      []Line{{BookID: "b1", Quantity: 1, UnitPrice: 1000}},
+			code:       "AUTUMN",
+			err:        ErrUnknownCoupon,
+		},
+		{
+			name:       "used up coupon",
+			promotions: []*Promotion{usedUpCoupon},
+			lines:      []Line{{BookID: "b1", Quantity: 1, UnitPrice: 1000}},
+			code:       "ONCE",
+			err:        ErrUnknownCoupon,
+		},
+		{
+			name:       "unknown coupon",
+			promotions: []*Promotion{coupon},
+			lines:      []Line{{BookID: "b1", Quantity: 1, UnitPrice: 1000}},
+			code:       "WINTER",
+			err:        ErrUnknownCoupon,
+		},
+		{
+			name:       "coupon for other books",
+			promotions: []*Promotion{otherBookCoupon},
+			lines:      []Line{{BookID: "b1", Quantity: 1, UnitPrice: 1000}},
+			code:       "OTHER",
+			err:        ErrCouponNotApplicable,
+		},
+		{
+			name:       "coupon beaten by an automatic promotion",
+			promotions: []*Promotion{twentyPercentOffOrder, coupon},
+			lines:      []Line{{BookID: "b1", Quantity: 1, UnitPrice: 1000}},
+			code:       "SUMMER",
+			err:        ErrCouponNotApplicable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Apply(tt.promotions, tt.lines, tt.code, now)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Apply() error = %v, want %v", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+
+			if len(got.Lines) != len(tt.discounts) {
+				t.Fatalf("Apply() priced %d lines, want %d", len(got.Lines), len(tt.discounts))
+			}
+			for i, line := range got.Lines {
+				var amounts []int64
+				for _, d := range line.Discounts {
+					amounts = append(amounts, d.Amount)
+				}
+				if !slices.Equal(amounts, tt.discounts[i]) {
+					t.Errorf("line %d discounts = %v, want %v", i, amounts, tt.discounts[i])
+				}
+			}
+			if got.Total != tt.total {
+				t.Errorf("Apply() total = %d, want %d", got.Total, tt.total)
+			}
+			if got.Subtotal-got.Discount != got.Total {
+				t.Errorf("Apply() subtotal %d minus discount %d is not the total %d", got.Subtotal, got.Discount, got.Total)
+			}
+			if !slices.Equal(got.Applied, tt.applied) {
+				t.Errorf("Apply() applied %v, want %v", names(got.Applied), names(tt.applied))
+			}
+		})
+	}
+}
+
+func TestSpread(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  int64
+		weights []int64
+		want    []int64
+	}{
+		{"proportional", 150, []int64{1000, 500}, []int64{100, 50}},
+		{"leftover to the first lines", 100, []int64{300, 300, 300}, []int64{34, 33, 33}},
+		{"leftover before larger shares", 5, []int64{1, 1, 10}, []int64{1, 0, 4}},
+		{"zero weights", 100, []int64{0, 0}, []int64{0, 0}},
+		{"whole weights", 600, []int64{100, 200, 300}, []int64{100, 200, 300}},
+		{"large amounts", 1 << 60, []int64{1 << 61, 1 << 61}, []int64{1 << 59, 1 << 59}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := spread(tt.amount, tt.weights); !slices.Equal(got, tt.want) {
+				t.Errorf("spread(%d, %v) = %v, want %v", tt.amount, tt.weights, got, tt.want)
+			}
+		})
+	}
+}
+
+func names(promotions []*Promotion) []string {
+	var names []string
+	for _, p := range promotions {
+		names = append(names, p.Name)
+	}
+	return names
+}
@@ -0,0 +1,20 @@
+package promotions
+
+import "errors"
+
+var (
+	ErrInvalidID           = errors.New("promotions: invalid id")
+	ErrInvalidName         = errors.New("promotions: invalid name")
+	ErrInvalidKind         = errors.New("promotions: invalid kind")
+	ErrInvalidCode         = errors.New("promotions: invalid code")
+	ErrInvalidValue        = errors.New("promotions: invalid discount value")
+	ErrInvalidWindow       = errors.New("promotions: invalid validity window")
+	ErrInvalidTarget       = errors.New("promotions: invalid target")
+	ErrInvalidRedemptions  = errors.New("promotions: invalid max redemptions")
+	ErrCodeExists          = errors.New("promotions: code already exists")
+	ErrPromotionNotFound   = errors.New("promotions: promotion not found")
+	ErrPromotionExhausted  = errors.New("promotions: promotion has no redemptions left")
+	ErrUnknownCoupon       = errors.New("promotions: unknown or expired coupon")
+	ErrCouponNotApplicable = errors.New("promotions: coupon does not apply to the order")
+	ErrTotalOutOfBounds    = errors.New("promotions: total price out of bounds")
+)
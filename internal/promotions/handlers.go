@@ -0,0 +1,182 @@
+// Package promotions implements the PromotionsService and the engine that
+// applies promotions when orders are priced.
+package promotions
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/iho/bookstore/internal/money"
+	v1 "github.com/iho/bookstore/protos/gen/promotions/v1"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var kinds = map[v1.PromotionKind]Kind{
+	v1.PromotionKind_PROMOTION_KIND_PERCENTAGE:   KindPercentage,
+	v1.PromotionKind_PROMOTION_KIND_FIXED_AMOUNT: KindFixedAmount,
+	v1.PromotionKind_PROMOTION_KIND_BUY_X_GET_Y:  KindBuyXGetY,
+}
+
+type PromotionsService struct {
+	store *Store
+	// currency is the currency of the store, fixed amounts default to it.
+	currency string
+}
+
+func NewPromotionsService(store *Store, currency string) *PromotionsService {
+	return &PromotionsService{store: store, currency: currency}
+}
+
+func (ps *PromotionsService) ListPromotions(ctx context.Context, req *connect.Request[v1.ListPromotionsRequest]) (*connect.Response[v1.ListPromotionsResponse], error) {
+	promotions, err := ps.store.list(ctx, bson.D{})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*v1.Promotion, 0, len(promotions))
+	for _, p := range promotions {
+		res = append(res, toProto(p))
+	}
+
+	return &connect.Response[v1.ListPromotionsResponse]{
+		Msg: &v1.ListPromotionsResponse{
+			Promotions: res,
+		},
+	}, nil
+}
+
+func (ps *PromotionsService) GetPromotion(ctx context.Context, req *connect.Request[v1.GetPromotionRequest]) (*connect.Response[v1.GetPromotionResponse], error) {
+	id, err := parseID(req.Msg.Id)
+	if err != nil {
+		return nil, err
+	}
+	p, err := ps.store.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &connect.Response[v1.GetPromotionResponse]{
+		Msg: &v1.GetPromotionResponse{
+			Promotion: toProto(p),
+		},
+	}, nil
+}
+
+func (ps *PromotionsService) CreatePromotion(ctx context.Context, req *connect.Request[v1.CreatePromotionRequest]) (*connect.Response[v1.CreatePromotionResponse], error) {
+	msg := req.Msg.GetPromotion()
+	if msg == nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("%w: missing promotion", ErrInvalidName))
+	}
+
+	p := &Promotion{
+		ID:             primitive.NewObjectID(),
+		Name:           strings.TrimSpace(msg.Name),
+		Kind:           kinds[msg.Kind],
+		Code:           NormalizeCode(msg.Code),
+		Percent:        msg.Percent,
+		Amount:         msg.Amount,
+		BuyQuantity:    msg.BuyQuantity,
+		GetQuantity:    msg.GetQuantity,
+		BookIDs:        msg.BookIds,
+		AuthorIDs:      msg.AuthorIds,
+		MaxRedemptions: msg.MaxRedemptions,
+	}
+	var err error
+	if p.Kind == KindFixedAmount {
+		p.Currency = ps.currency
+		if msg.Currency != "" {
+			if p.Currency, err = money.ParseCurrency(msg.Currency); err != nil {
+				return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("%w: %w", ErrInvalidValue, err))
+			}
+		}
+	}
+	if p.StartsAt, err = parseTime(msg.StartsAt); err != nil {
+		return nil, err
+	}
+	if p.EndsAt, err = parseTime(msg.EndsAt); err != nil {
+		return nil, err
+	}
+	if err := p.Validate(); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	if err := ps.store.create(ctx, p); err != nil {
+		return nil, err
+	}
+
+	return &connect.Response[v1.CreatePromotionResponse]{
+		Msg: &v1.CreatePromotionResponse{
+			Promotion: toProto(p),
+		},
+	}, nil
+}
+
+func (ps *PromotionsService) DeletePromotion(ctx context.Context, req *connect.Request[v1.DeletePromotionRequest]) (*connect.Response[v1.DeletePromotionResponse], error) {
+	id, err := parseID(req.Msg.Id)
+	if err != nil {
+		return nil, err
+	}
+	deleted, err := ps.store.delete(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &connect.Response[v1.DeletePromotionResponse]{
+		Msg: &v1.DeletePromotionResponse{
+			Status: deleted,
+		},
+	}, nil
+}
+
+func toProto(p *Promotion) *v1.Promotion {
+	msg := &v1.Promotion{
+		Id:             p.ID.Hex(),
+		Name:           p.Name,
+		Code:           p.Code,
+		Percent:        p.Percent,
+		Amount:         p.Amount,
+		BuyQuantity:    p.BuyQuantity,
+		GetQuantity:    p.GetQuantity,
+		BookIds:        p.BookIDs,
+		AuthorIds:      p.AuthorIDs,
+		MaxRedemptions: p.MaxRedemptions,
+		Redemptions:    p.Redemptions,
+		Currency:       p.Currency,
+	}
+	for kind, k := range kinds {
+		if k == p.Kind {
+			msg.Kind = kind
+			break
+		}
+	}
+	if p.StartsAt != nil {
+		msg.StartsAt = p.StartsAt.UTC().Format(time.RFC3339)
+	}
+	if p.EndsAt != nil {
+		msg.EndsAt = p.EndsAt.UTC().Format(time.RFC3339)
+	}
+	return msg
+}
+
+func parseID(id string) (primitive.ObjectID, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return primitive.NilObjectID, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("%w: %q", ErrInvalidID, id))
+	}
+	return oid, nil
+}
+
+func parseTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("%w: %w", ErrInvalidWindow, err))
+	}
+	return &t, nil
+}
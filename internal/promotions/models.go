@@ -0,0 +1,109 @@
+package promotions
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Kind is how a promotion computes its discount.
+type Kind string
+
+const (
+	KindPercentage  Kind = "percentage"
+	KindFixedAmount Kind = "fixed_amount"
+	KindBuyXGetY    Kind = "buy_x_get_y"
+)
+
+// Codes are matched case-insensitively, they are stored upper case.
+var codePattern = regexp.MustCompile(`^[A-Z0-9_-]{1,32}$`)
+
+type Promotion struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty"`
+	Name           string             `bson:"name"`
+	Kind           Kind               `bson:"kind"`
+	Code           string             `bson:"code,omitempty"`
+	Percent        int32              `bson:"percent,omitempty"`
+	Amount         int64              `bson:"amount,omitempty"`
+	Currency       string             `bson:"currency,omitempty"`
+	BuyQuantity    int32              `bson:"buy_quantity,omitempty"`
+	GetQuantity    int32              `bson:"get_quantity,omitempty"`
+	BookIDs        []string           `bson:"book_ids,omitempty"`
+	AuthorIDs      []string           `bson:"author_ids,omitempty"`
+	StartsAt       *time.Time         `bson:"starts_at,omitempty"`
+	EndsAt         *time.Time         `bson:"ends_at,omitempty"`
+	MaxRedemptions int32              `bson:"max_redemptions"`
+	Redemptions    int32              `bson:"redemptions"`
+}
+
+// NormalizeCode returns code the way it is stored.
+func NormalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Validate checks that the promotion can be applied.
+func (p *Promotion) Validate() error {
+	if strings.TrimSpace(p.Name) == "" {
+		return ErrInvalidName
+	}
+	if p.Code != "" && !codePattern.MatchString(p.Code) {
+		return fmt.Errorf("%w: %q", ErrInvalidCode, p.Code)
+	}
+
+	switch p.Kind {
+	case KindPercentage:
+		if p.Percent < 1 || p.Percent > 100 {
+			return fmt.Errorf("%w: percent must be between 1 and 100", ErrInvalidValue)
+		}
+	case KindFixedAmount:
+		if p.Amount < 1 {
+			return fmt.Errorf("%w: amount must be greater than 0", ErrInvalidValue)
+		}
+	case KindBuyXGetY:
+		if p.BuyQuantity < 1 || p.GetQuantity < 1 {
+			return fmt.Errorf("%w: buy and get quantities must be greater than 0", ErrInvalidValue)
+		}
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidKind, p.Kind)
+	}
+
+	if slices.Contains(p.BookIDs, "") || slices.Contains(p.AuthorIDs, "") {
+		return ErrInvalidTarget
+	}
+	if p.StartsAt != nil && p.EndsAt != nil && !p.EndsAt.After(*p.StartsAt) {
+		return ErrInvalidWindow
+	}
+	if p.MaxRedemptions < 0 {
+		return ErrInvalidRedemptions
+	}
+	return nil
+}
+
+// targeted reports whether the promotion only applies to some lines.
+// Buy X get Y is counted per book, so it always works on lines.
+func (p *Promotion) targeted() bool {
+	return len(p.BookIDs) > 0 || len(p.AuthorIDs) > 0 || p.Kind == KindBuyXGetY
+}
+
+// usable reports whether the promotion may apply at now.
+func (p *Promotion) usable(now time.Time) bool {
+	if p.StartsAt != nil && now.Before(*p.StartsAt) {
+		return false
+	}
+	if p.EndsAt != nil && !now.Before(*p.EndsAt) {
+		return false
+	}
+	return p.MaxRedemptions == 0 || p.Redemptions < p.MaxRedemptions
+}
+
+// matches reports whether the promotion applies to a line.
+func (p *Promotion) matches(line Line) bool {
+	if len(p.BookIDs) == 0 && len(p.AuthorIDs) == 0 {
+		return true
+	}
+	return slices.Contains(p.BookIDs, line.BookID) || slices.Contains(p.AuthorIDs, line.AuthorID)
+}
@@ -0,0 +1,193 @@
+package promotions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"connectrpc.com/connect"
+	"github.com/iho/bookstore/internal/tenant"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	databaseKey   = "bookstore"
+	collectionKey = "promotions"
+)
+
+// Store keeps promotions in Mongo, in a collection per tenant like orders.
+type Store struct {
+	client *mongo.Client
+	// indexed holds the tenants whose collection has its indexes.
+	indexed sync.Map
+}
+
+func NewStore(client *mongo.Client) *Store {
+	return &Store{client: client}
+}
+
+// promotions returns the promotions collection of the tenant of ctx.
+func (s *Store) promotions(ctx context.Context) (*mongo.Collection, string, error) {
+	id, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	return s.client.Database(databaseKey).Collection(collectionKey + "_" + id), id, nil
+}
+
+// collection returns the promotions of the tenant of ctx, making sure codes
+// are unique within it. Indexes cannot be created in transactions, so ctx
+// must not be a session context.
+func (s *Store) collection(ctx context.Context) (*mongo.Collection, error) {
+	coll, id, err := s.promotions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := s.indexed.Load(id); ok {
+		return coll, nil
+	}
+
+	_, err = coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "code", Value: 1}},
+		Options: options.Index().
+			SetUnique(true).
+			SetPartialFilterExpression(bson.D{{Key: "code", Value: bson.D{{Key: "$exists", Value: true}}}}),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create promotion indexes: %w", err)
+	}
+	s.indexed.Store(id, struct{}{})
+	return coll, nil
+}
+
+func (s *Store) create(ctx context.Context, p *Promotion) error {
+	coll, err := s.collection(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err := coll.InsertOne(ctx, p); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return connect.NewError(connect.CodeAlreadyExists, fmt.Errorf("%w: %q", ErrCodeExists, p.Code))
+		}
+		return fmt.Errorf("failed to create promotion: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) get(ctx context.Context, id primitive.ObjectID) (*Promotion, error) {
+	coll, err := s.collection(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p := new(Promotion)
+	err = coll.FindOne(ctx, bson.D{{Key: "_id", Value: id}}).Decode(p)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("%w: [id=%s]", ErrPromotionNotFound, id.Hex()))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get promotion: %w", err)
+	}
+	return p, nil
+}
+
+func (s *Store) list(ctx context.Context, filter bson.D) ([]*Promotion, error) {
+	coll, err := s.collection(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cursor, err := coll.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list promotions: %w", err)
+	}
+	var promotions []*Promotion
+	if err := cursor.All(ctx, &promotions); err != nil {
+		return nil, fmt.Errorf("failed to decode promotions: %w", err)
+	}
+	return promotions, nil
+}
+
+func (s *Store) delete(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	coll, err := s.collection(ctx)
+	if err != nil {
+		return false, err
+	}
+	res, err := coll.DeleteOne(ctx, bson.D{{Key: "_id", Value: id}})
+	if err != nil {
+		return false, fmt.Errorf("failed to delete promotion: %w", err)
+	}
+	return res.DeletedCount > 0, nil
+}
+
+// Candidates returns the promotions that may apply to an order with code:
+// the automatic ones and the coupon named by code. Apply picks the usable
+// ones among them.
+func (s *Store) Candidates(ctx context.Context, code string) ([]*Promotion, error) {
+	codes := bson.A{nil}
+	if code = NormalizeCode(code); code != "" {
+		codes = append(codes, code)
+	}
+	return s.list(ctx, bson.D{{Key: "code", Value: bson.D{{Key: "$in", Value: codes}}}})
+}
+
+// Redeem counts an order against the usage limits of promotions. ctx should
+// be the session context of the transaction creating or repricing the
+// order. A promotion used up in the meantime fails the call with
+// CodeAborted, pricing the order again leaves it out.
+func (s *Store) Redeem(ctx context.Context, promotions []*Promotion) error {
+	if len(promotions) == 0 {
+		return nil
+	}
+	coll, _, err := s.promotions(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range promotions {
+		filter := bson.D{
+			{Key: "_id", Value: p.ID},
+			{Key: "$or", Value: bson.A{
+				bson.D{{Key: "max_redemptions", Value: 0}},
+				bson.D{{Key: "$expr", Value: bson.D{{Key: "$lt", Value: bson.A{"$redemptions", "$max_redemptions"}}}}},
+			}},
+		}
+		update := bson.D{{Key: "$inc", Value: bson.D{{Key: "redemptions", Value: 1}}}}
+		res, err := coll.UpdateOne(ctx, filter, update)
+		if err != nil {
+			return fmt.Errorf("failed to redeem promotion: %w", err)
+		}
+		if res.MatchedCount == 0 {
+			return connect.NewError(connect.CodeAborted, fmt.Errorf("%w: [id=%s]", ErrPromotionExhausted, p.ID.Hex()))
+		}
+	}
+	return nil
+}
+
+// Release takes back the redemptions of an order that was cancelled,
+// refunded, deleted or repriced without the promotions with ids. ctx should
+// be the session context of the transaction changing the order. Promotions
+// deleted in the meantime are skipped.
+func (s *Store) Release(ctx context.Context, ids []primitive.ObjectID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	coll, _, err := s.promotions(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		filter := bson.D{
+			{Key: "_id", Value: id},
+			{Key: "redemptions", Value: bson.D{{Key: "$gt", Value: 0}}},
+		}
+		update := bson.D{{Key: "$inc", Value: bson.D{{Key: "redemptions", Value: -1}}}}
+		if _, err := coll.UpdateOne(ctx, filter, update); err != nil {
+			return fmt.Errorf("failed to release promotion: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package ratelimit
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+	"github.com/iho/bookstore/internal/caller"
+)
+
+type interceptor struct {
+	limiter *Limiter
+}
+
+// NewInterceptor limits the calls handlers accept per end user, by the
+// default limit and the limit named after the procedure, e.g.
+// "/orders.v1.OrdersService/CreateOrder". Rejected calls fail with
+// CodeResourceExhausted and a Retry-After header. Only calls forwarding a
+// verified subject are limited: the others come from the gateway and other
+// services on behalf of many callers, which the gateway limits by IP.
+func NewInterceptor(limiter *Limiter) connect.Interceptor {
+	return &interceptor{limiter: limiter}
+}
+
+func (i *interceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if req.Spec().IsClient {
+			return next(ctx, req)
+		}
+		if err := i.allow(ctx, req.Spec().Procedure); err != nil {
+			return nil, err
+		}
+		return next(ctx, req)
+	}
+}
+
+func (i *interceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (i *interceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		if err := i.allow(ctx, conn.Spec().Procedure); err != nil {
+			return err
+		}
+		return next(ctx, conn)
+	}
+}
+
+func (i *interceptor) allow(ctx context.Context, procedure string) error {
+	subject, ok := caller.Subject(ctx)
+	if !ok {
+		return nil
+	}
+	client := "sub:" + subject
+	retryAfter := i.limiter.AllowClient(ctx, client)
+	if retryAfter == 0 {
+		retryAfter = i.limiter.AllowNamed(ctx, client, procedure)
+	}
+	if retryAfter == 0 {
+		return nil
+	}
+	err := connect.NewError(connect.CodeResourceExhausted, ErrRateLimited)
+	err.Meta().Set("Retry-After", retryAfterSeconds(retryAfter))
+	return err
+}
@@ -0,0 +1,76 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/felixge/httpsnoop"
+)
+
+type ctxKey struct{}
+
+// request is the rate limiting state of one HTTP request.
+type request struct {
+	client string
+
+	mu         sync.Mutex
+	retryAfter time.Duration
+}
+
+// Middleware rejects requests over the default limit of their client with
+// 429 Too Many Requests. Handlers can reject requests over a named limit
+// with Reject and still get the status code and Retry-After header.
+func (l *Limiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		client := ClientKey(r.Context(), r.RemoteAddr)
+		if retryAfter := l.AllowClient(r.Context(), client); retryAfter > 0 {
+			w.Header().Set("Retry-After", retryAfterSeconds(retryAfter))
+			http.Error(w, ErrRateLimited.Error(), http.StatusTooManyRequests)
+			return
+		}
+
+		req := &request{client: client}
+		w = httpsnoop.Wrap(w, httpsnoop.Hooks{
+			WriteHeader: func(next httpsnoop.WriteHeaderFunc) httpsnoop.WriteHeaderFunc {
+				return func(code int) {
+					req.mu.Lock()
+					retryAfter := req.retryAfter
+					req.mu.Unlock()
+					if retryAfter > 0 {
+						w.Header().Set("Retry-After", retryAfterSeconds(retryAfter))
+						code = http.StatusTooManyRequests
+					}
+					next(code)
+				}
+			},
+		})
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), ctxKey{}, req)))
+	})
+}
+
+// Client returns the client key Middleware derived for the request.
+func Client(ctx context.Context) (string, bool) {
+	req, ok := ctx.Value(ctxKey{}).(*request)
+	if !ok {
+		return "", false
+	}
+	return req.client, true
+}
+
+// Reject makes Middleware answer the request with 429 and Retry-After, as
+// long as the status has not been written yet.
+func Reject(ctx context.Context, retryAfter time.Duration) {
+	if req, ok := ctx.Value(ctxKey{}).(*request); ok {
+		req.mu.Lock()
+		req.retryAfter = retryAfter
+		req.mu.Unlock()
+	}
+}
+
+func retryAfterSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
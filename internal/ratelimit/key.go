@@ -0,0 +1,23 @@
+package ratelimit
+
+import (
+	"context"
+	"net"
+
+	"github.com/iho/bookstore/internal/caller"
+)
+
+// ClientKey identifies the caller of a request: by its verified subject, or
+// else by IP address. Credentials that were not verified never pick the
+// bucket, a client could make up new ones for every request.
+func ClientKey(ctx context.Context, remoteAddr string) string {
+	if subject, ok := caller.Subject(ctx); ok {
+		return "sub:" + subject
+	}
+
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	return "ip:" + host
+}
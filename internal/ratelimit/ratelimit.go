@@ -0,0 +1,149 @@
+// Package ratelimit limits how many requests a single client may make. The
+// gateway limits GraphQL operations and the services limit Connect
+// procedures, each with a default limit per client and optional limits per
+// operation or procedure name.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iho/bookstore/internal/redisclient"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	ErrRateLimited  = errors.New("ratelimit: too many requests")
+	ErrInvalidLimit = errors.New("ratelimit: invalid limit")
+	ErrUnknownStore = errors.New("ratelimit: unknown store")
+)
+
+var rejections = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "rate_limited_requests_total",
+	Help: "Requests rejected by the rate limiter by limit name.",
+}, []string{"limit"})
+
+// RegisterMetrics registers the rate limiter metrics.
+func RegisterMetrics(reg prometheus.Registerer) {
+	reg.MustRegister(rejections)
+}
+
+// Limit allows Requests per Per, in bursts of up to Requests.
+type Limit struct {
+	Requests int
+	Per      time.Duration
+}
+
+// ParseLimit parses limits written as "100/1m".
+func ParseLimit(s string) (Limit, error) {
+	requests, per, ok := strings.Cut(s, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("%w: [limit=%s]", ErrInvalidLimit, s)
+	}
+	n, err := strconv.Atoi(requests)
+	if err != nil || n < 1 {
+		return Limit{}, fmt.Errorf("%w: [limit=%s]", ErrInvalidLimit, s)
+	}
+	d, err := time.ParseDuration(per)
+	if err != nil || d <= 0 {
+		return Limit{}, fmt.Errorf("%w: [limit=%s]", ErrInvalidLimit, s)
+	}
+	return Limit{Requests: n, Per: d}, nil
+}
+
+// Store counts requests per key.
+type Store interface {
+	// Allow takes one request from the allowance of key. When the request
+	// is over limit it returns how long to wait before retrying.
+	Allow(ctx context.Context, key string, limit Limit) (retryAfter time.Duration, err error)
+}
+
+// Limiter applies Default to every client and Named limits to operations or
+// procedures with that name, on top of the default.
+type Limiter struct {
+	store   Store
+	Default Limit
+	Named   map[string]Limit
+}
+
+func NewLimiter(store Store, def Limit, named map[string]Limit) *Limiter {
+	return &Limiter{store: store, Default: def, Named: named}
+}
+
+// AllowClient checks the default limit of client.
+func (l *Limiter) AllowClient(ctx context.Context, client string) time.Duration {
+	return l.allow(ctx, "default", "*:"+client, l.Default)
+}
+
+// AllowNamed checks the limit for name of client, if there is one.
+func (l *Limiter) AllowNamed(ctx context.Context, client, name string) time.Duration {
+	limit, ok := l.Named[name]
+	if !ok {
+		return 0
+	}
+	return l.allow(ctx, name, name+":"+client, limit)
+}
+
+func (l *Limiter) allow(ctx context.Context, name, key string, limit Limit) time.Duration {
+	retryAfter, err := l.store.Allow(ctx, key, limit)
+	if err != nil {
+		// an unreachable store must not take the service down with it
+		slog.WarnContext(ctx, "failed to check rate limit", "limit", name, "error", err)
+		return 0
+	}
+	if retryAfter > 0 {
+		rejections.WithLabelValues(name).Inc()
+	}
+	return retryAfter
+}
+
+// FromEnv builds a limiter from RATE_LIMIT, e.g. "600/1m" per client, and
+// RATE_LIMIT_NAMED, e.g. "CreateOrder=10/1m,/books.v1.BooksService/CreateBook=30/1m".
+// RATE_LIMIT_STORE picks "memory", the default, or "redis" configured by
+// RATE_LIMIT_REDIS_ADDRS and the other variables of
+// redisclient.OptionsFromEnv. It returns nil when RATE_LIMIT is not set.
+func FromEnv() (*Limiter, error) {
+	value := os.Getenv("RATE_LIMIT")
+	if value == "" {
+		return nil, nil
+	}
+	def, err := ParseLimit(value)
+	if err != nil {
+		return nil, err
+	}
+
+	named := map[string]Limit{}
+	if value := os.Getenv("RATE_LIMIT_NAMED"); value != "" {
+		for _, entry := range strings.Split(value, ",") {
+			name, limit, ok := strings.Cut(strings.TrimSpace(entry), "=")
+			if !ok {
+				return nil, fmt.Errorf("%w: [entry=%s]", ErrInvalidLimit, entry)
+			}
+			if named[name], err = ParseLimit(limit); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var store Store
+	switch kind := os.Getenv("RATE_LIMIT_STORE"); kind {
+	case "", "memory":
+		store = NewMemoryStore()
+	case "redis":
+		rdb, err := redisclient.FromEnv("RATE_LIMIT_REDIS", "redis:6379")
+		if err != nil {
+			return nil, err
+		}
+		store = NewRedisStore(rdb)
+	default:
+		return nil, fmt.Errorf("%w: [store=%s]", ErrUnknownStore, kind)
+	}
+
+	return NewLimiter(store, def, named), nil
+}
@@ -0,0 +1,51 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	redis "github.com/redis/go-redis/v9"
+)
+
+const redisPrefix = "ratelimit:"
+
+// slidingWindow keeps the timestamps of the requests in the last window of
+// a key in a sorted set. It returns 0 when the request is allowed, else the
+// milliseconds until the oldest request leaves the window.
+var slidingWindow = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", now - window)
+if redis.call("ZCARD", KEYS[1]) < limit then
+	redis.call("ZADD", KEYS[1], now, ARGV[4])
+	redis.call("PEXPIRE", KEYS[1], window)
+	return 0
+end
+local oldest = redis.call("ZRANGE", KEYS[1], 0, 0, "WITHSCORES")
+return math.max(tonumber(oldest[2]) + window - now, 1)
+`)
+
+// RedisStore counts requests in a sliding window shared by all replicas.
+type RedisStore struct {
+	rdb redis.UniversalClient
+}
+
+func NewRedisStore(rdb redis.UniversalClient) *RedisStore {
+	return &RedisStore{rdb: rdb}
+}
+
+func (s *RedisStore) Allow(ctx context.Context, key string, limit Limit) (time.Duration, error) {
+	retryAfter, err := slidingWindow.Run(ctx, s.rdb, []string{redisPrefix + key},
+		time.Now().UnixMilli(),
+		limit.Per.Milliseconds(),
+		limit.Requests,
+		uuid.NewString(),
+	).Int64()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check rate limit: [key=%s] %w", key, err)
+	}
+	return time.Duration(retryAfter) * time.Millisecond, nil
+}
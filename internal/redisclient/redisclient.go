@@ -0,0 +1,54 @@
+// Package redisclient builds the Redis clients of the services from the
+// environment, so that every one of them supports standalone servers,
+// Sentinel and Cluster alike.
+package redisclient
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	redis "github.com/redis/go-redis/v9"
+)
+
+// OptionsFromEnv configures a client from the variables named after prefix:
+// <prefix>_ADDRS, a comma separated list, or <prefix>_ADDR, and
+// <prefix>_MASTER_NAME, <prefix>_PASSWORD and <prefix>_DB. A master name
+// selects Sentinel, several addresses select Cluster and a single address a
+// standalone server. Without an address defaultAddr is used, and nil is
+// returned when that is empty too.
+func OptionsFromEnv(prefix, defaultAddr string) (*redis.UniversalOptions, error) {
+	addrs := os.Getenv(prefix + "_ADDRS")
+	if addrs == "" {
+		addrs = cmp.Or(os.Getenv(prefix+"_ADDR"), defaultAddr)
+	}
+	if addrs == "" {
+		return nil, nil
+	}
+
+	opts := &redis.UniversalOptions{
+		Addrs:      strings.Split(addrs, ","),
+		MasterName: os.Getenv(prefix + "_MASTER_NAME"),
+		Password:   os.Getenv(prefix + "_PASSWORD"),
+	}
+	if db := os.Getenv(prefix + "_DB"); db != "" {
+		n, err := strconv.Atoi(db)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s_DB: %w", prefix, err)
+		}
+		opts.DB = n
+	}
+	return opts, nil
+}
+
+// FromEnv returns a client configured by OptionsFromEnv, nil when there is
+// no address.
+func FromEnv(prefix, defaultAddr string) (redis.UniversalClient, error) {
+	opts, err := OptionsFromEnv(prefix, defaultAddr)
+	if err != nil || opts == nil {
+		return nil, err
+	}
+	return redis.NewUniversalClient(opts), nil
+}
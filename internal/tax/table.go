@@ -0,0 +1,104 @@
+package tax
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/iho/bookstore/internal/money"
+)
+
+// Table charges a percentage of the price per region. Subdivisions without
+// a rate of their own get the rate of their country, other regions and
+// orders without one the default rate.
+type Table struct {
+	rates map[string]*big.Rat
+	// def is nil when regions without a rate are refused.
+	def *big.Rat
+}
+
+var _ Calculator = (*Table)(nil)
+
+// NewTable parses rates, which are percentages as decimal strings such as
+// "7.25". An empty def refuses regions without a rate.
+func NewTable(rates map[string]string, def string) (*Table, error) {
+	t := &Table{rates: make(map[string]*big.Rat, len(rates))}
+	for region, value := range rates {
+		rate, err := parseRate(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: [region=%s]", err, region)
+		}
+		t.rates[NormalizeRegion(region)] = rate
+	}
+	if def != "" {
+		rate, err := parseRate(def)
+		if err != nil {
+			return nil, fmt.Errorf("%w: [region=default]", err)
+		}
+		t.def = rate
+	}
+	return t, nil
+}
+
+// LoadTable reads a table from a JSON file such as
+//
+//	{"default": "0", "rates": {"DE": "19", "US-CA": "7.25"}}
+func LoadTable(path string) (*Table, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tax rates: %w", err)
+	}
+	var file struct {
+		Default string            `json:"default"`
+		Rates   map[string]string `json:"rates"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse tax rates: [path=%s] %w", path, err)
+	}
+	return NewTable(file.Rates, file.Default)
+}
+
+// NewTableFromEnv loads the table in TAX_RATES_FILE. Without one no tax is
+// charged.
+func NewTableFromEnv() (*Table, error) {
+	if path := os.Getenv("TAX_RATES_FILE"); path != "" {
+		return LoadTable(path)
+	}
+	return NewTable(nil, "0")
+}
+
+// Rate returns the percentage charged in region.
+func (t *Table) Rate(region string) (*big.Rat, error) {
+	region = NormalizeRegion(region)
+	if rate, ok := t.rates[region]; ok {
+		return rate, nil
+	}
+	if country, _, found := strings.Cut(region, "-"); found {
+		if rate, ok := t.rates[country]; ok {
+			return rate, nil
+		}
+	}
+	if t.def == nil {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownRegion, region)
+	}
+	return t.def, nil
+}
+
+func (t *Table) Tax(ctx context.Context, region string, amount money.Money) (money.Money, error) {
+	rate, err := t.Rate(region)
+	if err != nil {
+		return money.Money{}, err
+	}
+	return amount.Scale(new(big.Rat).Quo(rate, big.NewRat(100, 1)))
+}
+
+func parseRate(value string) (*big.Rat, error) {
+	rate, ok := new(big.Rat).SetString(value)
+	if !ok || rate.Sign() < 0 || rate.Cmp(big.NewRat(100, 1)) > 0 {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidRate, value)
+	}
+	return rate, nil
+}
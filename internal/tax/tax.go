@@ -0,0 +1,44 @@
+// Package tax computes the taxes owed on orders. Calculators are pluggable,
+// the table calculator applies rates per region from a config file.
+package tax
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/iho/bookstore/internal/money"
+)
+
+var (
+	ErrUnknownRegion     = errors.New("tax: no rate for region")
+	ErrUnknownCalculator = errors.New("tax: unknown calculator")
+	ErrInvalidRate       = errors.New("tax: invalid rate")
+)
+
+// Calculator computes the tax on goods delivered to a region.
+type Calculator interface {
+	// Tax returns the tax on amount, the price of the goods after
+	// discounts, in the currency of amount. It fails with ErrUnknownRegion
+	// when it has no rate for region.
+	Tax(ctx context.Context, region string, amount money.Money) (money.Money, error)
+}
+
+// FromEnv returns the calculator picked by TAX_CALCULATOR. "table", the
+// default, is the only one so far; see NewTableFromEnv.
+func FromEnv() (Calculator, error) {
+	switch kind := os.Getenv("TAX_CALCULATOR"); kind {
+	case "", "table":
+		return NewTableFromEnv()
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownCalculator, kind)
+	}
+}
+
+// NormalizeRegion returns region the way it is looked up. Regions are ISO
+// 3166 codes, a country such as "DE" or a subdivision such as "US-CA".
+func NormalizeRegion(region string) string {
+	return strings.ToUpper(strings.TrimSpace(region))
+}
@@ -0,0 +1,131 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"connectrpc.com/connect"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Interceptor traces Connect calls. On clients it starts a span per call and
+// injects the trace context into the request headers; on handlers it
+// continues the trace found in the headers.
+type Interceptor struct{}
+
+func NewInterceptor() *Interceptor {
+	return &Interceptor{}
+}
+
+func (i *Interceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if req.Spec().IsClient {
+			ctx, span := startSpan(ctx, req.Spec(), trace.SpanKindClient)
+			defer span.End()
+			inject(ctx, req.Header())
+
+			res, err := next(ctx, req)
+			endCall(span, err)
+			return res, err
+		}
+
+		ctx = extract(ctx, req.Header())
+		ctx, span := startSpan(ctx, req.Spec(), trace.SpanKindServer)
+		defer span.End()
+
+		res, err := next(ctx, req)
+		endCall(span, err)
+		return res, err
+	}
+}
+
+func (i *Interceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return func(ctx context.Context, spec connect.Spec) connect.StreamingClientConn {
+		ctx, span := startSpan(ctx, spec, trace.SpanKindClient)
+		conn := next(ctx, spec)
+		inject(ctx, conn.RequestHeader())
+		return &tracedClientConn{StreamingClientConn: conn, span: span}
+	}
+}
+
+func (i *Interceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		ctx = extract(ctx, conn.RequestHeader())
+		ctx, span := startSpan(ctx, conn.Spec(), trace.SpanKindServer)
+		defer span.End()
+
+		err := next(ctx, conn)
+		endCall(span, err)
+		return err
+	}
+}
+
+// tracedClientConn ends the span of a streaming call once the response is
+// closed, recording the first receive error.
+type tracedClientConn struct {
+	connect.StreamingClientConn
+	span trace.Span
+	once sync.Once
+	err  error
+}
+
+func (c *tracedClientConn) Receive(msg any) error {
+	err := c.StreamingClientConn.Receive(msg)
+	if err != nil && !errors.Is(err, io.EOF) && c.err == nil {
+		c.err = err
+	}
+	return err
+}
+
+func (c *tracedClientConn) CloseResponse() error {
+	err := c.StreamingClientConn.CloseResponse()
+	c.once.Do(func() {
+		endCall(c.span, c.err)
+		c.span.End()
+	})
+	return err
+}
+
+func startSpan(ctx context.Context, spec connect.Spec, kind trace.SpanKind) (context.Context, trace.Span) {
+	service, method := splitProcedure(spec.Procedure)
+	return tracer().Start(ctx, strings.TrimPrefix(spec.Procedure, "/"),
+		trace.WithSpanKind(kind),
+		trace.WithAttributes(
+			semconv.RPCSystemKey.String("connect_rpc"),
+			semconv.RPCService(service),
+			semconv.RPCMethod(method),
+		),
+	)
+}
+
+func endCall(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.SetAttributes(attribute.String("rpc.connect_rpc.error_code", connect.CodeOf(err).String()))
+	recordError(span, err)
+}
+
+// splitProcedure splits "/books.v1.BooksService/GetBook" into the service
+// and method names.
+func splitProcedure(procedure string) (string, string) {
+	procedure = strings.TrimPrefix(procedure, "/")
+	service, method, _ := strings.Cut(procedure, "/")
+	return service, method
+}
+
+func inject(ctx context.Context, header http.Header) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+func extract(ctx context.Context, header http.Header) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(header))
+}
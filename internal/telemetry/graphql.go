@@ -0,0 +1,105 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// GraphQLTracer is a gqlgen extension recording a span per operation and
+// one per resolver call. Fields that are read straight from their parent
+// object are not traced. Every event of a subscription gets its own
+// operation span. Operations are recorded by name only, as documents may
+// carry user data inline.
+type GraphQLTracer struct{}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationInterceptor
+	graphql.FieldInterceptor
+} = GraphQLTracer{}
+
+func (GraphQLTracer) ExtensionName() string {
+	return "OpenTelemetry"
+}
+
+func (GraphQLTracer) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (t GraphQLTracer) InterceptOperation(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	oc := graphql.GetOperationContext(ctx)
+	if oc.Operation != nil && oc.Operation.Operation == ast.Subscription {
+		handler := next(ctx)
+		return func(ctx context.Context) *graphql.Response {
+			ctx, span := startOperation(ctx, oc)
+			defer span.End()
+
+			res := handler(ctx)
+			endOperation(span, res)
+			return res
+		}
+	}
+
+	ctx, span := startOperation(ctx, oc)
+	handler := next(ctx)
+	return func(ctx context.Context) *graphql.Response {
+		defer span.End()
+
+		res := handler(trace.ContextWithSpan(ctx, span))
+		endOperation(span, res)
+		return res
+	}
+}
+
+func (t GraphQLTracer) InterceptField(ctx context.Context, next graphql.Resolver) (any, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || !fc.IsResolver {
+		return next(ctx)
+	}
+
+	ctx, span := tracer().Start(ctx, fmt.Sprintf("graphql.resolve %s.%s", fc.Object, fc.Field.Name),
+		trace.WithAttributes(
+			attribute.String("graphql.field.path", fc.Path().String()),
+			attribute.String("graphql.field.name", fc.Field.Name),
+			attribute.String("graphql.field.type", fc.Field.Definition.Type.String()),
+		),
+	)
+	defer span.End()
+
+	res, err := next(ctx)
+	recordError(span, err)
+	return res, err
+}
+
+func startOperation(ctx context.Context, oc *graphql.OperationContext) (context.Context, trace.Span) {
+	opType := "query"
+	if oc.Operation != nil {
+		opType = string(oc.Operation.Operation)
+	}
+	name := oc.OperationName
+	if name == "" {
+		name = "anonymous"
+	}
+
+	return tracer().Start(ctx, fmt.Sprintf("graphql.%s %s", opType, name),
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.String("graphql.operation.type", opType),
+			attribute.String("graphql.operation.name", oc.OperationName),
+		),
+	)
+}
+
+func endOperation(span trace.Span, res *graphql.Response) {
+	if res == nil || len(res.Errors) == 0 {
+		return
+	}
+	span.SetAttributes(attribute.Int("graphql.errors", len(res.Errors)))
+	span.SetStatus(codes.Error, res.Errors.Error())
+}
@@ -0,0 +1,138 @@
+// Package tenant scopes calls to a tenant. The gateway picks the tenant of a
+// request, Connect metadata carries it to the services and the services only
+// touch the data of the tenant in the context. Services take the tenant only
+// from internal callers, see package caller.
+package tenant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+
+	"connectrpc.com/connect"
+	"github.com/iho/bookstore/internal/caller"
+)
+
+// Header carries the tenant ID of a call.
+const Header = "X-Tenant-Id"
+
+var (
+	ErrMissing = errors.New("tenant: missing tenant")
+	ErrInvalid = errors.New("tenant: invalid tenant ID")
+)
+
+// IDs end up in Redis keys and Mongo collection names, so they are kept to
+// a safe alphabet.
+var idPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`)
+
+type ctxKey struct{}
+
+// LegacyFromEnv returns the tenant that data stored before the services were
+// scoped to tenants belongs to: LEGACY_TENANT, or "default".
+func LegacyFromEnv() (string, error) {
+	id := os.Getenv("LEGACY_TENANT")
+	if id == "" {
+		return "default", nil
+	}
+	if err := Validate(id); err != nil {
+		return "", fmt.Errorf("invalid LEGACY_TENANT: %w", err)
+	}
+	return id, nil
+}
+
+// Validate reports whether id is a well-formed tenant ID.
+func Validate(id string) error {
+	if !idPattern.MatchString(id) {
+		return fmt.Errorf("%w: %q", ErrInvalid, id)
+	}
+	return nil
+}
+
+// WithID stores the tenant ID in ctx.
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// FromContext returns the tenant ID stored in ctx.
+func FromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(ctxKey{}).(string)
+	return id, ok && id != ""
+}
+
+// Require returns the tenant ID stored in ctx, failing with
+// CodeInvalidArgument when there is none. Services call it before touching
+// any data, so that a handler reached without the interceptor fails closed.
+func Require(ctx context.Context) (string, error) {
+	id, ok := FromContext(ctx)
+	if !ok {
+		return "", connect.NewError(connect.CodeInvalidArgument, ErrMissing)
+	}
+	return id, nil
+}
+
+type interceptor struct{}
+
+// NewInterceptor passes the tenant on between services. On clients it sends
+// the tenant of the context in the X-Tenant-Id header, on handlers it
+// rejects calls without a valid header with CodeInvalidArgument and stores
+// the tenant in the context otherwise. Handlers only accept the header from
+// callers trusted by the caller interceptor, which has to run first, and
+// reject other calls with CodeUnauthenticated.
+func NewInterceptor() connect.Interceptor {
+	return interceptor{}
+}
+
+func (interceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if req.Spec().IsClient {
+			forward(ctx, req.Header())
+			return next(ctx, req)
+		}
+		ctx, err := fromHeader(ctx, req.Header())
+		if err != nil {
+			return nil, err
+		}
+		return next(ctx, req)
+	}
+}
+
+func (interceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return func(ctx context.Context, spec connect.Spec) connect.StreamingClientConn {
+		conn := next(ctx, spec)
+		forward(ctx, conn.RequestHeader())
+		return conn
+	}
+}
+
+func (interceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		ctx, err := fromHeader(ctx, conn.RequestHeader())
+		if err != nil {
+			return err
+		}
+		return next(ctx, conn)
+	}
+}
+
+func forward(ctx context.Context, header http.Header) {
+	if id, ok := FromContext(ctx); ok {
+		header.Set(Header, id)
+	}
+}
+
+func fromHeader(ctx context.Context, header http.Header) (context.Context, error) {
+	if !caller.Trusted(ctx) {
+		return ctx, connect.NewError(connect.CodeUnauthenticated, caller.ErrUntrusted)
+	}
+	id := header.Get(Header)
+	if id == "" {
+		return ctx, connect.NewError(connect.CodeInvalidArgument, ErrMissing)
+	}
+	if err := Validate(id); err != nil {
+		return ctx, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	return WithID(ctx, id), nil
+}
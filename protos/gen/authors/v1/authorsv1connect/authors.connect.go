@@ -48,6 +48,9 @@ const (
 	// AuthorsServiceDeleteAuthorProcedure is the fully-qualified name of the AuthorsService's
 	// DeleteAuthor RPC.
 	AuthorsServiceDeleteAuthorProcedure = "/authors.v1.AuthorsService/DeleteAuthor"
+	// AuthorsServiceWatchAuthorsProcedure is the fully-qualified name of the AuthorsService's
+	// WatchAuthors RPC.
+	AuthorsServiceWatchAuthorsProcedure = "/authors.v1.AuthorsService/WatchAuthors"
 )
 
 // These variables are the protoreflect.Descriptor objects for the RPCs defined in this package.
@@ -58,6 +61,7 @@ var (
 	authorsServiceCreateAuthorMethodDescriptor = authorsServiceServiceDescriptor.Methods().ByName("CreateAuthor")
 	authorsServiceUpdateAuthorMethodDescriptor = authorsServiceServiceDescriptor.Methods().ByName("UpdateAuthor")
 	authorsServiceDeleteAuthorMethodDescriptor = authorsServiceServiceDescriptor.Methods().ByName("DeleteAuthor")
+	authorsServiceWatchAuthorsMethodDescriptor = authorsServiceServiceDescriptor.Methods().ByName("WatchAuthors")
 )
 
 // AuthorsServiceClient is a client for the authors.v1.AuthorsService service.
@@ -67,6 +71,7 @@ type AuthorsServiceClient interface {
 	CreateAuthor(context.Context, *connect.Request[v1.CreateAuthorRequest]) (*connect.Response[v1.CreateAuthorResponse], error)
 	UpdateAuthor(context.Context, *connect.Request[v1.UpdateAuthorRequest]) (*connect.Response[v1.UpdateAuthorResponse], error)
 	DeleteAuthor(context.Context, *connect.Request[v1.DeleteAuthorRequest]) (*connect.Response[v1.DeleteAuthorResponse], error)
+	WatchAuthors(context.Context, *connect.Request[v1.WatchAuthorsRequest]) (*connect.ServerStreamForClient[v1.WatchAuthorsResponse], error)
 }
 
 // NewAuthorsServiceClient constructs a client for the authors.v1.AuthorsService service. By
@@ -83,12 +88,14 @@ func NewAuthorsServiceClient(httpClient connect.HTTPClient, baseURL string, opts
 			httpClient,
 			baseURL+AuthorsServiceListAuthorsProcedure,
 			connect.WithSchema(authorsServiceListAuthorsMethodDescriptor),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
 			connect.WithClientOptions(opts...),
 		),
 		getAuthor: connect.NewClient[v1.GetAuthorRequest, v1.GetAuthorResponse](
 			httpClient,
 			baseURL+AuthorsServiceGetAuthorProcedure,
 			connect.WithSchema(authorsServiceGetAuthorMethodDescriptor),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
 			connect.WithClientOptions(opts...),
 		),
 		createAuthor: connect.NewClient[v1.CreateAuthorRequest, v1.CreateAuthorResponse](
@@ -109,6 +116,12 @@ func NewAuthorsServiceClient(httpClient connect.HTTPClient, baseURL string, opts
 			connect.WithSchema(authorsServiceDeleteAuthorMethodDescriptor),
 			connect.WithClientOptions(opts...),
 		),
+		watchAuthors: connect.NewClient[v1.WatchAuthorsRequest, v1.WatchAuthorsResponse](
+			httpClient,
+			baseURL+AuthorsServiceWatchAuthorsProcedure,
+			connect.WithSchema(authorsServiceWatchAuthorsMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
 	}
 }
 
@@ -119,6 +132,7 @@ type authorsServiceClient struct {
 	createAuthor *connect.Client[v1.CreateAuthorRequest, v1.CreateAuthorResponse]
 	updateAuthor *connect.Client[v1.UpdateAuthorRequest, v1.UpdateAuthorResponse]
 	deleteAuthor *connect.Client[v1.DeleteAuthorRequest, v1.DeleteAuthorResponse]
+	watchAuthors *connect.Client[v1.WatchAuthorsRequest, v1.WatchAuthorsResponse]
 }
 
 // ListAuthors calls authors.v1.AuthorsService.ListAuthors.
@@ -146,6 +160,11 @@ func (c *authorsServiceClient) DeleteAuthor(ctx context.Context, req *connect.Re
 	return c.deleteAuthor.CallUnary(ctx, req)
 }
 
+// WatchAuthors calls authors.v1.AuthorsService.WatchAuthors.
+func (c *authorsServiceClient) WatchAuthors(ctx context.Context, req *connect.Request[v1.WatchAuthorsRequest]) (*connect.ServerStreamForClient[v1.WatchAuthorsResponse], error) {
+	return c.watchAuthors.CallServerStream(ctx, req)
+}
+
 // AuthorsServiceHandler is an implementation of the authors.v1.AuthorsService service.
 type AuthorsServiceHandler interface {
 	ListAuthors(context.Context, *connect.Request[v1.ListAuthorsRequest]) (*connect.Response[v1.ListAuthorsResponse], error)
@@ -153,6 +172,7 @@ type AuthorsServiceHandler interface {
 	CreateAuthor(context.Context, *connect.Request[v1.CreateAuthorRequest]) (*connect.Response[v1.CreateAuthorResponse], error)
 	UpdateAuthor(context.Context, *connect.Request[v1.UpdateAuthorRequest]) (*connect.Response[v1.UpdateAuthorResponse], error)
 	DeleteAuthor(context.Context, *connect.Request[v1.DeleteAuthorRequest]) (*connect.Response[v1.DeleteAuthorResponse], error)
+	WatchAuthors(context.Context, *connect.Request[v1.WatchAuthorsRequest], *connect.ServerStream[v1.WatchAuthorsResponse]) error
 }
 
 // NewAuthorsServiceHandler builds an HTTP handler from the service implementation. It returns the
@@ -165,12 +185,14 @@ func NewAuthorsServiceHandler(svc AuthorsServiceHandler, opts ...connect.Handler
 		AuthorsServiceListAuthorsProcedure,
 		svc.ListAuthors,
 		connect.WithSchema(authorsServiceListAuthorsMethodDescriptor),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
 		connect.WithHandlerOptions(opts...),
 	)
 	authorsServiceGetAuthorHandler := connect.NewUnaryHandler(
 		AuthorsServiceGetAuthorProcedure,
 		svc.GetAuthor,
 		connect.WithSchema(authorsServiceGetAuthorMethodDescriptor),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
 		connect.WithHandlerOptions(opts...),
 	)
 	authorsServiceCreateAuthorHandler := connect.NewUnaryHandler(
@@ -191,6 +213,12 @@ func NewAuthorsServiceHandler(svc AuthorsServiceHandler, opts ...connect.Handler
 		connect.WithSchema(authorsServiceDeleteAuthorMethodDescriptor),
 		connect.WithHandlerOptions(opts...),
 	)
+	authorsServiceWatchAuthorsHandler := connect.NewServerStreamHandler(
+		AuthorsServiceWatchAuthorsProcedure,
+		svc.WatchAuthors,
+		connect.WithSchema(authorsServiceWatchAuthorsMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
 	return "/authors.v1.AuthorsService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case AuthorsServiceListAuthorsProcedure:
@@ -203,6 +231,8 @@ func NewAuthorsServiceHandler(svc AuthorsServiceHandler, opts ...connect.Handler
 			authorsServiceUpdateAuthorHandler.ServeHTTP(w, r)
 		case AuthorsServiceDeleteAuthorProcedure:
 			authorsServiceDeleteAuthorHandler.ServeHTTP(w, r)
+		case AuthorsServiceWatchAuthorsProcedure:
+			authorsServiceWatchAuthorsHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -231,3 +261,7 @@ func (UnimplementedAuthorsServiceHandler) UpdateAuthor(context.Context, *connect
 func (UnimplementedAuthorsServiceHandler) DeleteAuthor(context.Context, *connect.Request[v1.DeleteAuthorRequest]) (*connect.Response[v1.DeleteAuthorResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("authors.v1.AuthorsService.DeleteAuthor is not implemented"))
 }
+
+func (UnimplementedAuthorsServiceHandler) WatchAuthors(context.Context, *connect.Request[v1.WatchAuthorsRequest], *connect.ServerStream[v1.WatchAuthorsResponse]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("authors.v1.AuthorsService.WatchAuthors is not implemented"))
+}
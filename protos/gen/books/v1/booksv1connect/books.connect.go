@@ -43,6 +43,8 @@ const (
 	BooksServiceUpdateBookProcedure = "/books.v1.BooksService/UpdateBook"
 	// BooksServiceDeleteBookProcedure is the fully-qualified name of the BooksService's DeleteBook RPC.
 	BooksServiceDeleteBookProcedure = "/books.v1.BooksService/DeleteBook"
+	// BooksServiceWatchBooksProcedure is the fully-qualified name of the BooksService's WatchBooks RPC.
+	BooksServiceWatchBooksProcedure = "/books.v1.BooksService/WatchBooks"
 )
 
 // These variables are the protoreflect.Descriptor objects for the RPCs defined in this package.
@@ -53,6 +55,7 @@ var (
 	booksServiceCreateBookMethodDescriptor = booksServiceServiceDescriptor.Methods().ByName("CreateBook")
 	booksServiceUpdateBookMethodDescriptor = booksServiceServiceDescriptor.Methods().ByName("UpdateBook")
 	booksServiceDeleteBookMethodDescriptor = booksServiceServiceDescriptor.Methods().ByName("DeleteBook")
+	booksServiceWatchBooksMethodDescriptor = booksServiceServiceDescriptor.Methods().ByName("WatchBooks")
 )
 
 // BooksServiceClient is a client for the books.v1.BooksService service.
@@ -62,6 +65,7 @@ type BooksServiceClient interface {
 	CreateBook(context.Context, *connect.Request[v1.CreateBookRequest]) (*connect.Response[v1.CreateBookResponse], error)
 	UpdateBook(context.Context, *connect.Request[v1.UpdateBookRequest]) (*connect.Response[v1.UpdateBookResponse], error)
 	DeleteBook(context.Context, *connect.Request[v1.DeleteBookRequest]) (*connect.Response[v1.DeleteBookResponse], error)
+	WatchBooks(context.Context, *connect.Request[v1.WatchBooksRequest]) (*connect.ServerStreamForClient[v1.WatchBooksResponse], error)
 }
 
 // NewBooksServiceClient constructs a client for the books.v1.BooksService service. By default, it
@@ -78,12 +82,14 @@ func NewBooksServiceClient(httpClient connect.HTTPClient, baseURL string, opts .
 			httpClient,
 			baseURL+BooksServiceListBooksProcedure,
 			connect.WithSchema(booksServiceListBooksMethodDescriptor),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
 			connect.WithClientOptions(opts...),
 		),
 		getBook: connect.NewClient[v1.GetBookRequest, v1.GetBookResponse](
 			httpClient,
 			baseURL+BooksServiceGetBookProcedure,
 			connect.WithSchema(booksServiceGetBookMethodDescriptor),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
 			connect.WithClientOptions(opts...),
 		),
 		createBook: connect.NewClient[v1.CreateBookRequest, v1.CreateBookResponse](
@@ -104,6 +110,12 @@ func NewBooksServiceClient(httpClient connect.HTTPClient, baseURL string, opts .
 			connect.WithSchema(booksServiceDeleteBookMethodDescriptor),
 			connect.WithClientOptions(opts...),
 		),
+		watchBooks: connect.NewClient[v1.WatchBooksRequest, v1.WatchBooksResponse](
+			httpClient,
+			baseURL+BooksServiceWatchBooksProcedure,
+			connect.WithSchema(booksServiceWatchBooksMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
 	}
 }
 
@@ -114,6 +126,7 @@ type booksServiceClient struct {
 	createBook *connect.Client[v1.CreateBookRequest, v1.CreateBookResponse]
 	updateBook *connect.Client[v1.UpdateBookRequest, v1.UpdateBookResponse]
 	deleteBook *connect.Client[v1.DeleteBookRequest, v1.DeleteBookResponse]
+	watchBooks *connect.Client[v1.WatchBooksRequest, v1.WatchBooksResponse]
 }
 
 // ListBooks calls books.v1.BooksService.ListBooks.
@@ -141,6 +154,11 @@ func (c *booksServiceClient) DeleteBook(ctx context.Context, req *connect.Reques
 	return c.deleteBook.CallUnary(ctx, req)
 }
 
+// WatchBooks calls books.v1.BooksService.WatchBooks.
+func (c *booksServiceClient) WatchBooks(ctx context.Context, req *connect.Request[v1.WatchBooksRequest]) (*connect.ServerStreamForClient[v1.WatchBooksResponse], error) {
+	return c.watchBooks.CallServerStream(ctx, req)
+}
+
 // BooksServiceHandler is an implementation of the books.v1.BooksService service.
 type BooksServiceHandler interface {
 	ListBooks(context.Context, *connect.Request[v1.ListBooksRequest]) (*connect.Response[v1.ListBooksResponse], error)
@@ -148,6 +166,7 @@ type BooksServiceHandler interface {
 	CreateBook(context.Context, *connect.Request[v1.CreateBookRequest]) (*connect.Response[v1.CreateBookResponse], error)
 	UpdateBook(context.Context, *connect.Request[v1.UpdateBookRequest]) (*connect.Response[v1.UpdateBookResponse], error)
 	DeleteBook(context.Context, *connect.Request[v1.DeleteBookRequest]) (*connect.Response[v1.DeleteBookResponse], error)
+	WatchBooks(context.Context, *connect.Request[v1.WatchBooksRequest], *connect.ServerStream[v1.WatchBooksResponse]) error
 }
 
 // NewBooksServiceHandler builds an HTTP handler from the service implementation. It returns the
@@ -160,12 +179,14 @@ func NewBooksServiceHandler(svc BooksServiceHandler, opts ...connect.HandlerOpti
 		BooksServiceListBooksProcedure,
 		svc.ListBooks,
 		connect.WithSchema(booksServiceListBooksMethodDescriptor),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
 		connect.WithHandlerOptions(opts...),
 	)
 	booksServiceGetBookHandler := connect.NewUnaryHandler(
 		BooksServiceGetBookProcedure,
 		svc.GetBook,
 		connect.WithSchema(booksServiceGetBookMethodDescriptor),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
 		connect.WithHandlerOptions(opts...),
 	)
 	booksServiceCreateBookHandler := connect.NewUnaryHandler(
@@ -186,6 +207,12 @@ func NewBooksServiceHandler(svc BooksServiceHandler, opts ...connect.HandlerOpti
 		connect.WithSchema(booksServiceDeleteBookMethodDescriptor),
 		connect.WithHandlerOptions(opts...),
 	)
+	booksServiceWatchBooksHandler := connect.NewServerStreamHandler(
+		BooksServiceWatchBooksProcedure,
+		svc.WatchBooks,
+		connect.WithSchema(booksServiceWatchBooksMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
 	return "/books.v1.BooksService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case BooksServiceListBooksProcedure:
@@ -198,6 +225,8 @@ func NewBooksServiceHandler(svc BooksServiceHandler, opts ...connect.HandlerOpti
 			booksServiceUpdateBookHandler.ServeHTTP(w, r)
 		case BooksServiceDeleteBookProcedure:
 			booksServiceDeleteBookHandler.ServeHTTP(w, r)
+		case BooksServiceWatchBooksProcedure:
+			booksServiceWatchBooksHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -226,3 +255,7 @@ func (UnimplementedBooksServiceHandler) UpdateBook(context.Context, *connect.Req
 func (UnimplementedBooksServiceHandler) DeleteBook(context.Context, *connect.Request[v1.DeleteBookRequest]) (*connect.Response[v1.DeleteBookResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("books.v1.BooksService.DeleteBook is not implemented"))
 }
+
+func (UnimplementedBooksServiceHandler) WatchBooks(context.Context, *connect.Request[v1.WatchBooksRequest], *connect.ServerStream[v1.WatchBooksResponse]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("books.v1.BooksService.WatchBooks is not implemented"))
+}
@@ -0,0 +1,272 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: cart/v1/cart.proto
+
+package cartv1connect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	v1 "github.com/iho/bookstore/protos/gen/cart/v1"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// CartServiceName is the fully-qualified name of the CartService service.
+	CartServiceName = "cart.v1.CartService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// CartServiceCreateCartProcedure is the fully-qualified name of the CartService's CreateCart RPC.
+	CartServiceCreateCartProcedure = "/cart.v1.CartService/CreateCart"
+	// CartServiceGetCartProcedure is the fully-qualified name of the CartService's GetCart RPC.
+	CartServiceGetCartProcedure = "/cart.v1.CartService/GetCart"
+	// CartServiceAddItemProcedure is the fully-qualified name of the CartService's AddItem RPC.
+	CartServiceAddItemProcedure = "/cart.v1.CartService/AddItem"
+	// CartServiceRemoveItemProcedure is the fully-qualified name of the CartService's RemoveItem RPC.
+	CartServiceRemoveItemProcedure = "/cart.v1.CartService/RemoveItem"
+	// CartServiceSetItemQuantityProcedure is the fully-qualified name of the CartService's
+	// SetItemQuantity RPC.
+	CartServiceSetItemQuantityProcedure = "/cart.v1.CartService/SetItemQuantity"
+	// CartServiceCheckoutProcedure is the fully-qualified name of the CartService's Checkout RPC.
+	CartServiceCheckoutProcedure = "/cart.v1.CartService/Checkout"
+)
+
+// These variables are the protoreflect.Descriptor objects for the RPCs defined in this package.
+var (
+	cartServiceServiceDescriptor               = v1.File_cart_v1_cart_proto.Services().ByName("CartService")
+	cartServiceCreateCartMethodDescriptor      = cartServiceServiceDescriptor.Methods().ByName("CreateCart")
+	cartServiceGetCartMethodDescriptor         = cartServiceServiceDescriptor.Methods().ByName("GetCart")
+	cartServiceAddItemMethodDescriptor         = cartServiceServiceDescriptor.Methods().ByName("AddItem")
+	cartServiceRemoveItemMethodDescriptor      = cartServiceServiceDescriptor.Methods().ByName("RemoveItem")
+	cartServiceSetItemQuantityMethodDescriptor = cartServiceServiceDescriptor.Methods().ByName("SetItemQuantity")
+	cartServiceCheckoutMethodDescriptor        = cartServiceServiceDescriptor.Methods().ByName("Checkout")
+)
+
+// CartServiceClient is a client for the cart.v1.CartService service.
+type CartServiceClient interface {
+	CreateCart(context.Context, *connect.Request[v1.CreateCartRequest]) (*connect.Response[v1.CreateCartResponse], error)
+	// GetCart prices the cart at the current book prices.
+	GetCart(context.Context, *connect.Request[v1.GetCartRequest]) (*connect.Response[v1.GetCartResponse], error)
+	// AddItem adds quantity copies of a book to the cart.
+	AddItem(context.Context, *connect.Request[v1.AddItemRequest]) (*connect.Response[v1.AddItemResponse], error)
+	RemoveItem(context.Context, *connect.Request[v1.RemoveItemRequest]) (*connect.Response[v1.RemoveItemResponse], error)
+	// SetItemQuantity replaces the quantity of a book, 0 removes it.
+	SetItemQuantity(context.Context, *connect.Request[v1.SetItemQuantityRequest]) (*connect.Response[v1.SetItemQuantityResponse], error)
+	// Checkout prices the cart, creates the order and deletes the cart. The
+	// cart cannot change while it is checked out, and retrying a checkout that
+	// failed midway creates at most one order.
+	Checkout(context.Context, *connect.Request[v1.CheckoutRequest]) (*connect.Response[v1.CheckoutResponse], error)
+}
+
+// NewCartServiceClient constructs a client for the cart.v1.CartService service. By default, it uses
+// the Connect protocol with the binary Protobuf Codec, asks for gzipped responses, and sends
+// uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the connect.WithGRPC() or
+// connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewCartServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) CartServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	return &cartServiceClient{
+		createCart: connect.NewClient[v1.CreateCartRequest, v1.CreateCartResponse](
+			httpClient,
+			baseURL+CartServiceCreateCartProcedure,
+			connect.WithSchema(cartServiceCreateCartMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		getCart: connect.NewClient[v1.GetCartRequest, v1.GetCartResponse](
+			httpClient,
+			baseURL+CartServiceGetCartProcedure,
+			connect.WithSchema(cartServiceGetCartMethodDescriptor),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		addItem: connect.NewClient[v1.AddItemRequest, v1.AddItemResponse](
+			httpClient,
+			baseURL+CartServiceAddItemProcedure,
+			connect.WithSchema(cartServiceAddItemMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		removeItem: connect.NewClient[v1.RemoveItemRequest, v1.RemoveItemResponse](
+			httpClient,
+			baseURL+CartServiceRemoveItemProcedure,
+			connect.WithSchema(cartServiceRemoveItemMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		setItemQuantity: connect.NewClient[v1.SetItemQuantityRequest, v1.SetItemQuantityResponse](
+			httpClient,
+			baseURL+CartServiceSetItemQuantityProcedure,
+			connect.WithSchema(cartServiceSetItemQuantityMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		checkout: connect.NewClient[v1.CheckoutRequest, v1.CheckoutResponse](
+			httpClient,
+			baseURL+CartServiceCheckoutProcedure,
+			connect.WithSchema(cartServiceCheckoutMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// cartServiceClient implements CartServiceClient.
+type cartServiceClient struct {
+	createCart      *connect.Client[v1.CreateCartRequest, v1.CreateCartResponse]
+	getCart         *connect.Client[v1.GetCartRequest, v1.GetCartResponse]
+	addItem         *connect.Client[v1.AddItemRequest, v1.AddItemResponse]
+	removeItem      *connect.Client[v1.RemoveItemRequest, v1.RemoveItemResponse]
+	setItemQuantity *connect.Client[v1.SetItemQuantityRequest, v1.SetItemQuantityResponse]
+	checkout        *connect.Client[v1.CheckoutRequest, v1.CheckoutResponse]
+}
+
+// CreateCart calls cart.v1.CartService.CreateCart.
+func (c *cartServiceClient) CreateCart(ctx context.Context, req *connect.Request[v1.CreateCartRequest]) (*connect.Response[v1.CreateCartResponse], error) {
+	return c.createCart.CallUnary(ctx, req)
+}
+
+// GetCart calls cart.v1.CartService.GetCart.
+func (c *cartServiceClient) GetCart(ctx context.Context, req *connect.Request[v1.GetCartRequest]) (*connect.Response[v1.GetCartResponse], error) {
+	return c.getCart.CallUnary(ctx, req)
+}
+
+// AddItem calls cart.v1.CartService.AddItem.
+func (c *cartServiceClient) AddItem(ctx context.Context, req *connect.Request[v1.AddItemRequest]) (*connect.Response[v1.AddItemResponse], error) {
+	return c.addItem.CallUnary(ctx, req)
+}
+
+// RemoveItem calls cart.v1.CartService.RemoveItem.
+func (c *cartServiceClient) RemoveItem(ctx context.Context, req *connect.Request[v1.RemoveItemRequest]) (*connect.Response[v1.RemoveItemResponse], error) {
+	return c.removeItem.CallUnary(ctx, req)
+}
+
+// SetItemQuantity calls cart.v1.CartService.SetItemQuantity.
+func (c *cartServiceClient) SetItemQuantity(ctx context.Context, req *connect.Request[v1.SetItemQuantityRequest]) (*connect.Response[v1.SetItemQuantityResponse], error) {
+	return c.setItemQuantity.CallUnary(ctx, req)
+}
+
+// Checkout calls cart.v1.CartService.Checkout.
+func (c *cartServiceClient) Checkout(ctx context.Context, req *connect.Request[v1.CheckoutRequest]) (*connect.Response[v1.CheckoutResponse], error) {
+	return c.checkout.CallUnary(ctx, req)
+}
+
+// CartServiceHandler is an implementation of the cart.v1.CartService service.
+type CartServiceHandler interface {
+	CreateCart(context.Context, *connect.Request[v1.CreateCartRequest]) (*connect.Response[v1.CreateCartResponse], error)
+	// GetCart prices the cart at the current book prices.
+	GetCart(context.Context, *connect.Request[v1.GetCartRequest]) (*connect.Response[v1.GetCartResponse], error)
+	// AddItem adds quantity copies of a book to the cart.
+	AddItem(context.Context, *connect.Request[v1.AddItemRequest]) (*connect.Response[v1.AddItemResponse], error)
+	RemoveItem(context.Context, *connect.Request[v1.RemoveItemRequest]) (*connect.Response[v1.RemoveItemResponse], error)
+	// SetItemQuantity replaces the quantity of a book, 0 removes it.
+	SetItemQuantity(context.Context, *connect.Request[v1.SetItemQuantityRequest]) (*connect.Response[v1.SetItemQuantityResponse], error)
+	// Checkout prices the cart, creates the order and deletes the cart. The
+	// cart cannot change while it is checked out, and retrying a checkout that
+	// failed midway creates at most one order.
+	Checkout(context.Context, *connect.Request[v1.CheckoutRequest]) (*connect.Response[v1.CheckoutResponse], error)
+}
+
+// NewCartServiceHandler builds an HTTP handler from the service implementation. It returns the path
+// on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewCartServiceHandler(svc CartServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	cartServiceCreateCartHandler := connect.NewUnaryHandler(
+		CartServiceCreateCartProcedure,
+		svc.CreateCart,
+		connect.WithSchema(cartServiceCreateCartMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	cartServiceGetCartHandler := connect.NewUnaryHandler(
+		CartServiceGetCartProcedure,
+		svc.GetCart,
+		connect.WithSchema(cartServiceGetCartMethodDescriptor),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	cartServiceAddItemHandler := connect.NewUnaryHandler(
+		CartServiceAddItemProcedure,
+		svc.AddItem,
+		connect.WithSchema(cartServiceAddItemMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	cartServiceRemoveItemHandler := connect.NewUnaryHandler(
+		CartServiceRemoveItemProcedure,
+		svc.RemoveItem,
+		connect.WithSchema(cartServiceRemoveItemMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	cartServiceSetItemQuantityHandler := connect.NewUnaryHandler(
+		CartServiceSetItemQuantityProcedure,
+		svc.SetItemQuantity,
+		connect.WithSchema(cartServiceSetItemQuantityMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	cartServiceCheckoutHandler := connect.NewUnaryHandler(
+		CartServiceCheckoutProcedure,
+		svc.Checkout,
+		connect.WithSchema(cartServiceCheckoutMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/cart.v1.CartService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case CartServiceCreateCartProcedure:
+			cartServiceCreateCartHandler.ServeHTTP(w, r)
+		case CartServiceGetCartProcedure:
+			cartServiceGetCartHandler.ServeHTTP(w, r)
+		case CartServiceAddItemProcedure:
+			cartServiceAddItemHandler.ServeHTTP(w, r)
+		case CartServiceRemoveItemProcedure:
+			cartServiceRemoveItemHandler.ServeHTTP(w, r)
+		case CartServiceSetItemQuantityProcedure:
+			cartServiceSetItemQuantityHandler.ServeHTTP(w, r)
+		case CartServiceCheckoutProcedure:
+			cartServiceCheckoutHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedCartServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedCartServiceHandler struct{}
+
+func (UnimplementedCartServiceHandler) CreateCart(context.Context, *connect.Request[v1.CreateCartRequest]) (*connect.Response[v1.CreateCartResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("cart.v1.CartService.CreateCart is not implemented"))
+}
+
+func (UnimplementedCartServiceHandler) GetCart(context.Context, *connect.Request[v1.GetCartRequest]) (*connect.Response[v1.GetCartResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("cart.v1.CartService.GetCart is not implemented"))
+}
+
+func (UnimplementedCartServiceHandler) AddItem(context.Context, *connect.Request[v1.AddItemRequest]) (*connect.Response[v1.AddItemResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("cart.v1.CartService.AddItem is not implemented"))
+}
+
+func (UnimplementedCartServiceHandler) RemoveItem(context.Context, *connect.Request[v1.RemoveItemRequest]) (*connect.Response[v1.RemoveItemResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("cart.v1.CartService.RemoveItem is not implemented"))
+}
+
+func (UnimplementedCartServiceHandler) SetItemQuantity(context.Context, *connect.Request[v1.SetItemQuantityRequest]) (*connect.Response[v1.SetItemQuantityResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("cart.v1.CartService.SetItemQuantity is not implemented"))
+}
+
+func (UnimplementedCartServiceHandler) Checkout(context.Context, *connect.Request[v1.CheckoutRequest]) (*connect.Response[v1.CheckoutResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("cart.v1.CartService.Checkout is not implemented"))
+}
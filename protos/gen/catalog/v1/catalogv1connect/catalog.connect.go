@@ -0,0 +1,149 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: catalog/v1/catalog.proto
+
+package catalogv1connect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	v1 "github.com/iho/bookstore/protos/gen/catalog/v1"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// CatalogServiceName is the fully-qualified name of the CatalogService service.
+	CatalogServiceName = "catalog.v1.CatalogService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// CatalogServiceImportCatalogProcedure is the fully-qualified name of the CatalogService's
+	// ImportCatalog RPC.
+	CatalogServiceImportCatalogProcedure = "/catalog.v1.CatalogService/ImportCatalog"
+	// CatalogServiceExportCatalogProcedure is the fully-qualified name of the CatalogService's
+	// ExportCatalog RPC.
+	CatalogServiceExportCatalogProcedure = "/catalog.v1.CatalogService/ExportCatalog"
+)
+
+// These variables are the protoreflect.Descriptor objects for the RPCs defined in this package.
+var (
+	catalogServiceServiceDescriptor             = v1.File_catalog_v1_catalog_proto.Services().ByName("CatalogService")
+	catalogServiceImportCatalogMethodDescriptor = catalogServiceServiceDescriptor.Methods().ByName("ImportCatalog")
+	catalogServiceExportCatalogMethodDescriptor = catalogServiceServiceDescriptor.Methods().ByName("ExportCatalog")
+)
+
+// CatalogServiceClient is a client for the catalog.v1.CatalogService service.
+type CatalogServiceClient interface {
+	// ImportCatalog reads a stream of author and book records and creates them.
+	// Rows that fail validation are reported in the response and skipped.
+	ImportCatalog(context.Context) *connect.ClientStreamForClient[v1.ImportCatalogRequest, v1.ImportCatalogResponse]
+	// ExportCatalog streams every author followed by every book.
+	ExportCatalog(context.Context, *connect.Request[v1.ExportCatalogRequest]) (*connect.ServerStreamForClient[v1.ExportCatalogResponse], error)
+}
+
+// NewCatalogServiceClient constructs a client for the catalog.v1.CatalogService service. By
+// default, it uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses,
+// and sends uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the
+// connect.WithGRPC() or connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewCatalogServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) CatalogServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	return &catalogServiceClient{
+		importCatalog: connect.NewClient[v1.ImportCatalogRequest, v1.ImportCatalogResponse](
+			httpClient,
+			baseURL+CatalogServiceImportCatalogProcedure,
+			connect.WithSchema(catalogServiceImportCatalogMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		exportCatalog: connect.NewClient[v1.ExportCatalogRequest, v1.ExportCatalogResponse](
+			httpClient,
+			baseURL+CatalogServiceExportCatalogProcedure,
+			connect.WithSchema(catalogServiceExportCatalogMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// catalogServiceClient implements CatalogServiceClient.
+type catalogServiceClient struct {
+	importCatalog *connect.Client[v1.ImportCatalogRequest, v1.ImportCatalogResponse]
+	exportCatalog *connect.Client[v1.ExportCatalogRequest, v1.ExportCatalogResponse]
+}
+
+// ImportCatalog calls catalog.v1.CatalogService.ImportCatalog.
+func (c *catalogServiceClient) ImportCatalog(ctx context.Context) *connect.ClientStreamForClient[v1.ImportCatalogRequest, v1.ImportCatalogResponse] {
+	return c.importCatalog.CallClientStream(ctx)
+}
+
+// ExportCatalog calls catalog.v1.CatalogService.ExportCatalog.
+func (c *catalogServiceClient) ExportCatalog(ctx context.Context, req *connect.Request[v1.ExportCatalogRequest]) (*connect.ServerStreamForClient[v1.ExportCatalogResponse], error) {
+	return c.exportCatalog.CallServerStream(ctx, req)
+}
+
+// CatalogServiceHandler is an implementation of the catalog.v1.CatalogService service.
+type CatalogServiceHandler interface {
+	// ImportCatalog reads a stream of author and book records and creates them.
+	// Rows that fail validation are reported in the response and skipped.
+	ImportCatalog(context.Context, *connect.ClientStream[v1.ImportCatalogRequest]) (*connect.Response[v1.ImportCatalogResponse], error)
+	// ExportCatalog streams every author followed by every book.
+	ExportCatalog(context.Context, *connect.Request[v1.ExportCatalogRequest], *connect.ServerStream[v1.ExportCatalogResponse]) error
+}
+
+// NewCatalogServiceHandler builds an HTTP handler from the service implementation. It returns the
+// path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewCatalogServiceHandler(svc CatalogServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	catalogServiceImportCatalogHandler := connect.NewClientStreamHandler(
+		CatalogServiceImportCatalogProcedure,
+		svc.ImportCatalog,
+		connect.WithSchema(catalogServiceImportCatalogMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	catalogServiceExportCatalogHandler := connect.NewServerStreamHandler(
+		CatalogServiceExportCatalogProcedure,
+		svc.ExportCatalog,
+		connect.WithSchema(catalogServiceExportCatalogMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/catalog.v1.CatalogService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case CatalogServiceImportCatalogProcedure:
+			catalogServiceImportCatalogHandler.ServeHTTP(w, r)
+		case CatalogServiceExportCatalogProcedure:
+			catalogServiceExportCatalogHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedCatalogServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedCatalogServiceHandler struct{}
+
+func (UnimplementedCatalogServiceHandler) ImportCatalog(context.Context, *connect.ClientStream[v1.ImportCatalogRequest]) (*connect.Response[v1.ImportCatalogResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("catalog.v1.CatalogService.ImportCatalog is not implemented"))
+}
+
+func (UnimplementedCatalogServiceHandler) ExportCatalog(context.Context, *connect.Request[v1.ExportCatalogRequest], *connect.ServerStream[v1.ExportCatalogResponse]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("catalog.v1.CatalogService.ExportCatalog is not implemented"))
+}
@@ -38,6 +38,9 @@ const (
 	OrdersServiceListOrdersProcedure = "/orders.v1.OrdersService/ListOrders"
 	// OrdersServiceGetOrderProcedure is the fully-qualified name of the OrdersService's GetOrder RPC.
 	OrdersServiceGetOrderProcedure = "/orders.v1.OrdersService/GetOrder"
+	// OrdersServicePriceOrderProcedure is the fully-qualified name of the OrdersService's PriceOrder
+	// RPC.
+	OrdersServicePriceOrderProcedure = "/orders.v1.OrdersService/PriceOrder"
 	// OrdersServiceCreateOrderProcedure is the fully-qualified name of the OrdersService's CreateOrder
 	// RPC.
 	OrdersServiceCreateOrderProcedure = "/orders.v1.OrdersService/CreateOrder"
@@ -47,25 +50,115 @@ const (
 	// OrdersServiceDeleteOrderProcedure is the fully-qualified name of the OrdersService's DeleteOrder
 	// RPC.
 	OrdersServiceDeleteOrderProcedure = "/orders.v1.OrdersService/DeleteOrder"
+	// OrdersServiceWatchOrdersProcedure is the fully-qualified name of the OrdersService's WatchOrders
+	// RPC.
+	OrdersServiceWatchOrdersProcedure = "/orders.v1.OrdersService/WatchOrders"
+	// OrdersServiceAuthorizePaymentProcedure is the fully-qualified name of the OrdersService's
+	// AuthorizePayment RPC.
+	OrdersServiceAuthorizePaymentProcedure = "/orders.v1.OrdersService/AuthorizePayment"
+	// OrdersServiceCapturePaymentProcedure is the fully-qualified name of the OrdersService's
+	// CapturePayment RPC.
+	OrdersServiceCapturePaymentProcedure = "/orders.v1.OrdersService/CapturePayment"
+	// OrdersServiceRefundPaymentProcedure is the fully-qualified name of the OrdersService's
+	// RefundPayment RPC.
+	OrdersServiceRefundPaymentProcedure = "/orders.v1.OrdersService/RefundPayment"
+	// OrdersServiceVoidPaymentProcedure is the fully-qualified name of the OrdersService's VoidPayment
+	// RPC.
+	OrdersServiceVoidPaymentProcedure = "/orders.v1.OrdersService/VoidPayment"
+	// OrdersServiceCreateShipmentProcedure is the fully-qualified name of the OrdersService's
+	// CreateShipment RPC.
+	OrdersServiceCreateShipmentProcedure = "/orders.v1.OrdersService/CreateShipment"
+	// OrdersServiceDeliverShipmentProcedure is the fully-qualified name of the OrdersService's
+	// DeliverShipment RPC.
+	OrdersServiceDeliverShipmentProcedure = "/orders.v1.OrdersService/DeliverShipment"
+	// OrdersServiceListBestSellingBooksProcedure is the fully-qualified name of the OrdersService's
+	// ListBestSellingBooks RPC.
+	OrdersServiceListBestSellingBooksProcedure = "/orders.v1.OrdersService/ListBestSellingBooks"
+	// OrdersServiceListBestSellingAuthorsProcedure is the fully-qualified name of the OrdersService's
+	// ListBestSellingAuthors RPC.
+	OrdersServiceListBestSellingAuthorsProcedure = "/orders.v1.OrdersService/ListBestSellingAuthors"
+	// OrdersServiceGetRevenueProcedure is the fully-qualified name of the OrdersService's GetRevenue
+	// RPC.
+	OrdersServiceGetRevenueProcedure = "/orders.v1.OrdersService/GetRevenue"
+	// OrdersServiceGetOrderStatsProcedure is the fully-qualified name of the OrdersService's
+	// GetOrderStats RPC.
+	OrdersServiceGetOrderStatsProcedure = "/orders.v1.OrdersService/GetOrderStats"
+	// OrdersServiceExportSalesReportProcedure is the fully-qualified name of the OrdersService's
+	// ExportSalesReport RPC.
+	OrdersServiceExportSalesReportProcedure = "/orders.v1.OrdersService/ExportSalesReport"
 )
 
 // These variables are the protoreflect.Descriptor objects for the RPCs defined in this package.
 var (
-	ordersServiceServiceDescriptor           = v1.File_orders_v1_orders_proto.Services().ByName("OrdersService")
-	ordersServiceListOrdersMethodDescriptor  = ordersServiceServiceDescriptor.Methods().ByName("ListOrders")
-	ordersServiceGetOrderMethodDescriptor    = ordersServiceServiceDescriptor.Methods().ByName("GetOrder")
-	ordersServiceCreateOrderMethodDescriptor = ordersServiceServiceDescriptor.Methods().ByName("CreateOrder")
-	ordersServiceUpdateOrderMethodDescriptor = ordersServiceServiceDescriptor.Methods().ByName("UpdateOrder")
-	ordersServiceDeleteOrderMethodDescriptor = ordersServiceServiceDescriptor.Methods().ByName("DeleteOrder")
+	ordersServiceServiceDescriptor                      = v1.File_orders_v1_orders_proto.Services().ByName("OrdersService")
+	ordersServiceListOrdersMethodDescriptor             = ordersServiceServiceDescriptor.Methods().ByName("ListOrders")
+	ordersServiceGetOrderMethodDescriptor               = ordersServiceServiceDescriptor.Methods().ByName("GetOrder")
+	ordersServicePriceOrderMethodDescriptor             = ordersServiceServiceDescriptor.Methods().ByName("PriceOrder")
+	ordersServiceCreateOrderMethodDescriptor            = ordersServiceServiceDescriptor.Methods().ByName("CreateOrder")
+	ordersServiceUpdateOrderMethodDescriptor            = ordersServiceServiceDescriptor.Methods().ByName("UpdateOrder")
+	ordersServiceDeleteOrderMethodDescriptor            = ordersServiceServiceDescriptor.Methods().ByName("DeleteOrder")
+	ordersServiceWatchOrdersMethodDescriptor            = ordersServiceServiceDescriptor.Methods().ByName("WatchOrders")
+	ordersServiceAuthorizePaymentMethodDescriptor       = ordersServiceServiceDescriptor.Methods().ByName("AuthorizePayment")
+	ordersServiceCapturePaymentMethodDescriptor         = ordersServiceServiceDescriptor.Methods().ByName("CapturePayment")
+	ordersServiceRefundPaymentMethodDescriptor          = ordersServiceServiceDescriptor.Methods().ByName("RefundPayment")
+	ordersServiceVoidPaymentMethodDescriptor            = ordersServiceServiceDescriptor.Methods().ByName("VoidPayment")
+	ordersServiceCreateShipmentMethodDescriptor         = ordersServiceServiceDescriptor.Methods().ByName("CreateShipment")
+	ordersServiceDeliverShipmentMethodDescriptor        = ordersServiceServiceDescriptor.Methods().ByName("DeliverShipment")
+	ordersServiceListBestSellingBooksMethodDescriptor   = ordersServiceServiceDescriptor.Methods().ByName("ListBestSellingBooks")
+	ordersServiceListBestSellingAuthorsMethodDescriptor = ordersServiceServiceDescriptor.Methods().ByName("ListBestSellingAuthors")
+	ordersServiceGetRevenueMethodDescriptor             = ordersServiceServiceDescriptor.Methods().ByName("GetRevenue")
+	ordersServiceGetOrderStatsMethodDescriptor          = ordersServiceServiceDescriptor.Methods().ByName("GetOrderStats")
+	ordersServiceExportSalesReportMethodDescriptor      = ordersServiceServiceDescriptor.Methods().ByName("ExportSalesReport")
 )
 
 // OrdersServiceClient is a client for the orders.v1.OrdersService service.
 type OrdersServiceClient interface {
+	// ListOrders pages through the orders matching the filters of the
+	// request, newest first unless sorted otherwise.
 	ListOrders(context.Context, *connect.Request[v1.ListOrdersRequest]) (*connect.Response[v1.ListOrdersResponse], error)
 	GetOrder(context.Context, *connect.Request[v1.GetOrderRequest]) (*connect.Response[v1.GetOrderResponse], error)
+	// PriceOrder prices order lines like CreateOrder would, without creating
+	// an order.
+	PriceOrder(context.Context, *connect.Request[v1.PriceOrderRequest]) (*connect.Response[v1.PriceOrderResponse], error)
+	// CreateOrder prices the order from the book prices and promotions.
 	CreateOrder(context.Context, *connect.Request[v1.CreateOrderRequest]) (*connect.Response[v1.CreateOrderResponse], error)
 	UpdateOrder(context.Context, *connect.Request[v1.UpdateOrderRequest]) (*connect.Response[v1.UpdateOrderResponse], error)
+	// DeleteOrder only deletes orders pending payment, whose payment failed or
+	// that were cancelled. Other orders are voided or refunded first.
 	DeleteOrder(context.Context, *connect.Request[v1.DeleteOrderRequest]) (*connect.Response[v1.DeleteOrderResponse], error)
+	WatchOrders(context.Context, *connect.Request[v1.WatchOrdersRequest]) (*connect.ServerStreamForClient[v1.WatchOrdersResponse], error)
+	// AuthorizePayment reserves the total price of a pending order with the
+	// payment provider. Declines are recorded on the order, they are not
+	// errors. When the provider's answer is lost the call fails with
+	// Unavailable and may be retried, or the outcome arrives by webhook.
+	AuthorizePayment(context.Context, *connect.Request[v1.AuthorizePaymentRequest]) (*connect.Response[v1.AuthorizePaymentResponse], error)
+	// CapturePayment collects the authorized amount of an order.
+	CapturePayment(context.Context, *connect.Request[v1.CapturePaymentRequest]) (*connect.Response[v1.CapturePaymentResponse], error)
+	// RefundPayment pays the captured amount of an order back.
+	RefundPayment(context.Context, *connect.Request[v1.RefundPaymentRequest]) (*connect.Response[v1.RefundPaymentResponse], error)
+	// VoidPayment releases the authorization of an order, cancelling it.
+	VoidPayment(context.Context, *connect.Request[v1.VoidPaymentRequest]) (*connect.Response[v1.VoidPaymentResponse], error)
+	// CreateShipment records that some or all of the unshipped books of a paid
+	// order left the warehouse. The order is shipped once every book is, and
+	// partially shipped before. It creates at most one shipment per
+	// Idempotency-Key header.
+	CreateShipment(context.Context, *connect.Request[v1.CreateShipmentRequest]) (*connect.Response[v1.CreateShipmentResponse], error)
+	// DeliverShipment records that a shipment arrived. The order is delivered
+	// once it is shipped and every shipment arrived. Delivering a shipment
+	// again leaves it alone.
+	DeliverShipment(context.Context, *connect.Request[v1.DeliverShipmentRequest]) (*connect.Response[v1.DeliverShipmentResponse], error)
+	// ListBestSellingBooks ranks the books by the quantity sold.
+	ListBestSellingBooks(context.Context, *connect.Request[v1.ListBestSellingBooksRequest]) (*connect.Response[v1.ListBestSellingBooksResponse], error)
+	// ListBestSellingAuthors ranks the authors by the quantity of their books
+	// sold.
+	ListBestSellingAuthors(context.Context, *connect.Request[v1.ListBestSellingAuthorsRequest]) (*connect.Response[v1.ListBestSellingAuthorsResponse], error)
+	// GetRevenue sums the orders per day, week or month.
+	GetRevenue(context.Context, *connect.Request[v1.GetRevenueRequest]) (*connect.Response[v1.GetRevenueResponse], error)
+	// GetOrderStats returns the average order value and how many books the
+	// orders have.
+	GetOrderStats(context.Context, *connect.Request[v1.GetOrderStatsRequest]) (*connect.Response[v1.GetOrderStatsResponse], error)
+	// ExportSalesReport renders one of the reports as CSV.
+	ExportSalesReport(context.Context, *connect.Request[v1.ExportSalesReportRequest]) (*connect.Response[v1.ExportSalesReportResponse], error)
 }
 
 // NewOrdersServiceClient constructs a client for the orders.v1.OrdersService service. By default,
@@ -82,12 +175,21 @@ func NewOrdersServiceClient(httpClient connect.HTTPClient, baseURL string, opts
 			httpClient,
 			baseURL+OrdersServiceListOrdersProcedure,
 			connect.WithSchema(ordersServiceListOrdersMethodDescriptor),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
 			connect.WithClientOptions(opts...),
 		),
 		getOrder: connect.NewClient[v1.GetOrderRequest, v1.GetOrderResponse](
 			httpClient,
 			baseURL+OrdersServiceGetOrderProcedure,
 			connect.WithSchema(ordersServiceGetOrderMethodDescriptor),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		priceOrder: connect.NewClient[v1.PriceOrderRequest, v1.PriceOrderResponse](
+			httpClient,
+			baseURL+OrdersServicePriceOrderProcedure,
+			connect.WithSchema(ordersServicePriceOrderMethodDescriptor),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
 			connect.WithClientOptions(opts...),
 		),
 		createOrder: connect.NewClient[v1.CreateOrderRequest, v1.CreateOrderResponse](
@@ -108,16 +210,106 @@ func NewOrdersServiceClient(httpClient connect.HTTPClient, baseURL string, opts
 			connect.WithSchema(ordersServiceDeleteOrderMethodDescriptor),
 			connect.WithClientOptions(opts...),
 		),
+		watchOrders: connect.NewClient[v1.WatchOrdersRequest, v1.WatchOrdersResponse](
+			httpClient,
+			baseURL+OrdersServiceWatchOrdersProcedure,
+			connect.WithSchema(ordersServiceWatchOrdersMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		authorizePayment: connect.NewClient[v1.AuthorizePaymentRequest, v1.AuthorizePaymentResponse](
+			httpClient,
+			baseURL+OrdersServiceAuthorizePaymentProcedure,
+			connect.WithSchema(ordersServiceAuthorizePaymentMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		capturePayment: connect.NewClient[v1.CapturePaymentRequest, v1.CapturePaymentResponse](
+			httpClient,
+			baseURL+OrdersServiceCapturePaymentProcedure,
+			connect.WithSchema(ordersServiceCapturePaymentMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		refundPayment: connect.NewClient[v1.RefundPaymentRequest, v1.RefundPaymentResponse](
+			httpClient,
+			baseURL+OrdersServiceRefundPaymentProcedure,
+			connect.WithSchema(ordersServiceRefundPaymentMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		voidPayment: connect.NewClient[v1.VoidPaymentRequest, v1.VoidPaymentResponse](
+			httpClient,
+			baseURL+OrdersServiceVoidPaymentProcedure,
+			connect.WithSchema(ordersServiceVoidPaymentMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		createShipment: connect.NewClient[v1.CreateShipmentRequest, v1.CreateShipmentResponse](
+			httpClient,
+			baseURL+OrdersServiceCreateShipmentProcedure,
+			connect.WithSchema(ordersServiceCreateShipmentMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		deliverShipment: connect.NewClient[v1.DeliverShipmentRequest, v1.DeliverShipmentResponse](
+			httpClient,
+			baseURL+OrdersServiceDeliverShipmentProcedure,
+			connect.WithSchema(ordersServiceDeliverShipmentMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		listBestSellingBooks: connect.NewClient[v1.ListBestSellingBooksRequest, v1.ListBestSellingBooksResponse](
+			httpClient,
+			baseURL+OrdersServiceListBestSellingBooksProcedure,
+			connect.WithSchema(ordersServiceListBestSellingBooksMethodDescriptor),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		listBestSellingAuthors: connect.NewClient[v1.ListBestSellingAuthorsRequest, v1.ListBestSellingAuthorsResponse](
+			httpClient,
+			baseURL+OrdersServiceListBestSellingAuthorsProcedure,
+			connect.WithSchema(ordersServiceListBestSellingAuthorsMethodDescriptor),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		getRevenue: connect.NewClient[v1.GetRevenueRequest, v1.GetRevenueResponse](
+			httpClient,
+			baseURL+OrdersServiceGetRevenueProcedure,
+			connect.WithSchema(ordersServiceGetRevenueMethodDescriptor),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		getOrderStats: connect.NewClient[v1.GetOrderStatsRequest, v1.GetOrderStatsResponse](
+			httpClient,
+			baseURL+OrdersServiceGetOrderStatsProcedure,
+			connect.WithSchema(ordersServiceGetOrderStatsMethodDescriptor),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		exportSalesReport: connect.NewClient[v1.ExportSalesReportRequest, v1.ExportSalesReportResponse](
+			httpClient,
+			baseURL+OrdersServiceExportSalesReportProcedure,
+			connect.WithSchema(ordersServiceExportSalesReportMethodDescriptor),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
 	}
 }
 
 // ordersServiceClient implements OrdersServiceClient.
 type ordersServiceClient struct {
-	listOrders  *connect.Client[v1.ListOrdersRequest, v1.ListOrdersResponse]
-	getOrder    *connect.Client[v1.GetOrderRequest, v1.GetOrderResponse]
-	createOrder *connect.Client[v1.CreateOrderRequest, v1.CreateOrderResponse]
-	updateOrder *connect.Client[v1.UpdateOrderRequest, v1.UpdateOrderResponse]
-	deleteOrder *connect.Client[v1.DeleteOrderRequest, v1.DeleteOrderResponse]
+	listOrders             *connect.Client[v1.ListOrdersRequest, v1.ListOrdersResponse]
+	getOrder               *connect.Client[v1.GetOrderRequest, v1.GetOrderResponse]
+	priceOrder             *connect.Client[v1.PriceOrderRequest, v1.PriceOrderResponse]
+	createOrder            *connect.Client[v1.CreateOrderRequest, v1.CreateOrderResponse]
+	updateOrder            *connect.Client[v1.UpdateOrderRequest, v1.UpdateOrderResponse]
+	deleteOrder            *connect.Client[v1.DeleteOrderRequest, v1.DeleteOrderResponse]
+	watchOrders            *connect.Client[v1.WatchOrdersRequest, v1.WatchOrdersResponse]
+	authorizePayment       *connect.Client[v1.AuthorizePaymentRequest, v1.AuthorizePaymentResponse]
+	capturePayment         *connect.Client[v1.CapturePaymentRequest, v1.CapturePaymentResponse]
+	refundPayment          *connect.Client[v1.RefundPaymentRequest, v1.RefundPaymentResponse]
+	voidPayment            *connect.Client[v1.VoidPaymentRequest, v1.VoidPaymentResponse]
+	createShipment         *connect.Client[v1.CreateShipmentRequest, v1.CreateShipmentResponse]
+	deliverShipment        *connect.Client[v1.DeliverShipmentRequest, v1.DeliverShipmentResponse]
+	listBestSellingBooks   *connect.Client[v1.ListBestSellingBooksRequest, v1.ListBestSellingBooksResponse]
+	listBestSellingAuthors *connect.Client[v1.ListBestSellingAuthorsRequest, v1.ListBestSellingAuthorsResponse]
+	getRevenue             *connect.Client[v1.GetRevenueRequest, v1.GetRevenueResponse]
+	getOrderStats          *connect.Client[v1.GetOrderStatsRequest, v1.GetOrderStatsResponse]
+	exportSalesReport      *connect.Client[v1.ExportSalesReportRequest, v1.ExportSalesReportResponse]
 }
 
 // ListOrders calls orders.v1.OrdersService.ListOrders.
@@ -130,6 +322,11 @@ func (c *ordersServiceClient) GetOrder(ctx context.Context, req *connect.Request
 	return c.getOrder.CallUnary(ctx, req)
 }
 
+// PriceOrder calls orders.v1.OrdersService.PriceOrder.
+func (c *ordersServiceClient) PriceOrder(ctx context.Context, req *connect.Request[v1.PriceOrderRequest]) (*connect.Response[v1.PriceOrderResponse], error) {
+	return c.priceOrder.CallUnary(ctx, req)
+}
+
 // CreateOrder calls orders.v1.OrdersService.CreateOrder.
 func (c *ordersServiceClient) CreateOrder(ctx context.Context, req *connect.Request[v1.CreateOrderRequest]) (*connect.Response[v1.CreateOrderResponse], error) {
 	return c.createOrder.CallUnary(ctx, req)
@@ -145,13 +342,114 @@ func (c *ordersServiceClient) DeleteOrder(ctx context.Context, req *connect.Requ
 	return c.deleteOrder.CallUnary(ctx, req)
 }
 
+// WatchOrders calls orders.v1.OrdersService.WatchOrders.
+func (c *ordersServiceClient) WatchOrders(ctx context.Context, req *connect.Request[v1.WatchOrdersRequest]) (*connect.ServerStreamForClient[v1.WatchOrdersResponse], error) {
+	return c.watchOrders.CallServerStream(ctx, req)
+}
+
+// AuthorizePayment calls orders.v1.OrdersService.AuthorizePayment.
+func (c *ordersServiceClient) AuthorizePayment(ctx context.Context, req *connect.Request[v1.AuthorizePaymentRequest]) (*connect.Response[v1.AuthorizePaymentResponse], error) {
+	return c.authorizePayment.CallUnary(ctx, req)
+}
+
+// CapturePayment calls orders.v1.OrdersService.CapturePayment.
+func (c *ordersServiceClient) CapturePayment(ctx context.Context, req *connect.Request[v1.CapturePaymentRequest]) (*connect.Response[v1.CapturePaymentResponse], error) {
+	return c.capturePayment.CallUnary(ctx, req)
+}
+
+// RefundPayment calls orders.v1.OrdersService.RefundPayment.
+func (c *ordersServiceClient) RefundPayment(ctx context.Context, req *connect.Request[v1.RefundPaymentRequest]) (*connect.Response[v1.RefundPaymentResponse], error) {
+	return c.refundPayment.CallUnary(ctx, req)
+}
+
+// VoidPayment calls orders.v1.OrdersService.VoidPayment.
+func (c *ordersServiceClient) VoidPayment(ctx context.Context, req *connect.Request[v1.VoidPaymentRequest]) (*connect.Response[v1.VoidPaymentResponse], error) {
+	return c.voidPayment.CallUnary(ctx, req)
+}
+
+// CreateShipment calls orders.v1.OrdersService.CreateShipment.
+func (c *ordersServiceClient) CreateShipment(ctx context.Context, req *connect.Request[v1.CreateShipmentRequest]) (*connect.Response[v1.CreateShipmentResponse], error) {
+	return c.createShipment.CallUnary(ctx, req)
+}
+
+// DeliverShipment calls orders.v1.OrdersService.DeliverShipment.
+func (c *ordersServiceClient) DeliverShipment(ctx context.Context, req *connect.Request[v1.DeliverShipmentRequest]) (*connect.Response[v1.DeliverShipmentResponse], error) {
+	return c.deliverShipment.CallUnary(ctx, req)
+}
+
+// ListBestSellingBooks calls orders.v1.OrdersService.ListBestSellingBooks.
+func (c *ordersServiceClient) ListBestSellingBooks(ctx context.Context, req *connect.Request[v1.ListBestSellingBooksRequest]) (*connect.Response[v1.ListBestSellingBooksResponse], error) {
+	return c.listBestSellingBooks.CallUnary(ctx, req)
+}
+
+// ListBestSellingAuthors calls orders.v1.OrdersService.ListBestSellingAuthors.
+func (c *ordersServiceClient) ListBestSellingAuthors(ctx context.Context, req *connect.Request[v1.ListBestSellingAuthorsRequest]) (*connect.Response[v1.ListBestSellingAuthorsResponse], error) {
+	return c.listBestSellingAuthors.CallUnary(ctx, req)
+}
+
+// GetRevenue calls orders.v1.OrdersService.GetRevenue.
+func (c *ordersServiceClient) GetRevenue(ctx context.Context, req *connect.Request[v1.GetRevenueRequest]) (*connect.Response[v1.GetRevenueResponse], error) {
+	return c.getRevenue.CallUnary(ctx, req)
+}
+
+// GetOrderStats calls orders.v1.OrdersService.GetOrderStats.
+func (c *ordersServiceClient) GetOrderStats(ctx context.Context, req *connect.Request[v1.GetOrderStatsRequest]) (*connect.Response[v1.GetOrderStatsResponse], error) {
+	return c.getOrderStats.CallUnary(ctx, req)
+}
+
+// ExportSalesReport calls orders.v1.OrdersService.ExportSalesReport.
+func (c *ordersServiceClient) ExportSalesReport(ctx context.Context, req *connect.Request[v1.ExportSalesReportRequest]) (*connect.Response[v1.ExportSalesReportResponse], error) {
+	return c.exportSalesReport.CallUnary(ctx, req)
+}
+
 // OrdersServiceHandler is an implementation of the orders.v1.OrdersService service.
 type OrdersServiceHandler interface {
+	// ListOrders pages through the orders matching the filters of the
+	// request, newest first unless sorted otherwise.
 	ListOrders(context.Context, *connect.Request[v1.ListOrdersRequest]) (*connect.Response[v1.ListOrdersResponse], error)
 	GetOrder(context.Context, *connect.Request[v1.GetOrderRequest]) (*connect.Response[v1.GetOrderResponse], error)
+	// PriceOrder prices order lines like CreateOrder would, without creating
+	// an order.
+	PriceOrder(context.Context, *connect.Request[v1.PriceOrderRequest]) (*connect.Response[v1.PriceOrderResponse], error)
+	// CreateOrder prices the order from the book prices and promotions.
 	CreateOrder(context.Context, *connect.Request[v1.CreateOrderRequest]) (*connect.Response[v1.CreateOrderResponse], error)
 	UpdateOrder(context.Context, *connect.Request[v1.UpdateOrderRequest]) (*connect.Response[v1.UpdateOrderResponse], error)
+	// DeleteOrder only deletes orders pending payment, whose payment failed or
+	// that were cancelled. Other orders are voided or refunded first.
 	DeleteOrder(context.Context, *connect.Request[v1.DeleteOrderRequest]) (*connect.Response[v1.DeleteOrderResponse], error)
+	WatchOrders(context.Context, *connect.Request[v1.WatchOrdersRequest], *connect.ServerStream[v1.WatchOrdersResponse]) error
+	// AuthorizePayment reserves the total price of a pending order with the
+	// payment provider. Declines are recorded on the order, they are not
+	// errors. When the provider's answer is lost the call fails with
+	// Unavailable and may be retried, or the outcome arrives by webhook.
+	AuthorizePayment(context.Context, *connect.Request[v1.AuthorizePaymentRequest]) (*connect.Response[v1.AuthorizePaymentResponse], error)
+	// CapturePayment collects the authorized amount of an order.
+	CapturePayment(context.Context, *connect.Request[v1.CapturePaymentRequest]) (*connect.Response[v1.CapturePaymentResponse], error)
+	// RefundPayment pays the captured amount of an order back.
+	RefundPayment(context.Context, *connect.Request[v1.RefundPaymentRequest]) (*connect.Response[v1.RefundPaymentResponse], error)
+	// VoidPayment releases the authorization of an order, cancelling it.
+	VoidPayment(context.Context, *connect.Request[v1.VoidPaymentRequest]) (*connect.Response[v1.VoidPaymentResponse], error)
+	// CreateShipment records that some or all of the unshipped books of a paid
+	// order left the warehouse. The order is shipped once every book is, and
+	// partially shipped before. It creates at most one shipment per
+	// Idempotency-Key header.
+	CreateShipment(context.Context, *connect.Request[v1.CreateShipmentRequest]) (*connect.Response[v1.CreateShipmentResponse], error)
+	// DeliverShipment records that a shipment arrived. The order is delivered
+	// once it is shipped and every shipment arrived. Delivering a shipment
+	// again leaves it alone.
+	DeliverShipment(context.Context, *connect.Request[v1.DeliverShipmentRequest]) (*connect.Response[v1.DeliverShipmentResponse], error)
+	// ListBestSellingBooks ranks the books by the quantity sold.
+	ListBestSellingBooks(context.Context, *connect.Request[v1.ListBestSellingBooksRequest]) (*connect.Response[v1.ListBestSellingBooksResponse], error)
+	// ListBestSellingAuthors ranks the authors by the quantity of their books
+	// sold.
+	ListBestSellingAuthors(context.Context, *connect.Request[v1.ListBestSellingAuthorsRequest]) (*connect.Response[v1.ListBestSellingAuthorsResponse], error)
+	// GetRevenue sums the orders per day, week or month.
+	GetRevenue(context.Context, *connect.Request[v1.GetRevenueRequest]) (*connect.Response[v1.GetRevenueResponse], error)
+	// GetOrderStats returns the average order value and how many books the
+	// orders have.
+	GetOrderStats(context.Context, *connect.Request[v1.GetOrderStatsRequest]) (*connect.Response[v1.GetOrderStatsResponse], error)
+	// ExportSalesReport renders one of the reports as CSV.
+	ExportSalesReport(context.Context, *connect.Request[v1.ExportSalesReportRequest]) (*connect.Response[v1.ExportSalesReportResponse], error)
 }
 
 // NewOrdersServiceHandler builds an HTTP handler from the service implementation. It returns the
@@ -164,12 +462,21 @@ func NewOrdersServiceHandler(svc OrdersServiceHandler, opts ...connect.HandlerOp
 		OrdersServiceListOrdersProcedure,
 		svc.ListOrders,
 		connect.WithSchema(ordersServiceListOrdersMethodDescriptor),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
 		connect.WithHandlerOptions(opts...),
 	)
 	ordersServiceGetOrderHandler := connect.NewUnaryHandler(
 		OrdersServiceGetOrderProcedure,
 		svc.GetOrder,
 		connect.WithSchema(ordersServiceGetOrderMethodDescriptor),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	ordersServicePriceOrderHandler := connect.NewUnaryHandler(
+		OrdersServicePriceOrderProcedure,
+		svc.PriceOrder,
+		connect.WithSchema(ordersServicePriceOrderMethodDescriptor),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
 		connect.WithHandlerOptions(opts...),
 	)
 	ordersServiceCreateOrderHandler := connect.NewUnaryHandler(
@@ -190,18 +497,121 @@ func NewOrdersServiceHandler(svc OrdersServiceHandler, opts ...connect.HandlerOp
 		connect.WithSchema(ordersServiceDeleteOrderMethodDescriptor),
 		connect.WithHandlerOptions(opts...),
 	)
+	ordersServiceWatchOrdersHandler := connect.NewServerStreamHandler(
+		OrdersServiceWatchOrdersProcedure,
+		svc.WatchOrders,
+		connect.WithSchema(ordersServiceWatchOrdersMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	ordersServiceAuthorizePaymentHandler := connect.NewUnaryHandler(
+		OrdersServiceAuthorizePaymentProcedure,
+		svc.AuthorizePayment,
+		connect.WithSchema(ordersServiceAuthorizePaymentMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	ordersServiceCapturePaymentHandler := connect.NewUnaryHandler(
+		OrdersServiceCapturePaymentProcedure,
+		svc.CapturePayment,
+		connect.WithSchema(ordersServiceCapturePaymentMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	ordersServiceRefundPaymentHandler := connect.NewUnaryHandler(
+		OrdersServiceRefundPaymentProcedure,
+		svc.RefundPayment,
+		connect.WithSchema(ordersServiceRefundPaymentMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	ordersServiceVoidPaymentHandler := connect.NewUnaryHandler(
+		OrdersServiceVoidPaymentProcedure,
+		svc.VoidPayment,
+		connect.WithSchema(ordersServiceVoidPaymentMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	ordersServiceCreateShipmentHandler := connect.NewUnaryHandler(
+		OrdersServiceCreateShipmentProcedure,
+		svc.CreateShipment,
+		connect.WithSchema(ordersServiceCreateShipmentMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	ordersServiceDeliverShipmentHandler := connect.NewUnaryHandler(
+		OrdersServiceDeliverShipmentProcedure,
+		svc.DeliverShipment,
+		connect.WithSchema(ordersServiceDeliverShipmentMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	ordersServiceListBestSellingBooksHandler := connect.NewUnaryHandler(
+		OrdersServiceListBestSellingBooksProcedure,
+		svc.ListBestSellingBooks,
+		connect.WithSchema(ordersServiceListBestSellingBooksMethodDescriptor),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	ordersServiceListBestSellingAuthorsHandler := connect.NewUnaryHandler(
+		OrdersServiceListBestSellingAuthorsProcedure,
+		svc.ListBestSellingAuthors,
+		connect.WithSchema(ordersServiceListBestSellingAuthorsMethodDescriptor),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	ordersServiceGetRevenueHandler := connect.NewUnaryHandler(
+		OrdersServiceGetRevenueProcedure,
+		svc.GetRevenue,
+		connect.WithSchema(ordersServiceGetRevenueMethodDescriptor),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	ordersServiceGetOrderStatsHandler := connect.NewUnaryHandler(
+		OrdersServiceGetOrderStatsProcedure,
+		svc.GetOrderStats,
+		connect.WithSchema(ordersServiceGetOrderStatsMethodDescriptor),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	ordersServiceExportSalesReportHandler := connect.NewUnaryHandler(
+		OrdersServiceExportSalesReportProcedure,
+		svc.ExportSalesReport,
+		connect.WithSchema(ordersServiceExportSalesReportMethodDescriptor),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
 	return "/orders.v1.OrdersService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case OrdersServiceListOrdersProcedure:
 			ordersServiceListOrdersHandler.ServeHTTP(w, r)
 		case OrdersServiceGetOrderProcedure:
 			ordersServiceGetOrderHandler.ServeHTTP(w, r)
+		case OrdersServicePriceOrderProcedure:
+			ordersServicePriceOrderHandler.ServeHTTP(w, r)
 		case OrdersServiceCreateOrderProcedure:
 			ordersServiceCreateOrderHandler.ServeHTTP(w, r)
 		case OrdersServiceUpdateOrderProcedure:
 			ordersServiceUpdateOrderHandler.ServeHTTP(w, r)
 		case OrdersServiceDeleteOrderProcedure:
 			ordersServiceDeleteOrderHandler.ServeHTTP(w, r)
+		case OrdersServiceWatchOrdersProcedure:
+			ordersServiceWatchOrdersHandler.ServeHTTP(w, r)
+		case OrdersServiceAuthorizePaymentProcedure:
+			ordersServiceAuthorizePaymentHandler.ServeHTTP(w, r)
+		case OrdersServiceCapturePaymentProcedure:
+			ordersServiceCapturePaymentHandler.ServeHTTP(w, r)
+		case OrdersServiceRefundPaymentProcedure:
+			ordersServiceRefundPaymentHandler.ServeHTTP(w, r)
+		case OrdersServiceVoidPaymentProcedure:
+			ordersServiceVoidPaymentHandler.ServeHTTP(w, r)
+		case OrdersServiceCreateShipmentProcedure:
+			ordersServiceCreateShipmentHandler.ServeHTTP(w, r)
+		case OrdersServiceDeliverShipmentProcedure:
+			ordersServiceDeliverShipmentHandler.ServeHTTP(w, r)
+		case OrdersServiceListBestSellingBooksProcedure:
+			ordersServiceListBestSellingBooksHandler.ServeHTTP(w, r)
+		case OrdersServiceListBestSellingAuthorsProcedure:
+			ordersServiceListBestSellingAuthorsHandler.ServeHTTP(w, r)
+		case OrdersServiceGetRevenueProcedure:
+			ordersServiceGetRevenueHandler.ServeHTTP(w, r)
+		case OrdersServiceGetOrderStatsProcedure:
+			ordersServiceGetOrderStatsHandler.ServeHTTP(w, r)
+		case OrdersServiceExportSalesReportProcedure:
+			ordersServiceExportSalesReportHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -219,6 +629,10 @@ func (UnimplementedOrdersServiceHandler) GetOrder(context.Context, *connect.Requ
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("orders.v1.OrdersService.GetOrder is not implemented"))
 }
 
+func (UnimplementedOrdersServiceHandler) PriceOrder(context.Context, *connect.Request[v1.PriceOrderRequest]) (*connect.Response[v1.PriceOrderResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("orders.v1.OrdersService.PriceOrder is not implemented"))
+}
+
 func (UnimplementedOrdersServiceHandler) CreateOrder(context.Context, *connect.Request[v1.CreateOrderRequest]) (*connect.Response[v1.CreateOrderResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("orders.v1.OrdersService.CreateOrder is not implemented"))
 }
@@ -230,3 +644,51 @@ func (UnimplementedOrdersServiceHandler) UpdateOrder(context.Context, *connect.R
 func (UnimplementedOrdersServiceHandler) DeleteOrder(context.Context, *connect.Request[v1.DeleteOrderRequest]) (*connect.Response[v1.DeleteOrderResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("orders.v1.OrdersService.DeleteOrder is not implemented"))
 }
+
+func (UnimplementedOrdersServiceHandler) WatchOrders(context.Context, *connect.Request[v1.WatchOrdersRequest], *connect.ServerStream[v1.WatchOrdersResponse]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("orders.v1.OrdersService.WatchOrders is not implemented"))
+}
+
+func (UnimplementedOrdersServiceHandler) AuthorizePayment(context.Context, *connect.Request[v1.AuthorizePaymentRequest]) (*connect.Response[v1.AuthorizePaymentResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("orders.v1.OrdersService.AuthorizePayment is not implemented"))
+}
+
+func (UnimplementedOrdersServiceHandler) CapturePayment(context.Context, *connect.Request[v1.CapturePaymentRequest]) (*connect.Response[v1.CapturePaymentResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("orders.v1.OrdersService.CapturePayment is not implemented"))
+}
+
+func (UnimplementedOrdersServiceHandler) RefundPayment(context.Context, *connect.Request[v1.RefundPaymentRequest]) (*connect.Response[v1.RefundPaymentResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("orders.v1.OrdersService.RefundPayment is not implemented"))
+}
+
+func (UnimplementedOrdersServiceHandler) VoidPayment(context.Context, *connect.Request[v1.VoidPaymentRequest]) (*connect.Response[v1.VoidPaymentResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("orders.v1.OrdersService.VoidPayment is not implemented"))
+}
+
+func (UnimplementedOrdersServiceHandler) CreateShipment(context.Context, *connect.Request[v1.CreateShipmentRequest]) (*connect.Response[v1.CreateShipmentResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("orders.v1.OrdersService.CreateShipment is not implemented"))
+}
+
+func (UnimplementedOrdersServiceHandler) DeliverShipment(context.Context, *connect.Request[v1.DeliverShipmentRequest]) (*connect.Response[v1.DeliverShipmentResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("orders.v1.OrdersService.DeliverShipment is not implemented"))
+}
+
+func (UnimplementedOrdersServiceHandler) ListBestSellingBooks(context.Context, *connect.Request[v1.ListBestSellingBooksRequest]) (*connect.Response[v1.ListBestSellingBooksResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("orders.v1.OrdersService.ListBestSellingBooks is not implemented"))
+}
+
+func (UnimplementedOrdersServiceHandler) ListBestSellingAuthors(context.Context, *connect.Request[v1.ListBestSellingAuthorsRequest]) (*connect.Response[v1.ListBestSellingAuthorsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("orders.v1.OrdersService.ListBestSellingAuthors is not implemented"))
+}
+
+func (UnimplementedOrdersServiceHandler) GetRevenue(context.Context, *connect.Request[v1.GetRevenueRequest]) (*connect.Response[v1.GetRevenueResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("orders.v1.OrdersService.GetRevenue is not implemented"))
+}
+
+func (UnimplementedOrdersServiceHandler) GetOrderStats(context.Context, *connect.Request[v1.GetOrderStatsRequest]) (*connect.Response[v1.GetOrderStatsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("orders.v1.OrdersService.GetOrderStats is not implemented"))
+}
+
+func (UnimplementedOrdersServiceHandler) ExportSalesReport(context.Context, *connect.Request[v1.ExportSalesReportRequest]) (*connect.Response[v1.ExportSalesReportResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("orders.v1.OrdersService.ExportSalesReport is not implemented"))
+}
@@ -0,0 +1,207 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: promotions/v1/promotions.proto
+
+package promotionsv1connect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	v1 "github.com/iho/bookstore/protos/gen/promotions/v1"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// PromotionsServiceName is the fully-qualified name of the PromotionsService service.
+	PromotionsServiceName = "promotions.v1.PromotionsService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// PromotionsServiceListPromotionsProcedure is the fully-qualified name of the PromotionsService's
+	// ListPromotions RPC.
+	PromotionsServiceListPromotionsProcedure = "/promotions.v1.PromotionsService/ListPromotions"
+	// PromotionsServiceGetPromotionProcedure is the fully-qualified name of the PromotionsService's
+	// GetPromotion RPC.
+	PromotionsServiceGetPromotionProcedure = "/promotions.v1.PromotionsService/GetPromotion"
+	// PromotionsServiceCreatePromotionProcedure is the fully-qualified name of the PromotionsService's
+	// CreatePromotion RPC.
+	PromotionsServiceCreatePromotionProcedure = "/promotions.v1.PromotionsService/CreatePromotion"
+	// PromotionsServiceDeletePromotionProcedure is the fully-qualified name of the PromotionsService's
+	// DeletePromotion RPC.
+	PromotionsServiceDeletePromotionProcedure = "/promotions.v1.PromotionsService/DeletePromotion"
+)
+
+// These variables are the protoreflect.Descriptor objects for the RPCs defined in this package.
+var (
+	promotionsServiceServiceDescriptor               = v1.File_promotions_v1_promotions_proto.Services().ByName("PromotionsService")
+	promotionsServiceListPromotionsMethodDescriptor  = promotionsServiceServiceDescriptor.Methods().ByName("ListPromotions")
+	promotionsServiceGetPromotionMethodDescriptor    = promotionsServiceServiceDescriptor.Methods().ByName("GetPromotion")
+	promotionsServiceCreatePromotionMethodDescriptor = promotionsServiceServiceDescriptor.Methods().ByName("CreatePromotion")
+	promotionsServiceDeletePromotionMethodDescriptor = promotionsServiceServiceDescriptor.Methods().ByName("DeletePromotion")
+)
+
+// PromotionsServiceClient is a client for the promotions.v1.PromotionsService service.
+type PromotionsServiceClient interface {
+	ListPromotions(context.Context, *connect.Request[v1.ListPromotionsRequest]) (*connect.Response[v1.ListPromotionsResponse], error)
+	GetPromotion(context.Context, *connect.Request[v1.GetPromotionRequest]) (*connect.Response[v1.GetPromotionResponse], error)
+	CreatePromotion(context.Context, *connect.Request[v1.CreatePromotionRequest]) (*connect.Response[v1.CreatePromotionResponse], error)
+	DeletePromotion(context.Context, *connect.Request[v1.DeletePromotionRequest]) (*connect.Response[v1.DeletePromotionResponse], error)
+}
+
+// NewPromotionsServiceClient constructs a client for the promotions.v1.PromotionsService service.
+// By default, it uses the Connect protocol with the binary Protobuf Codec, asks for gzipped
+// responses, and sends uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the
+// connect.WithGRPC() or connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewPromotionsServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) PromotionsServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	return &promotionsServiceClient{
+		listPromotions: connect.NewClient[v1.ListPromotionsRequest, v1.ListPromotionsResponse](
+			httpClient,
+			baseURL+PromotionsServiceListPromotionsProcedure,
+			connect.WithSchema(promotionsServiceListPromotionsMethodDescriptor),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		getPromotion: connect.NewClient[v1.GetPromotionRequest, v1.GetPromotionResponse](
+			httpClient,
+			baseURL+PromotionsServiceGetPromotionProcedure,
+			connect.WithSchema(promotionsServiceGetPromotionMethodDescriptor),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		createPromotion: connect.NewClient[v1.CreatePromotionRequest, v1.CreatePromotionResponse](
+			httpClient,
+			baseURL+PromotionsServiceCreatePromotionProcedure,
+			connect.WithSchema(promotionsServiceCreatePromotionMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		deletePromotion: connect.NewClient[v1.DeletePromotionRequest, v1.DeletePromotionResponse](
+			httpClient,
+			baseURL+PromotionsServiceDeletePromotionProcedure,
+			connect.WithSchema(promotionsServiceDeletePromotionMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// promotionsServiceClient implements PromotionsServiceClient.
+type promotionsServiceClient struct {
+	listPromotions  *connect.Client[v1.ListPromotionsRequest, v1.ListPromotionsResponse]
+	getPromotion    *connect.Client[v1.GetPromotionRequest, v1.GetPromotionResponse]
+	createPromotion *connect.Client[v1.CreatePromotionRequest, v1.CreatePromotionResponse]
+	deletePromotion *connect.Client[v1.DeletePromotionRequest, v1.DeletePromotionResponse]
+}
+
+// ListPromotions calls promotions.v1.PromotionsService.ListPromotions.
+func (c *promotionsServiceClient) ListPromotions(ctx context.Context, req *connect.Request[v1.ListPromotionsRequest]) (*connect.Response[v1.ListPromotionsResponse], error) {
+	return c.listPromotions.CallUnary(ctx, req)
+}
+
+// GetPromotion calls promotions.v1.PromotionsService.GetPromotion.
+func (c *promotionsServiceClient) GetPromotion(ctx context.Context, req *connect.Request[v1.GetPromotionRequest]) (*connect.Response[v1.GetPromotionResponse], error) {
+	return c.getPromotion.CallUnary(ctx, req)
+}
+
+// CreatePromotion calls promotions.v1.PromotionsService.CreatePromotion.
+func (c *promotionsServiceClient) CreatePromotion(ctx context.Context, req *connect.Request[v1.CreatePromotionRequest]) (*connect.Response[v1.CreatePromotionResponse], error) {
+	return c.createPromotion.CallUnary(ctx, req)
+}
+
+// DeletePromotion calls promotions.v1.PromotionsService.DeletePromotion.
+func (c *promotionsServiceClient) DeletePromotion(ctx context.Context, req *connect.Request[v1.DeletePromotionRequest]) (*connect.Response[v1.DeletePromotionResponse], error) {
+	return c.deletePromotion.CallUnary(ctx, req)
+}
+
+// PromotionsServiceHandler is an implementation of the promotions.v1.PromotionsService service.
+type PromotionsServiceHandler interface {
+	ListPromotions(context.Context, *connect.Request[v1.ListPromotionsRequest]) (*connect.Response[v1.ListPromotionsResponse], error)
+	GetPromotion(context.Context, *connect.Request[v1.GetPromotionRequest]) (*connect.Response[v1.GetPromotionResponse], error)
+	CreatePromotion(context.Context, *connect.Request[v1.CreatePromotionRequest]) (*connect.Response[v1.CreatePromotionResponse], error)
+	DeletePromotion(context.Context, *connect.Request[v1.DeletePromotionRequest]) (*connect.Response[v1.DeletePromotionResponse], error)
+}
+
+// NewPromotionsServiceHandler builds an HTTP handler from the service implementation. It returns
+// the path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewPromotionsServiceHandler(svc PromotionsServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	promotionsServiceListPromotionsHandler := connect.NewUnaryHandler(
+		PromotionsServiceListPromotionsProcedure,
+		svc.ListPromotions,
+		connect.WithSchema(promotionsServiceListPromotionsMethodDescriptor),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	promotionsServiceGetPromotionHandler := connect.NewUnaryHandler(
+		PromotionsServiceGetPromotionProcedure,
+		svc.GetPromotion,
+		connect.WithSchema(promotionsServiceGetPromotionMethodDescriptor),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	promotionsServiceCreatePromotionHandler := connect.NewUnaryHandler(
+		PromotionsServiceCreatePromotionProcedure,
+		svc.CreatePromotion,
+		connect.WithSchema(promotionsServiceCreatePromotionMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	promotionsServiceDeletePromotionHandler := connect.NewUnaryHandler(
+		PromotionsServiceDeletePromotionProcedure,
+		svc.DeletePromotion,
+		connect.WithSchema(promotionsServiceDeletePromotionMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/promotions.v1.PromotionsService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case PromotionsServiceListPromotionsProcedure:
+			promotionsServiceListPromotionsHandler.ServeHTTP(w, r)
+		case PromotionsServiceGetPromotionProcedure:
+			promotionsServiceGetPromotionHandler.ServeHTTP(w, r)
+		case PromotionsServiceCreatePromotionProcedure:
+			promotionsServiceCreatePromotionHandler.ServeHTTP(w, r)
+		case PromotionsServiceDeletePromotionProcedure:
+			promotionsServiceDeletePromotionHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedPromotionsServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedPromotionsServiceHandler struct{}
+
+func (UnimplementedPromotionsServiceHandler) ListPromotions(context.Context, *connect.Request[v1.ListPromotionsRequest]) (*connect.Response[v1.ListPromotionsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("promotions.v1.PromotionsService.ListPromotions is not implemented"))
+}
+
+func (UnimplementedPromotionsServiceHandler) GetPromotion(context.Context, *connect.Request[v1.GetPromotionRequest]) (*connect.Response[v1.GetPromotionResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("promotions.v1.PromotionsService.GetPromotion is not implemented"))
+}
+
+func (UnimplementedPromotionsServiceHandler) CreatePromotion(context.Context, *connect.Request[v1.CreatePromotionRequest]) (*connect.Response[v1.CreatePromotionResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("promotions.v1.PromotionsService.CreatePromotion is not implemented"))
+}
+
+func (UnimplementedPromotionsServiceHandler) DeletePromotion(context.Context, *connect.Request[v1.DeletePromotionRequest]) (*connect.Response[v1.DeletePromotionResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("promotions.v1.PromotionsService.DeletePromotion is not implemented"))
+}